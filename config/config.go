@@ -19,6 +19,9 @@ type Config struct {
 	S3Region           string
 	AwsAccessKeyId     string
 	AwsSecretAccessKey string
+
+	CertificationReminderDays     string
+	CertificationReminderInterval string
 }
 
 func LoadConfig() *Config {
@@ -39,6 +42,9 @@ func LoadConfig() *Config {
 		S3Region:           getEnv("AWS_REGION", ""),
 		AwsAccessKeyId:     getEnv("AWS_ACCESS_KEY_ID", ""),
 		AwsSecretAccessKey: getEnv("AWS_SECRET_ACCESS_KEY", ""),
+
+		CertificationReminderDays:     getEnv("CERTIFICATION_REMINDER_DAYS", "30"),
+		CertificationReminderInterval: getEnv("CERTIFICATION_REMINDER_INTERVAL", "1h"),
 	}
 }
 
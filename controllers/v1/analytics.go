@@ -0,0 +1,208 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"go-go-manager/models"
+	"go-go-manager/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxAnalyticsMonths bounds the range of the monthly series.
+const maxAnalyticsMonths = 60
+
+// analyticsRangeFromQuery reads the from and to dates of the analytics
+// endpoints. The range ends today and covers the last twelve months unless
+// given.
+func analyticsRangeFromQuery(c *gin.Context) (time.Time, time.Time, error) {
+	today, _ := time.Parse(time.DateOnly, time.Now().Format(time.DateOnly))
+
+	to := today
+	if toStr := c.Query("to"); toStr != "" {
+		var err error
+		to, err = time.Parse(time.DateOnly, toStr)
+		if err != nil || to.After(today) {
+			return time.Time{}, time.Time{}, errors.New("to must be a date (YYYY-MM-DD) no later than today")
+		}
+	}
+
+	from := time.Date(to.Year(), to.Month()-11, 1, 0, 0, 0, 0, time.UTC)
+	if fromStr := c.Query("from"); fromStr != "" {
+		var err error
+		from, err = time.Parse(time.DateOnly, fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("from must be a date (YYYY-MM-DD)")
+		}
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, errors.New("from cannot be after to")
+	}
+	if len(analyticsMonths(from, to)) > maxAnalyticsMonths {
+		return time.Time{}, time.Time{}, fmt.Errorf("the range cannot exceed %d months", maxAnalyticsMonths)
+	}
+
+	return from, to, nil
+}
+
+// analyticsMonths lists the months from the one holding from to the one
+// holding to as YYYY-MM.
+func analyticsMonths(from time.Time, to time.Time) []string {
+	months := []string{}
+	for month := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC); !month.After(to); month = month.AddDate(0, 1, 0) {
+		months = append(months, month.Format("2006-01"))
+	}
+	return months
+}
+
+func (h *EmployeeHandler) GetHeadcountAnalytics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		from, to, err := analyticsRangeFromQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if c.Query("asOf") != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "asOf is not supported here; the headcount is taken on to"})
+			return
+		}
+		filters, err := employeeFiltersFromQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		filters["userId"] = strconv.Itoa(int(v.UserID))
+
+		// Hires and leavers are counted whatever the employee's status is now,
+		// including employees deleted since
+		flowFilters := map[string]string{"withDeleted": "true"}
+		for key, value := range filters {
+			if key != "status" {
+				flowFilters[key] = value
+			}
+		}
+
+		// The headcount is taken at the end of to, from the employee history
+		// when that is in the past
+		if to.Format(time.DateOnly) < time.Now().Format(time.DateOnly) {
+			filters["asOf"] = to.AddDate(0, 0, 1).Format(time.RFC3339Nano)
+			filters["asOfDate"] = to.Format(time.DateOnly)
+		}
+
+		departments, err := h.Repo.GetDepartmentHeadcounts(filters)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute headcount"})
+			return
+		}
+		tenure, err := h.Repo.GetTenureCounts(filters, to.Format(time.DateOnly))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute tenure"})
+			return
+		}
+		flows, err := h.Repo.GetMonthlyFlows(flowFilters, from.Format(time.DateOnly), to.Format(time.DateOnly))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute hires and leavers"})
+			return
+		}
+
+		report := models.HeadcountReport{
+			From:         from.Format(time.DateOnly),
+			To:           to.Format(time.DateOnly),
+			Departments:  departments,
+			Tenure:       []models.AnalyticsPoint{},
+			MonthlyFlows: []models.MonthlyFlow{},
+		}
+
+		var male, female int
+		for _, department := range departments {
+			report.Total += department.Headcount
+			male += department.Male
+			female += department.Female
+		}
+		report.Gender = []models.AnalyticsPoint{
+			{Label: string(models.Male), Count: male},
+			{Label: string(models.Female), Count: female},
+		}
+
+		for _, bucket := range models.TenureBuckets {
+			report.Tenure = append(report.Tenure, models.AnalyticsPoint{Label: bucket, Count: tenure[bucket]})
+		}
+
+		// Months without any movement are listed with zero counts
+		for _, key := range analyticsMonths(from, to) {
+			flow, ok := flows[key]
+			if !ok {
+				flow = models.MonthlyFlow{Month: key}
+			}
+			report.MonthlyFlows = append(report.MonthlyFlows, flow)
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
+
+func (h *EmployeeHandler) GetTurnoverAnalytics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		from, to, err := analyticsRangeFromQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Status is what is being measured, so only department and gender filter.
+		// Deleted employees count too, or leavers removed through DELETE
+		// /employee would vanish from the months they left in.
+		filters := map[string]string{"userId": strconv.Itoa(int(v.UserID)), "withDeleted": "true"}
+		if departmentID := c.Query("departmentId"); departmentID != "" {
+			filters["departmentId"] = departmentID
+		}
+		if gender := c.Query("gender"); gender != "" {
+			if gender != string(models.Male) && gender != string(models.Female) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid gender value"})
+				return
+			}
+			filters["gender"] = gender
+		}
+
+		counts, err := h.Repo.GetTurnoverCounts(filters, from.Format(time.DateOnly), to.Format(time.DateOnly))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute turnover"})
+			return
+		}
+
+		c.JSON(http.StatusOK, models.NewTurnoverReport(from.Format(time.DateOnly), to.Format(time.DateOnly), analyticsMonths(from, to), counts))
+	}
+}
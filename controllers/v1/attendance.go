@@ -0,0 +1,700 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"go-go-manager/models"
+	"go-go-manager/repositories"
+	"go-go-manager/utils"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxTimesheetDays bounds the period of a timesheet, approval or export.
+const maxTimesheetDays = 93
+
+type ClockRequest struct {
+	Note     *string `json:"note" binding:"omitempty,max=255"`
+	Location *string `json:"location" binding:"omitempty,max=255"`
+	At       *string `json:"at" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00"` // Defaults to now, set for corrections
+}
+
+type TimesheetApprovalRequest struct {
+	From string  `json:"from" binding:"required,datetime=2006-01-02"`
+	To   string  `json:"to" binding:"required,datetime=2006-01-02"`
+	Note *string `json:"note" binding:"omitempty,max=255"`
+}
+
+var timesheetExportColumns = []string{"identityNumber", "name", "departmentId", "from", "to", "workedHours", "expectedHours", "overtimeHours", "missingPunches", "approvedBy"}
+
+type TimesheetExportRow struct {
+	IdentityNumber string  `json:"identityNumber"`
+	Name           string  `json:"name"`
+	DepartmentID   string  `json:"departmentId"`
+	From           string  `json:"from"`
+	To             string  `json:"to"`
+	WorkedHours    float64 `json:"workedHours"`
+	ExpectedHours  float64 `json:"expectedHours"`
+	OvertimeHours  float64 `json:"overtimeHours"`
+	MissingPunches int     `json:"missingPunches"`
+	ApprovedBy     string  `json:"approvedBy"` // Empty unless one approval covers the whole period
+}
+
+// localToday is the current date in loc, as a UTC midnight like parsed dates.
+func localToday(loc *time.Location) time.Time {
+	date, _ := time.Parse(time.DateOnly, time.Now().In(loc).Format(time.DateOnly))
+	return date
+}
+
+// timesheetPeriod reads the from and to dates, defaulting to the current week
+// from Monday to Sunday in the schedule's timezone.
+func timesheetPeriod(fromStr string, toStr string, loc *time.Location) (time.Time, time.Time, error) {
+	now := localToday(loc)
+	from := now.AddDate(0, 0, -((int(now.Weekday()) + 6) % 7))
+	to := from.AddDate(0, 0, 6)
+
+	var err error
+	if fromStr != "" {
+		if from, err = time.Parse(time.DateOnly, fromStr); err != nil {
+			return from, to, errors.New("from must be a date (YYYY-MM-DD)")
+		}
+	}
+	if toStr != "" {
+		if to, err = time.Parse(time.DateOnly, toStr); err != nil {
+			return from, to, errors.New("to must be a date (YYYY-MM-DD)")
+		}
+	}
+	if to.Before(from) {
+		return from, to, errors.New("to cannot be before from")
+	}
+	if to.Sub(from).Hours()/24 >= maxTimesheetDays {
+		return from, to, fmt.Errorf("The period covers at most %d days", maxTimesheetDays)
+	}
+	return from, to, nil
+}
+
+// eventWindow is the span of punches needed to build a timesheet for the
+// period; it reaches a day beyond each end so shifts crossing midnight pair up.
+func eventWindow(from time.Time, to time.Time, loc *time.Location) (time.Time, time.Time) {
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, -1)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 2)
+	return start, end
+}
+
+// Clock records a clock-in or clock-out. A punch may be back-dated to correct
+// the record as long as it alternates with the punches around it, and cannot
+// fall in a period whose timesheet is approved.
+func (h *EmployeeHandler) Clock(eventType models.AttendanceEventType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		identityNumber := c.Param("identityNumber")
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(identityNumber)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		// The body is optional
+		var req ClockRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		transition, err := h.Repo.GetLatestTransition(identityNumber)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch employee status"})
+			return
+		}
+		if transition != nil && transition.ToStatus == models.StatusTerminated {
+			c.JSON(http.StatusConflict, gin.H{"error": "Terminated employees cannot clock in or out"})
+			return
+		}
+
+		now := time.Now()
+		occurredAt := now
+		if req.At != nil {
+			occurredAt, _ = time.Parse(time.RFC3339, *req.At)
+			if occurredAt.After(now) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "at cannot be in the future"})
+				return
+			}
+		}
+
+		previous, next, err := h.Repo.GetAdjacentAttendanceEvents(identityNumber, occurredAt)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch attendance"})
+			return
+		}
+		if eventType == models.ClockIn && previous != nil && previous.Type == models.ClockIn {
+			c.JSON(http.StatusConflict, gin.H{"error": "Employee is already clocked in at " + occurredAt.Format(time.RFC3339)})
+			return
+		}
+		if eventType == models.ClockOut && (previous == nil || previous.Type == models.ClockOut) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Employee is not clocked in at " + occurredAt.Format(time.RFC3339)})
+			return
+		}
+		if next != nil && next.Type == eventType {
+			c.JSON(http.StatusConflict, gin.H{"error": "The next punch at " + next.OccurredAt.Format(time.RFC3339) + " is of the same type"})
+			return
+		}
+
+		schedule, err := models.GetWorkSchedule(v.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch work schedule"})
+			return
+		}
+		loc, err := schedule.Location()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid work schedule timezone"})
+			return
+		}
+
+		approved, err := h.Repo.IsTimesheetApproved(identityNumber, occurredAt.In(loc).Format(time.DateOnly))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch timesheet approvals"})
+			return
+		}
+		if approved {
+			c.JSON(http.StatusConflict, gin.H{"error": "The timesheet for this day is already approved"})
+			return
+		}
+
+		event, err := h.Repo.AddAttendanceEvent(models.AttendanceEvent{
+			IdentityNumber: identityNumber,
+			Type:           eventType,
+			OccurredAt:     occurredAt,
+			Note:           req.Note,
+			Location:       req.Location,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record attendance", "details": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusCreated, event)
+	}
+}
+
+// DeleteAttendanceEvent removes a wrong punch, unless its day is in an
+// approved timesheet.
+func (h *EmployeeHandler) DeleteAttendanceEvent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		identityNumber := c.Param("identityNumber")
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(identityNumber)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		eventID, err := strconv.Atoi(c.Param("eventId"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Punch not found"})
+			return
+		}
+		event, err := h.Repo.GetAttendanceEvent(identityNumber, eventID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch attendance"})
+			return
+		}
+		if event == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Punch not found"})
+			return
+		}
+
+		schedule, err := models.GetWorkSchedule(v.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch work schedule"})
+			return
+		}
+		loc, err := schedule.Location()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid work schedule timezone"})
+			return
+		}
+
+		approved, err := h.Repo.IsTimesheetApproved(identityNumber, event.OccurredAt.In(loc).Format(time.DateOnly))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch timesheet approvals"})
+			return
+		}
+		if approved {
+			c.JSON(http.StatusConflict, gin.H{"error": "The timesheet for this day is already approved"})
+			return
+		}
+
+		if err := h.Repo.DeleteAttendanceEvent(eventID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete punch"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Punch deleted"})
+	}
+}
+
+func (h *EmployeeHandler) GetTimesheet() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		identityNumber := c.Param("identityNumber")
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(identityNumber)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		sheet, status, err := h.buildTimesheet(v.UserID, identityNumber, c.Query("from"), c.Query("to"))
+		if err != nil {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, sheet)
+	}
+}
+
+// buildTimesheet builds the employee's timesheet for the period from fromStr
+// to toStr. It returns the status code to respond with when that fails.
+func (h *EmployeeHandler) buildTimesheet(userID uint, identityNumber string, fromStr string, toStr string) (models.Timesheet, int, error) {
+	schedule, err := models.GetWorkSchedule(userID)
+	if err != nil {
+		return models.Timesheet{}, http.StatusInternalServerError, errors.New("Failed to fetch work schedule")
+	}
+	loc, err := schedule.Location()
+	if err != nil {
+		return models.Timesheet{}, http.StatusInternalServerError, errors.New("Invalid work schedule timezone")
+	}
+
+	from, to, err := timesheetPeriod(fromStr, toStr, loc)
+	if err != nil {
+		return models.Timesheet{}, http.StatusBadRequest, err
+	}
+
+	start, end := eventWindow(from, to, loc)
+	events, err := h.Repo.GetAttendanceEvents(userID, identityNumber, start, end)
+	if err != nil {
+		return models.Timesheet{}, http.StatusInternalServerError, errors.New("Failed to fetch attendance")
+	}
+
+	approvals, err := h.Repo.GetTimesheetApprovals(userID, identityNumber, from.Format(time.DateOnly), to.Format(time.DateOnly))
+	if err != nil {
+		return models.Timesheet{}, http.StatusInternalServerError, errors.New("Failed to fetch timesheet approvals")
+	}
+
+	sheet := models.BuildTimesheet(identityNumber, events[identityNumber], schedule, loc, from, to, time.Now())
+	if approvals[identityNumber] != nil {
+		sheet.Approvals = approvals[identityNumber]
+	}
+	return sheet, http.StatusOK, nil
+}
+
+func (h *EmployeeHandler) ApproveTimesheet() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		if c.GetHeader("Content-Type") != "application/json" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing content-type"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		identityNumber := c.Param("identityNumber")
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(identityNumber)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		var req TimesheetApprovalRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		sheet, status, err := h.buildTimesheet(v.UserID, identityNumber, req.From, req.To)
+		if err != nil {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		schedule, err := models.GetWorkSchedule(v.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch work schedule"})
+			return
+		}
+		loc, err := schedule.Location()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid work schedule timezone"})
+			return
+		}
+
+		to, _ := time.Parse(time.DateOnly, req.To)
+		if !to.Before(localToday(loc)) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Only periods that have ended can be approved"})
+			return
+		}
+
+		if sheet.MissingPunches > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Resolve the missing punches before approving", "timesheet": sheet})
+			return
+		}
+
+		approval, err := h.Repo.ApproveTimesheet(models.TimesheetApproval{
+			IdentityNumber: identityNumber,
+			PeriodStart:    req.From,
+			PeriodEnd:      req.To,
+			WorkedHours:    sheet.WorkedHours,
+			OvertimeHours:  sheet.OvertimeHours,
+			ApprovedBy:     v.Email,
+			Note:           req.Note,
+		})
+		if errors.Is(err, repositories.ErrTimesheetOverlap) {
+			c.JSON(http.StatusConflict, gin.H{"error": "The period overlaps an approved timesheet"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to approve timesheet", "details": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusCreated, approval)
+	}
+}
+
+func (h *EmployeeHandler) GetMissingPunches() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		schedule, err := models.GetWorkSchedule(v.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch work schedule"})
+			return
+		}
+		loc, err := schedule.Location()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid work schedule timezone"})
+			return
+		}
+
+		from, to, err := timesheetPeriod(c.Query("from"), c.Query("to"), loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		start, end := eventWindow(from, to, loc)
+		events, err := h.Repo.GetAttendanceEvents(v.UserID, "", start, end)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch attendance"})
+			return
+		}
+
+		filters := map[string]string{"userId": strconv.Itoa(int(v.UserID)), "status": "all"}
+		if departmentID := c.Query("departmentId"); departmentID != "" {
+			filters["departmentId"] = departmentID
+		}
+
+		now := time.Now()
+		missing := []models.MissingPunch{}
+		err = h.Repo.StreamEmployees(c.Request.Context(), filters, func(row models.EmployeeExportRow) error {
+			if events[row.IdentityNumber] == nil {
+				return nil
+			}
+			sheet := models.BuildTimesheet(row.IdentityNumber, events[row.IdentityNumber], schedule, loc, from, to, now)
+			for _, day := range sheet.Days {
+				for _, issue := range day.MissingPunches {
+					missing = append(missing, models.MissingPunch{
+						IdentityNumber: row.IdentityNumber,
+						Name:           row.Name,
+						DepartmentID:   row.DepartmentID,
+						Date:           day.Date,
+						Issue:          issue,
+					})
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch employees"})
+			return
+		}
+
+		c.JSON(http.StatusOK, missing)
+	}
+}
+
+// ExportTimesheets writes one row per employee with the totals for a pay
+// period. Current employees are always listed; former ones only when they
+// punched in the period.
+func (h *EmployeeHandler) ExportTimesheets() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		schedule, err := models.GetWorkSchedule(v.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch work schedule"})
+			return
+		}
+		loc, err := schedule.Location()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid work schedule timezone"})
+			return
+		}
+
+		if c.Query("from") == "" || c.Query("to") == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from and to are required"})
+			return
+		}
+		from, to, err := timesheetPeriod(c.Query("from"), c.Query("to"), loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		start, end := eventWindow(from, to, loc)
+		events, err := h.Repo.GetAttendanceEvents(v.UserID, "", start, end)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch attendance"})
+			return
+		}
+
+		approvals, err := h.Repo.GetTimesheetApprovals(v.UserID, "", from.Format(time.DateOnly), to.Format(time.DateOnly))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch timesheet approvals"})
+			return
+		}
+
+		filters := map[string]string{"userId": strconv.Itoa(int(v.UserID)), "status": "all"}
+		if departmentID := c.Query("departmentId"); departmentID != "" {
+			filters["departmentId"] = departmentID
+		}
+
+		writer, err := startExport(c, "timesheets", timesheetExportColumns)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		now := time.Now()
+		err = h.Repo.StreamEmployees(c.Request.Context(), filters, func(employee models.EmployeeExportRow) error {
+			current := employee.Status == models.StatusActive || employee.Status == models.StatusOnLeave
+			if !current && events[employee.IdentityNumber] == nil {
+				return nil
+			}
+
+			sheet := models.BuildTimesheet(employee.IdentityNumber, events[employee.IdentityNumber], schedule, loc, from, to, now)
+			row := TimesheetExportRow{
+				IdentityNumber: employee.IdentityNumber,
+				Name:           employee.Name,
+				DepartmentID:   employee.DepartmentID,
+				From:           sheet.From,
+				To:             sheet.To,
+				WorkedHours:    sheet.WorkedHours,
+				OvertimeHours:  sheet.OvertimeHours,
+				MissingPunches: sheet.MissingPunches,
+			}
+			for _, week := range sheet.Weeks {
+				row.ExpectedHours += week.ExpectedHours
+			}
+			for _, approval := range approvals[employee.IdentityNumber] {
+				if approval.PeriodStart <= sheet.From && approval.PeriodEnd >= sheet.To {
+					row.ApprovedBy = approval.ApprovedBy
+				}
+			}
+
+			return writer.Write(row, []string{
+				row.IdentityNumber,
+				row.Name,
+				row.DepartmentID,
+				row.From,
+				row.To,
+				strconv.FormatFloat(row.WorkedHours, 'f', 2, 64),
+				strconv.FormatFloat(row.ExpectedHours, 'f', 2, 64),
+				strconv.FormatFloat(row.OvertimeHours, 'f', 2, 64),
+				strconv.Itoa(row.MissingPunches),
+				row.ApprovedBy,
+			})
+		})
+		if err == nil {
+			err = writer.Close()
+		}
+		if err != nil {
+			// Headers are already sent, so the client only sees a truncated file
+			log.Printf("Failed to export timesheets: %v", err)
+			c.Abort()
+		}
+	}
+}
+
+func GetWorkSchedule(c *gin.Context) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+
+	if !strings.HasPrefix(auth, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		return
+	}
+
+	auth = auth[7:]
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule, err := models.GetWorkSchedule(v.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch work schedule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+func UpdateWorkSchedule(c *gin.Context) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+
+	if !strings.HasPrefix(auth, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		return
+	}
+
+	if c.GetHeader("Content-Type") != "application/json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing content-type"})
+		return
+	}
+
+	auth = auth[7:]
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req models.WorkSchedule
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+		return
+	}
+
+	if _, err := req.Location(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown timezone"})
+		return
+	}
+
+	schedule, err := models.SaveWorkSchedule(v.UserID, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save work schedule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
@@ -38,7 +38,7 @@ func AuthHandler(c *gin.Context) {
 			return
 		}
 
-		token, err := utils.GenerateJWT(user.ID, user.Email)
+		token, err := utils.GenerateJWT(user.Tenant(), user.ID, user.Email)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 			return
@@ -64,7 +64,7 @@ func AuthHandler(c *gin.Context) {
 				return
 			}
 
-			token, err := utils.GenerateJWT(user.ID, user.Email)
+			token, err := utils.GenerateJWT(user.ID, user.ID, user.Email)
 			if err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 				return
@@ -0,0 +1,333 @@
+package v1
+
+import (
+	"go-go-manager/models"
+	"go-go-manager/repositories"
+	"go-go-manager/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+type ChecklistTaskUpdateRequest struct {
+	Assignee  *string `json:"assignee" binding:"omitempty,min=1,max=100"`
+	DueDate   *string `json:"dueDate" binding:"omitempty,datetime=2006-01-02"`
+	Completed *bool   `json:"completed"`
+}
+
+// startOffboarding starts the employee's offboarding checklists unless some
+// are still open, e.g. from a termination recorded before the deletion.
+func startOffboarding(repo *repositories.EmployeeRepository, userID uint, employee models.Employee, date time.Time) error {
+	open, err := repo.HasOpenChecklist(employee.IdentityNumber, models.Offboarding)
+	if err != nil || open {
+		return err
+	}
+	_, err = repo.StartChecklists(userID, models.Offboarding, employee, date)
+	return err
+}
+
+func CreateChecklistTemplate(c *gin.Context) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+
+	if !strings.HasPrefix(auth, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		return
+	}
+
+	if c.GetHeader("Content-Type") != "application/json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing content-type"})
+		return
+	}
+
+	auth = auth[7:]
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Templates are active unless created otherwise
+	req := models.ChecklistTemplate{Active: true}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+		return
+	}
+
+	if req.DepartmentID != nil {
+		if _, err := models.FindDepartmentById(v.UserID, *req.DepartmentID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Department ID"})
+			return
+		}
+	}
+
+	if _, err := models.FindChecklistTemplateByName(v.UserID, req.Name); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Checklist template already exists"})
+		return
+	}
+
+	template, err := models.CreateChecklistTemplate(v.UserID, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create checklist template"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+func GetChecklistTemplates(c *gin.Context) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+
+	if !strings.HasPrefix(auth, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		return
+	}
+
+	auth = auth[7:]
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	templates, err := models.GetChecklistTemplates(v.UserID, c.Query("kind"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch checklist templates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+func UpdateChecklistTemplate(c *gin.Context) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+
+	if !strings.HasPrefix(auth, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		return
+	}
+
+	auth = auth[7:]
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	existing, err := models.FindChecklistTemplateById(v.UserID, c.Param("templateId"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Checklist template not found"})
+		return
+	}
+
+	var updated models.ChecklistTemplate
+	if status, err := applyPatch(c, existing, &updated); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	updated.ID = existing.ID
+
+	if err := binding.Validator.ValidateStruct(&updated); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+		return
+	}
+
+	if updated.DepartmentID != nil {
+		if _, err := models.FindDepartmentById(v.UserID, *updated.DepartmentID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Department ID"})
+			return
+		}
+	}
+
+	if other, err := models.FindChecklistTemplateByName(v.UserID, updated.Name); err == nil && other.ID != existing.ID {
+		c.JSON(http.StatusConflict, gin.H{"error": "Checklist template already exists"})
+		return
+	}
+
+	template, err := models.UpdateChecklistTemplate(v.UserID, updated)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update checklist template"})
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+func (h *EmployeeHandler) GetChecklists() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		filters := map[string]string{"userId": strconv.Itoa(int(v.UserID))}
+		if identityNumber := c.Query("identityNumber"); identityNumber != "" {
+			filters["identityNumber"] = identityNumber
+		}
+		if kind := c.Query("kind"); kind != "" {
+			if kind != string(models.Onboarding) && kind != string(models.Offboarding) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "kind must be onboarding or offboarding"})
+				return
+			}
+			filters["kind"] = kind
+		}
+		if status := c.Query("status"); status != "" {
+			if status != "open" && status != "completed" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "status must be open or completed"})
+				return
+			}
+			filters["status"] = status
+		}
+
+		checklists, err := h.Repo.GetChecklists(filters)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch checklists"})
+			return
+		}
+
+		c.JSON(http.StatusOK, checklists)
+	}
+}
+
+func (h *EmployeeHandler) GetChecklist() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		checklistID, err := strconv.Atoi(c.Param("checklistId"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Checklist not found"})
+			return
+		}
+
+		checklist, err := h.Repo.GetChecklist(v.UserID, checklistID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Checklist not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, checklist)
+	}
+}
+
+func (h *EmployeeHandler) UpdateChecklistTask() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		var req ChecklistTaskUpdateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+
+		taskID, err := strconv.Atoi(c.Param("taskId"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+			return
+		}
+		task, err := h.Repo.GetChecklistTask(v.UserID, taskID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+			return
+		}
+
+		if req.Assignee != nil {
+			task.Assignee = *req.Assignee
+		}
+		if req.DueDate != nil {
+			task.DueDate = *req.DueDate
+		}
+		if req.Completed != nil {
+			switch {
+			case *req.Completed && task.CompletedAt == nil:
+				now := time.Now().UTC().Format(time.RFC3339)
+				task.CompletedAt = &now
+				task.CompletedBy = &v.Email
+			case !*req.Completed:
+				task.CompletedAt = nil
+				task.CompletedBy = nil
+			}
+		}
+
+		updated, err := h.Repo.UpdateChecklistTask(task)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update task"})
+			return
+		}
+
+		c.JSON(http.StatusOK, updated)
+	}
+}
+
+func (h *EmployeeHandler) GetOverdueTasks() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		tasks, err := h.Repo.GetOverdueTasks(v.UserID, c.Query("assignee"), time.Now().Format(time.DateOnly))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch overdue tasks"})
+			return
+		}
+
+		c.JSON(http.StatusOK, tasks)
+	}
+}
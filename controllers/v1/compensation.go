@@ -0,0 +1,336 @@
+package v1
+
+import (
+	"database/sql"
+	"errors"
+	"go-go-manager/models"
+	"go-go-manager/repositories"
+	"go-go-manager/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// compensationRoles may read and change pay.
+var compensationRoles = []models.Role{models.RoleAdmin, models.RoleHR}
+
+func (h *EmployeeHandler) GetCompensations() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		if !requireRole(c, v, compensationRoles...) {
+			return
+		}
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(c.Param("identityNumber"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		records, err := h.Repo.GetCompensations(employee.IdentityNumber)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch compensation"})
+			return
+		}
+
+		// Records are sorted latest first, so the first one that has taken
+		// effect is the one in force
+		today := time.Now().Format(time.DateOnly)
+		var current *models.Compensation
+		for i := range records {
+			if records[i].EffectiveDate <= today {
+				current = &records[i]
+				break
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"current": current,
+			"records": records,
+		})
+	}
+}
+
+func (h *EmployeeHandler) AddCompensation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		if !requireRole(c, v, compensationRoles...) {
+			return
+		}
+
+		var req models.Compensation
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+		if req.Allowances == nil {
+			req.Allowances = []models.Allowance{}
+		}
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(c.Param("identityNumber"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		req.IdentityNumber = employee.IdentityNumber
+		record, err := h.Repo.AddCompensation(req, v.Email)
+		if errors.Is(err, repositories.ErrCompensationExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save compensation"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, record)
+	}
+}
+
+func (h *EmployeeHandler) UpdateCompensation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		if !requireRole(c, v, compensationRoles...) {
+			return
+		}
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(c.Param("identityNumber"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		id, err := strconv.Atoi(c.Param("compensationId"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "compensation not found"})
+			return
+		}
+		existing, err := h.Repo.GetCompensation(employee.IdentityNumber, id)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "compensation not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch compensation"})
+			return
+		}
+
+		var updated models.Compensation
+		if status, err := applyPatch(c, existing, &updated); err != nil {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		updated.ID = existing.ID
+		updated.IdentityNumber = existing.IdentityNumber
+		if updated.Allowances == nil {
+			updated.Allowances = []models.Allowance{}
+		}
+
+		if err := binding.Validator.ValidateStruct(&updated); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+
+		record, err := h.Repo.UpdateCompensation(updated, v.Email)
+		if errors.Is(err, repositories.ErrCompensationExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update compensation"})
+			return
+		}
+
+		c.JSON(http.StatusOK, record)
+	}
+}
+
+func (h *EmployeeHandler) DeleteCompensation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		if !requireRole(c, v, compensationRoles...) {
+			return
+		}
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(c.Param("identityNumber"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		id, err := strconv.Atoi(c.Param("compensationId"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "compensation not found"})
+			return
+		}
+		if _, err := h.Repo.GetCompensation(employee.IdentityNumber, id); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "compensation not found"})
+			return
+		}
+
+		if err := h.Repo.DeleteCompensation(id, v.Email); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete compensation"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Compensation deleted"})
+	}
+}
+
+func (h *EmployeeHandler) GetCompensationHistory() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		if !requireRole(c, v, compensationRoles...) {
+			return
+		}
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(c.Param("identityNumber"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		changes, err := h.Repo.GetCompensationHistory(employee.IdentityNumber)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch compensation history"})
+			return
+		}
+
+		c.JSON(http.StatusOK, changes)
+	}
+}
+
+func (h *EmployeeHandler) GetPayrollCost() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		if !requireRole(c, v, compensationRoles...) {
+			return
+		}
+
+		// The usual list filters pick the employees; asOf also picks the pay in force
+		filters, err := employeeFiltersFromQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		filters["userId"] = strconv.Itoa(int(v.UserID))
+
+		asOf := time.Now().Format(time.DateOnly)
+		if date, ok := filters["asOfDate"]; ok {
+			asOf = date
+		}
+
+		report, err := h.Repo.GetPayrollCost(filters, asOf)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute payroll cost"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"asOf":        asOf,
+			"departments": report,
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-go-manager/models"
+	"go-go-manager/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// customFieldQueryPrefix marks GET /employee query parameters that filter on a
+// custom field, e.g. cf.shirtSize=XL.
+const customFieldQueryPrefix = "cf."
+
+func CreateCustomField(c *gin.Context) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+
+	if !strings.HasPrefix(auth, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		return
+	}
+
+	if c.GetHeader("Content-Type") != "application/json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing content-type"})
+		return
+	}
+
+	auth = auth[7:]
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req models.CustomFieldDefinition
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+		return
+	}
+
+	if err := req.Check(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := models.FindCustomFieldDefinition(v.UserID, req.Key); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Custom field already exists"})
+		return
+	}
+
+	definition, err := models.CreateCustomFieldDefinition(v.UserID, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create custom field"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, definition)
+}
+
+func GetCustomFields(c *gin.Context) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+
+	if !strings.HasPrefix(auth, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		return
+	}
+
+	auth = auth[7:]
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	definitions, err := models.GetCustomFieldDefinitions(v.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch custom fields"})
+		return
+	}
+
+	c.JSON(http.StatusOK, definitions)
+}
+
+func UpdateCustomField(c *gin.Context) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+
+	if !strings.HasPrefix(auth, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		return
+	}
+
+	auth = auth[7:]
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	existing, err := models.FindCustomFieldDefinition(v.UserID, c.Param("key"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Custom field not found"})
+		return
+	}
+
+	var updated models.CustomFieldDefinition
+	if status, err := applyPatch(c, existing, &updated); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	if updated.Key != existing.Key || updated.Type != existing.Type {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "key and type cannot be changed"})
+		return
+	}
+
+	if err := binding.Validator.ValidateStruct(&updated); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+		return
+	}
+
+	if err := updated.Check(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	definition, err := models.UpdateCustomFieldDefinition(v.UserID, updated)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update custom field"})
+		return
+	}
+
+	c.JSON(http.StatusOK, definition)
+}
+
+func DeleteCustomField(c *gin.Context) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+
+	if !strings.HasPrefix(auth, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		return
+	}
+
+	auth = auth[7:]
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	key := c.Param("key")
+	if _, err := models.FindCustomFieldDefinition(v.UserID, key); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Custom field not found"})
+		return
+	}
+
+	// Stored values go with the definition
+	if err := models.DeleteCustomFieldDefinition(v.UserID, key); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Custom field deleted"})
+}
+
+// customFieldFilterFromQuery turns cf.<key> query parameters into the JSON
+// object the employee's custom fields must contain. Values are parsed with the
+// field's type, so cf.badgeNumber=42 matches the number 42.
+func customFieldFilterFromQuery(c *gin.Context, userID uint) (string, error) {
+	values := make(map[string]interface{})
+	var definitions []models.CustomFieldDefinition
+
+	for param, raw := range c.Request.URL.Query() {
+		key, ok := strings.CutPrefix(param, customFieldQueryPrefix)
+		if !ok || len(raw) == 0 {
+			continue
+		}
+
+		if definitions == nil {
+			var err error
+			if definitions, err = models.GetCustomFieldDefinitions(userID); err != nil {
+				return "", err
+			}
+		}
+
+		var definition *models.CustomFieldDefinition
+		for i := range definitions {
+			if definitions[i].Key == key {
+				definition = &definitions[i]
+			}
+		}
+		if definition == nil {
+			return "", fmt.Errorf("%s is not a defined custom field", key)
+		}
+
+		value, err := definition.Parse(raw[0])
+		if err != nil {
+			return "", err
+		}
+		values[key] = value
+	}
+
+	if len(values) == 0 {
+		return "", nil
+	}
+
+	filter, err := json.Marshal(values)
+	if err != nil {
+		return "", errors.New("Invalid custom field filter")
+	}
+	return string(filter), nil
+}
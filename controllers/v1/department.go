@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"go-go-manager/models"
 	"go-go-manager/utils"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -88,21 +90,24 @@ func GetDepartments(c *gin.Context) {
 		return
 	}
 
-	limit := 5
-	offset := 0
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
-			limit = parsedLimit
-		}
+	page, err := pageFromQuery(c, models.DepartmentKeyset, "departmentId", 5)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
-			offset = parsedOffset
+	name := c.Query("name")
+
+	var asOf *time.Time
+	if asOfStr := c.Query("asOf"); asOfStr != "" {
+		at, err := parseAsOf(asOfStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "asOf must be a date (YYYY-MM-DD) or RFC 3339 timestamp"})
+			return
 		}
+		asOf = &at
 	}
-	name := c.Query("name")
 
-	departments, err := models.GetDepartments(v.UserID, limit, offset, name)
+	departments, info, err := models.GetDepartments(v.UserID, name, asOf, page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -120,7 +125,7 @@ func GetDepartments(c *gin.Context) {
 		response = []gin.H{}
 	}
 
-	c.JSON(http.StatusOK, response)
+	writePage(c, response, info)
 }
 
 type UpdateDepartmentRequest struct {
@@ -237,3 +242,168 @@ func DeleteDepartment(c *gin.Context) {
 
 	c.JSON(http.StatusOK, "Department deleted")
 }
+
+func GetDepartmentFlows(c *gin.Context) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+
+	if !strings.HasPrefix(auth, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		return
+	}
+
+	auth = auth[7:]
+
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(401, gin.H{"error": err.Error()})
+		return
+	}
+
+	departmentId := c.Param("departmentId")
+	_, err = models.FindDepartmentById(v.UserID, departmentId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "department not found"})
+		return
+	}
+
+	// Defaults to the year to date
+	now := time.Now()
+	from := c.DefaultQuery("from", time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.UTC).Format(time.DateOnly))
+	to := c.DefaultQuery("to", now.Format(time.DateOnly))
+
+	fromDate, err := time.Parse(time.DateOnly, from)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date (YYYY-MM-DD)"})
+		return
+	}
+	toDate, err := time.Parse(time.DateOnly, to)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date (YYYY-MM-DD)"})
+		return
+	}
+	if toDate.Before(fromDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to cannot be before from"})
+		return
+	}
+
+	flow, err := models.GetDepartmentFlow(departmentId, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, flow)
+}
+
+var departmentExportColumns = []string{"departmentId", "name", "headcount", "createdAt"}
+
+func (h *EmployeeHandler) ExportDepartments() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+			return
+		}
+
+		if !strings.HasPrefix(auth, "Bearer ") {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+			return
+		}
+
+		auth = auth[7:]
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		writer, err := startExport(c, "departments", departmentExportColumns)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		err = h.Repo.StreamDepartments(c.Request.Context(), v.UserID, c.Query("name"), func(row models.DepartmentExportRow) error {
+			return writer.Write(row, []string{
+				row.DepartmentID,
+				row.Name,
+				strconv.Itoa(row.Headcount),
+				row.CreatedAt,
+			})
+		})
+		if err == nil {
+			err = writer.Close()
+		}
+		if err != nil {
+			// Headers are already sent, so the client only sees a truncated file
+			log.Printf("Failed to export departments: %v", err)
+			c.Abort()
+		}
+	}
+}
+
+type DepartmentHeadRequest struct {
+	IdentityNumber *string `json:"identityNumber"` // null removes the head
+}
+
+func SetDepartmentHead(c *gin.Context) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+
+	if !strings.HasPrefix(auth, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		return
+	}
+
+	if c.GetHeader("Content-Type") != "application/json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing content-type"})
+		return
+	}
+
+	auth = auth[7:]
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	departmentId := c.Param("departmentId")
+	department, err := models.FindDepartmentById(v.UserID, departmentId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "department not found"})
+		return
+	}
+
+	var req DepartmentHeadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The head has to work for the same tenant, though not necessarily in the department
+	if req.IdentityNumber != nil {
+		ok, err := models.IsTenantEmployee(v.UserID, *req.IdentityNumber)
+		if err != nil || !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid identity number"})
+			return
+		}
+	}
+
+	if err := models.SetDepartmentHead(departmentId, req.IdentityNumber); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"departmentId":       strconv.Itoa(int(department.ID)),
+		"name":               department.Name,
+		"headIdentityNumber": req.IdentityNumber,
+	})
+}
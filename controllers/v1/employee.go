@@ -2,22 +2,27 @@ package v1
 
 import (
 	"database/sql"
+	"errors"
 	"go-go-manager/models"
 	"go-go-manager/repositories"
 	"go-go-manager/utils"
 	"net/http"
-	"strconv"
+	"reflect"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 type EmployeeHandler struct {
-	Repo *repositories.EmployeeRepository
+	Repo  *repositories.EmployeeRepository
+	Files *FileHandler
 }
 
-func NewEmployeeHandler(db *sql.DB) *EmployeeHandler {
+func NewEmployeeHandler(db *sql.DB, files *FileHandler) *EmployeeHandler {
 	return &EmployeeHandler{
-		Repo: repositories.NewEmployeeRepository(db),
+		Repo:  repositories.NewEmployeeRepository(db),
+		Files: files,
 	}
 }
 
@@ -49,28 +54,19 @@ func (h *EmployeeHandler) CreateEmployee() gin.HandlerFunc {
 			return
 		}
 
-		// Validate gender
-		if employee.Gender != models.Male && employee.Gender != models.Female {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid gender value"})
-			return
-		}
-
-		// Check for duplicate identity number
-		existingEmployee, err := h.Repo.GetEmployeeByIdentityNumber(employee.IdentityNumber)
-		if err == nil && existingEmployee != nil {
-			c.JSON(http.StatusConflict, gin.H{"error": "Identity number conflict"})
+		if !validateNewEmployee(c, h.Repo, v.UserID, &employee) {
 			return
 		}
 
-		// Check department id available or not
-		_, err = models.FindDepartmentById(v.UserID, employee.DepartmentID)
+		// Add employee to the database, together with their onboarding checklists
+		err = h.Repo.Transaction(func(txRepo *repositories.EmployeeRepository) error {
+			if err := txRepo.AddEmployee(employee); err != nil {
+				return err
+			}
+			_, err := txRepo.StartChecklists(v.UserID, models.Onboarding, employee, time.Now())
+			return err
+		})
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Department ID"})
-			return
-		}
-
-		// Add employee to the database
-		if err := h.Repo.AddEmployee(employee); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create employee", "details": err.Error()})
 			return
 		}
@@ -81,16 +77,108 @@ func (h *EmployeeHandler) CreateEmployee() gin.HandlerFunc {
 			"identityNumber":   employee.IdentityNumber,
 			"gender":           employee.Gender,
 			"employeeImageUri": employee.EmployeeImageURI,
+			"customFields":     employee.CustomFields,
 		})
 	}
 }
 
+// validateNewEmployee checks a new employee against the tenant's identity
+// scheme, custom fields and departments, and normalizes the custom fields. It
+// writes the error response itself.
+func validateNewEmployee(c *gin.Context, repo *repositories.EmployeeRepository, userID uint, employee *models.Employee) bool {
+	// Validate gender
+	if employee.Gender != models.Male && employee.Gender != models.Female {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid gender value"})
+		return false
+	}
+
+	// Validate the identity number against the tenant's scheme
+	scheme, err := models.FindIdentityScheme(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch identity scheme"})
+		return false
+	}
+	if err := checkIdentityNumber(scheme, *employee, nil); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid identity number", "details": err.Error()})
+		return false
+	}
+
+	// Validate custom fields against the tenant's definitions
+	definitions, err := models.GetCustomFieldDefinitions(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch custom fields"})
+		return false
+	}
+	customFields, problems := models.ValidateCustomFields(definitions, employee.CustomFields)
+	if len(problems) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid custom fields", "details": problems})
+		return false
+	}
+	employee.CustomFields = customFields
+
+	// Check for duplicate identity number
+	existingEmployee, err := repo.GetEmployeeByIdentityNumber(employee.IdentityNumber)
+	if err == nil && existingEmployee != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Identity number conflict"})
+		return false
+	}
+
+	// Check department id available or not
+	_, err = models.FindDepartmentById(userID, employee.DepartmentID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Department ID"})
+		return false
+	}
+
+	return true
+}
+
 type EmployeeResponse struct {
-	IdentityNumber   string        `json:"identityNumber"`
-	Name             string        `json:"name"`
-	Gender           models.Gender `json:"gender"`
-	DepartmentID     string        `json:"departmentId"`
-	EmployeeImageURI string        `json:"employeeImageUri"`
+	IdentityNumber   string                  `json:"identityNumber"`
+	Name             string                  `json:"name"`
+	Gender           models.Gender           `json:"gender"`
+	DepartmentID     string                  `json:"departmentId"`
+	EmployeeImageURI string                  `json:"employeeImageUri"`
+	CustomFields     map[string]interface{}  `json:"customFields"`
+	Status           models.EmploymentStatus `json:"status"`
+	CreatedAt        string                  `json:"createdAt"`
+}
+
+// employeeFiltersFromQuery reads the filters accepted by GET /employee.
+func employeeFiltersFromQuery(c *gin.Context) (map[string]string, error) {
+	filters := make(map[string]string)
+
+	// Extract query parameters
+	if identityNumber := c.Query("identityNumber"); identityNumber != "" {
+		filters["identityNumber"] = identityNumber
+	}
+	if name := c.Query("name"); name != "" {
+		filters["name"] = name
+	}
+	if gender := c.Query("gender"); gender != "" {
+		filters["gender"] = gender
+	}
+	if departmentID := c.Query("departmentId"); departmentID != "" {
+		filters["departmentId"] = departmentID
+	}
+
+	// Status defaults to current staff; "former" lists terminated employees
+	status := c.DefaultQuery("status", "current")
+	if status != "current" && status != "former" && status != "all" && !models.EmploymentStatus(status).IsValid() {
+		return nil, errors.New("Invalid status value")
+	}
+	filters["status"] = status
+
+	if asOfStr := c.Query("asOf"); asOfStr != "" {
+		asOf, err := parseAsOf(asOfStr)
+		if err != nil {
+			return nil, errors.New("asOf must be a date (YYYY-MM-DD) or RFC 3339 timestamp")
+		}
+		filters["asOf"] = asOf.Format(time.RFC3339Nano)
+		filters["asOfDate"] = asOf.Add(-time.Nanosecond).Format(time.DateOnly)
+	}
+
+	return filters, nil
 }
 
 func (h *EmployeeHandler) GetEmployees() gin.HandlerFunc {
@@ -103,38 +191,37 @@ func (h *EmployeeHandler) GetEmployees() gin.HandlerFunc {
 		}
 
 		auth = auth[7:] // Remove "Bearer " prefix
-		_, err := utils.ValidateJWT(auth)
+		v, err := utils.ValidateJWT(auth)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			return
 		}
 
 		// Proceed with the handler logic
-		filters := make(map[string]string)
-
-		// Extract query parameters
-		if identityNumber := c.Query("identityNumber"); identityNumber != "" {
-			filters["identityNumber"] = identityNumber
-		}
-		if name := c.Query("name"); name != "" {
-			filters["name"] = name
+		filters, err := employeeFiltersFromQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
 		}
-		if gender := c.Query("gender"); gender != "" {
-			filters["gender"] = gender
+
+		customFieldFilter, err := customFieldFilterFromQuery(c, v.UserID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
 		}
-		if departmentID := c.Query("departmentId"); departmentID != "" {
-			filters["departmentId"] = departmentID
+		if customFieldFilter != "" {
+			filters["customFields"] = customFieldFilter
 		}
 
 		// Pagination
-		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5"))
-		offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-
-		filters["limit"] = strconv.Itoa(limit)
-		filters["offset"] = strconv.Itoa(offset)
+		page, err := pageFromQuery(c, repositories.EmployeeKeyset, "identityNumber", 5)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 
 		// Fetch employees from the database
-		employees, err := h.Repo.FilterEmployees(filters)
+		employees, info, err := h.Repo.FilterEmployees(filters, page)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch employees"})
 			return
@@ -148,11 +235,23 @@ func (h *EmployeeHandler) GetEmployees() gin.HandlerFunc {
 				Gender:           employee.Gender,
 				DepartmentID:     employee.DepartmentID,
 				EmployeeImageURI: employee.EmployeeImageURI,
+				CustomFields:     employee.CustomFields,
+				Status:           employee.Status,
+				CreatedAt:        employee.CreatedAt,
 			})
 		}
 
-		c.JSON(http.StatusOK, response)
+		writePage(c, response, info)
+	}
+}
+
+// customFieldsChanged reports whether a patch changed the custom fields. No
+// fields and an empty object are the same.
+func customFieldsChanged(before map[string]interface{}, after map[string]interface{}) bool {
+	if len(before) == 0 && len(after) == 0 {
+		return false
 	}
+	return !reflect.DeepEqual(before, after)
 }
 
 func (h *EmployeeHandler) UpdateEmployee() gin.HandlerFunc {
@@ -164,13 +263,13 @@ func (h *EmployeeHandler) UpdateEmployee() gin.HandlerFunc {
 			return
 		}
 
-		if c.GetHeader("Content-Type") != "application/json" {
+		if c.GetHeader("Content-Type") == "" {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing content-type"})
 			return
 		}
 
 		auth = auth[7:] // Remove "Bearer " prefix
-		_, err := utils.ValidateJWT(auth)
+		v, err := utils.ValidateJWT(auth)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			return
@@ -184,10 +283,20 @@ func (h *EmployeeHandler) UpdateEmployee() gin.HandlerFunc {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
 			return
 		}
+		if _, err := models.FindDepartmentById(v.UserID, existingEmployee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+			return
+		}
 
+		// Only the fields in the patch change; validation runs on the merged result
 		var updatedEmployee models.Employee
-		if err := c.ShouldBindJSON(&updatedEmployee); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		if status, err := applyPatch(c, existingEmployee, &updatedEmployee); err != nil {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := binding.Validator.ValidateStruct(&updatedEmployee); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
 			return
 		}
 
@@ -197,6 +306,45 @@ func (h *EmployeeHandler) UpdateEmployee() gin.HandlerFunc {
 			return
 		}
 
+		// The identity number keys the employee's history and cannot change
+		if updatedEmployee.IdentityNumber != existingEmployee.IdentityNumber {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "identityNumber cannot be changed"})
+			return
+		}
+
+		if _, err := models.FindDepartmentById(v.UserID, updatedEmployee.DepartmentID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Department ID"})
+			return
+		}
+
+		// Validate the identity number against the tenant's scheme
+		scheme, err := models.FindIdentityScheme(v.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch identity scheme"})
+			return
+		}
+		if err := checkIdentityNumber(scheme, updatedEmployee, existingEmployee); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid identity number", "details": err.Error()})
+			return
+		}
+
+		// Validate custom fields against the tenant's definitions, but only when
+		// the patch changes them: a field made required after the employee was
+		// added must not block edits to their other fields
+		if customFieldsChanged(existingEmployee.CustomFields, updatedEmployee.CustomFields) {
+			definitions, err := models.GetCustomFieldDefinitions(v.UserID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch custom fields"})
+				return
+			}
+			customFields, problems := models.ValidateCustomFields(definitions, updatedEmployee.CustomFields)
+			if len(problems) > 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid custom fields", "details": problems})
+				return
+			}
+			updatedEmployee.CustomFields = customFields
+		}
+
 		// Update employee in the database
 		if err := h.Repo.UpdateEmployee(identityNumber, updatedEmployee); err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
@@ -217,7 +365,7 @@ func (h *EmployeeHandler) DeleteEmployee() gin.HandlerFunc {
 		}
 
 		auth = auth[7:] // Remove "Bearer " prefix
-		_, err := utils.ValidateJWT(auth)
+		v, err := utils.ValidateJWT(auth)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			return
@@ -226,17 +374,33 @@ func (h *EmployeeHandler) DeleteEmployee() gin.HandlerFunc {
 		// Proceed with the handler logic
 		identityNumber := c.Param("identityNumber")
 
-		_, err = h.Repo.GetEmployeeByIdentityNumber(identityNumber)
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(identityNumber)
 		if err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
 			return
 		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
 
-		// Delete employee from the database
-		if err := h.Repo.DeleteEmployee(identityNumber); err != nil {
+		// Delete employee from the database, starting their offboarding
+		var documentKeys []string
+		err = h.Repo.Transaction(func(txRepo *repositories.EmployeeRepository) error {
+			if err := startOffboarding(txRepo, v.UserID, *employee, time.Now()); err != nil {
+				return err
+			}
+			var err error
+			if documentKeys, err = txRepo.GetDocumentStorageKeys(identityNumber); err != nil {
+				return err
+			}
+			return txRepo.DeleteEmployee(identityNumber)
+		})
+		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
+		h.removeDocumentObjects(documentKeys)
 
 		c.JSON(http.StatusOK, gin.H{"message": "Employee deleted"})
 	}
@@ -0,0 +1,156 @@
+package v1
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"go-go-manager/models"
+	"go-go-manager/repositories"
+	"go-go-manager/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// inviteValidity is how long an employee has to accept an invitation.
+const inviteValidity = 7 * 24 * time.Hour
+
+type AccountInviteRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// newInviteToken returns a random invite token and the hash that is stored.
+func newInviteToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := hex.EncodeToString(b)
+	return token, hashInviteToken(token), nil
+}
+
+func hashInviteToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (h *EmployeeHandler) InviteEmployeeAccount() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		var req AccountInviteRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(c.Param("identityNumber"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		token, tokenHash, err := newInviteToken()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invitation"})
+			return
+		}
+
+		account, err := h.Repo.InviteEmployeeAccount(employee.IdentityNumber, req.Email, tokenHash, time.Now().Add(inviteValidity), v.Email)
+		if errors.Is(err, repositories.ErrAccountActive) || errors.Is(err, repositories.ErrAccountEmailTaken) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invitation"})
+			return
+		}
+
+		// The token is only ever shown here; the manager passes it on to the
+		// employee, who accepts it at POST /self/activate
+		c.JSON(http.StatusCreated, gin.H{
+			"account":     account,
+			"inviteToken": token,
+		})
+	}
+}
+
+func (h *EmployeeHandler) GetEmployeeAccount() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		account, err := h.Repo.GetEmployeeAccount(c.Param("identityNumber"))
+		if err != nil || account.UserID != v.UserID {
+			c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, account)
+	}
+}
+
+func (h *EmployeeHandler) RevokeEmployeeAccount() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		account, err := h.Repo.GetEmployeeAccount(c.Param("identityNumber"))
+		if err == sql.ErrNoRows || (err == nil && account.UserID != v.UserID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke account"})
+			return
+		}
+
+		if _, err := h.Repo.DeleteEmployeeAccount(account.IdentityNumber); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke account"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Account revoked"})
+	}
+}
@@ -0,0 +1,293 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"go-go-manager/models"
+	"go-go-manager/repositories"
+	"go-go-manager/utils"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const maxBatchSize = 100
+
+type BatchOperation struct {
+	Op             string           `json:"op"` // "create", "update" or "delete"
+	IdentityNumber string           `json:"identityNumber"`
+	Employee       *models.Employee `json:"employee"`
+}
+
+type BatchRequest struct {
+	Mode       string           `json:"mode" binding:"omitempty,oneof=atomic best-effort"`
+	Operations []BatchOperation `json:"operations" binding:"required,min=1"`
+}
+
+type BatchOperationResult struct {
+	Index          int    `json:"index"`
+	Op             string `json:"op"`
+	IdentityNumber string `json:"identityNumber"`
+	Status         string `json:"status"` // "succeeded", "failed", "rolled_back" or "skipped"
+	Code           int    `json:"code,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// batchError carries the status code the operation would have returned on its own endpoint.
+type batchError struct {
+	code    int
+	message string
+}
+
+func (e *batchError) Error() string {
+	return e.message
+}
+
+var errBatchAborted = errors.New("batch aborted")
+
+func (h *EmployeeHandler) BatchEmployees() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		if c.GetHeader("Content-Type") != "application/json" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing content-type"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		var req BatchRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if len(req.Operations) > maxBatchSize {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("A batch can contain at most %d operations", maxBatchSize)})
+			return
+		}
+
+		if req.Mode == "" {
+			req.Mode = "atomic"
+		}
+
+		definitions, err := models.GetCustomFieldDefinitions(v.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch custom fields"})
+			return
+		}
+
+		scheme, err := models.FindIdentityScheme(v.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch identity scheme"})
+			return
+		}
+
+		results := make([]BatchOperationResult, len(req.Operations))
+		for i, op := range req.Operations {
+			identityNumber := op.IdentityNumber
+			if identityNumber == "" && op.Employee != nil {
+				identityNumber = op.Employee.IdentityNumber
+			}
+			results[i] = BatchOperationResult{Index: i, Op: op.Op, IdentityNumber: identityNumber, Status: "skipped"}
+		}
+
+		failed := 0
+		var documentKeys []string
+		err = h.Repo.Transaction(func(txRepo *repositories.EmployeeRepository) error {
+			for i, op := range req.Operations {
+				// The files of deleted employees are removed once the batch commits
+				var opKeys []string
+				run := func() error {
+					if op.Op == "delete" {
+						var err error
+						if opKeys, err = txRepo.GetDocumentStorageKeys(op.IdentityNumber); err != nil {
+							return err
+						}
+					}
+					return applyBatchOperation(txRepo, v.UserID, definitions, scheme, op)
+				}
+
+				// Best effort isolates each operation so a failure only undoes itself
+				var opErr error
+				if req.Mode == "best-effort" {
+					opErr = txRepo.Savepoint(fmt.Sprintf("batch_op_%d", i), run)
+				} else {
+					opErr = run()
+				}
+
+				if opErr != nil {
+					failed++
+					results[i].Status = "failed"
+					results[i].Code = http.StatusInternalServerError
+					results[i].Error = opErr.Error()
+
+					var be *batchError
+					if errors.As(opErr, &be) {
+						results[i].Code = be.code
+					}
+
+					if req.Mode == "atomic" {
+						return errBatchAborted
+					}
+					continue
+				}
+
+				results[i].Status = "succeeded"
+				documentKeys = append(documentKeys, opKeys...)
+			}
+			return nil
+		})
+
+		if errors.Is(err, errBatchAborted) {
+			for i := range results {
+				if results[i].Status == "succeeded" {
+					results[i].Status = "rolled_back"
+				}
+			}
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"mode": req.Mode, "succeeded": 0, "failed": failed, "results": results})
+			return
+		}
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply batch", "details": err.Error()})
+			return
+		}
+		h.removeDocumentObjects(documentKeys)
+
+		c.JSON(http.StatusOK, gin.H{"mode": req.Mode, "succeeded": len(results) - failed, "failed": failed, "results": results})
+	}
+}
+
+// applyBatchOperation runs one operation with the same checks as the single-employee endpoints.
+func applyBatchOperation(repo *repositories.EmployeeRepository, userID uint, definitions []models.CustomFieldDefinition, scheme string, op BatchOperation) error {
+	switch op.Op {
+	case "create":
+		if op.Employee == nil {
+			return &batchError{http.StatusBadRequest, "employee is required"}
+		}
+		if err := validateBatchEmployee(op.Employee, definitions, scheme, nil); err != nil {
+			return err
+		}
+
+		existingEmployee, err := repo.GetEmployeeByIdentityNumber(op.Employee.IdentityNumber)
+		if err == nil && existingEmployee != nil {
+			return &batchError{http.StatusConflict, "Identity number conflict"}
+		}
+
+		if err := checkBatchDepartment(repo, userID, op.Employee.DepartmentID); err != nil {
+			return err
+		}
+
+		if err := repo.AddEmployee(*op.Employee); err != nil {
+			return err
+		}
+		_, err = repo.StartChecklists(userID, models.Onboarding, *op.Employee, time.Now())
+		return err
+	case "update":
+		if op.IdentityNumber == "" {
+			return &batchError{http.StatusBadRequest, "identityNumber is required"}
+		}
+		if op.Employee == nil {
+			return &batchError{http.StatusBadRequest, "employee is required"}
+		}
+
+		existingEmployee, err := getBatchEmployee(repo, userID, op.IdentityNumber)
+		if err != nil {
+			return err
+		}
+		if op.Employee.IdentityNumber != existingEmployee.IdentityNumber {
+			return &batchError{http.StatusBadRequest, "identityNumber cannot be changed"}
+		}
+		if err := validateBatchEmployee(op.Employee, definitions, scheme, existingEmployee); err != nil {
+			return err
+		}
+		if err := checkBatchDepartment(repo, userID, op.Employee.DepartmentID); err != nil {
+			return err
+		}
+
+		return repo.UpdateEmployee(op.IdentityNumber, *op.Employee)
+	case "delete":
+		if op.IdentityNumber == "" {
+			return &batchError{http.StatusBadRequest, "identityNumber is required"}
+		}
+
+		employee, err := getBatchEmployee(repo, userID, op.IdentityNumber)
+		if err != nil {
+			return err
+		}
+
+		if err := startOffboarding(repo, userID, *employee, time.Now()); err != nil {
+			return err
+		}
+		return repo.DeleteEmployee(op.IdentityNumber)
+	default:
+		return &batchError{http.StatusBadRequest, "op must be create, update or delete"}
+	}
+}
+
+// getBatchEmployee returns the employee when they work in one of the tenant's
+// departments; employees of other tenants are reported as not found.
+func getBatchEmployee(repo *repositories.EmployeeRepository, userID uint, identityNumber string) (*models.Employee, error) {
+	employee, err := repo.GetEmployeeByIdentityNumber(identityNumber)
+	if err != nil {
+		return nil, &batchError{http.StatusNotFound, "Employee not found"}
+	}
+
+	ok, err := repo.IsTenantDepartment(userID, employee.DepartmentID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, &batchError{http.StatusNotFound, "Employee not found"}
+	}
+	return employee, nil
+}
+
+func checkBatchDepartment(repo *repositories.EmployeeRepository, userID uint, departmentID string) error {
+	ok, err := repo.IsTenantDepartment(userID, departmentID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &batchError{http.StatusBadRequest, "Invalid Department ID"}
+	}
+	return nil
+}
+
+// validateBatchEmployee checks the employee and normalizes its custom fields in place.
+func validateBatchEmployee(employee *models.Employee, definitions []models.CustomFieldDefinition, scheme string, existing *models.Employee) error {
+	if err := binding.Validator.ValidateStruct(employee); err != nil {
+		return &batchError{http.StatusBadRequest, strings.Join(describeValidationError(err), "; ")}
+	}
+
+	// Validate gender
+	if employee.Gender != models.Male && employee.Gender != models.Female {
+		return &batchError{http.StatusBadRequest, "Invalid gender value"}
+	}
+
+	if err := checkIdentityNumber(scheme, *employee, existing); err != nil {
+		return &batchError{http.StatusBadRequest, err.Error()}
+	}
+
+	customFields, problems := models.ValidateCustomFields(definitions, employee.CustomFields)
+	if len(problems) > 0 {
+		return &batchError{http.StatusBadRequest, strings.Join(problems, "; ")}
+	}
+	employee.CustomFields = customFields
+
+	return nil
+}
@@ -0,0 +1,305 @@
+package v1
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"go-go-manager/models"
+	"go-go-manager/repositories"
+	"go-go-manager/utils"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxDocumentSize = 10 * 1024 * 1024 // 10 MiB
+
+type DocumentHandler struct {
+	Repo  *repositories.EmployeeRepository
+	Files *FileHandler
+}
+
+func NewDocumentHandler(db *sql.DB, files *FileHandler) *DocumentHandler {
+	return &DocumentHandler{
+		Repo:  repositories.NewEmployeeRepository(db),
+		Files: files,
+	}
+}
+
+// removeDocumentObjects deletes the files of documents whose records went
+// with their employee. A leftover object is only wasted storage, so failures
+// are logged rather than reported.
+func (h *EmployeeHandler) removeDocumentObjects(keys []string) {
+	for _, key := range keys {
+		if err := h.Files.deleteObject(key); err != nil {
+			log.Printf("failed to remove document %s: %v", key, err)
+		}
+	}
+}
+
+type DocumentUploadRequest struct {
+	File      *multipart.FileHeader `form:"file" binding:"required"`
+	Category  string                `form:"category" binding:"required,oneof=contract id_scan certificate other"`
+	Title     string                `form:"title" binding:"required,min=1,max=255"`
+	ExpiresOn string                `form:"expiresOn" binding:"omitempty,datetime=2006-01-02"`
+}
+
+func isValidDocumentType(filename string) bool {
+	switch strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), ".")) {
+	case "pdf", "jpg", "jpeg", "png":
+		return true
+	}
+	return false
+}
+
+// documentKey builds a storage key that is unique even when the same file is uploaded twice.
+func documentKey(userID uint, identityNumber string, filename string) (string, error) {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("documents/%d/%s/%s-%s", userID, identityNumber, hex.EncodeToString(suffix), filepath.Base(filename)), nil
+}
+
+// tenantEmployee validates the token, requires an admin account and resolves
+// the employee in the path. It writes the error response itself.
+func (h *DocumentHandler) tenantEmployee(c *gin.Context) (*utils.Claims, *models.Employee, bool) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+		return nil, nil, false
+	}
+
+	auth = auth[7:] // Remove "Bearer " prefix
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+
+	if !requireRole(c, v, models.RoleAdmin) {
+		return nil, nil, false
+	}
+
+	employee, err := h.Repo.GetEmployeeByIdentityNumber(c.Param("identityNumber"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+		return nil, nil, false
+	}
+	if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+		return nil, nil, false
+	}
+
+	return v, employee, true
+}
+
+func (h *DocumentHandler) UploadDocument() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxDocumentSize+1024*1024)
+
+		v, employee, ok := h.tenantEmployee(c)
+		if !ok {
+			return
+		}
+
+		var req DocumentUploadRequest
+		if err := c.ShouldBind(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+
+		if !isValidDocumentType(req.File.Filename) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file type. Allowed types: pdf, jpeg, jpg, png"})
+			return
+		}
+
+		if req.File.Size > maxDocumentSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "File size exceeds the maximum limit of 10 MiB"})
+			return
+		}
+
+		key, err := documentKey(v.UserID, employee.IdentityNumber, req.File.Filename)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store document"})
+			return
+		}
+
+		contentType := getContentType(strings.ToLower(req.File.Filename))
+		if err := h.Files.putObject(key, contentType, req.File); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store document"})
+			return
+		}
+
+		document := models.EmployeeDocument{
+			IdentityNumber: employee.IdentityNumber,
+			Category:       models.DocumentCategory(req.Category),
+			Title:          req.Title,
+			FileName:       filepath.Base(req.File.Filename),
+			ContentType:    contentType,
+			SizeBytes:      req.File.Size,
+			StorageKey:     key,
+			UploadedBy:     v.Email,
+		}
+		if req.ExpiresOn != "" {
+			document.ExpiresOn = &req.ExpiresOn
+		}
+
+		document, err = h.Repo.AddDocument(document)
+		if err != nil {
+			if err := h.Files.deleteObject(key); err != nil {
+				log.Printf("failed to remove orphaned document %s: %v", key, err)
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save document"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, document)
+	}
+}
+
+func (h *DocumentHandler) GetDocuments() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, employee, ok := h.tenantEmployee(c)
+		if !ok {
+			return
+		}
+
+		category := c.Query("category")
+		if category != "" && !models.DocumentCategory(category).IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category"})
+			return
+		}
+
+		documents, err := h.Repo.GetDocuments(employee.IdentityNumber, category)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch documents"})
+			return
+		}
+
+		c.JSON(http.StatusOK, documents)
+	}
+}
+
+// document resolves the document in the path, scoped to the employee.
+func (h *DocumentHandler) document(c *gin.Context, employee *models.Employee) (*models.EmployeeDocument, bool) {
+	id, err := strconv.Atoi(c.Param("documentId"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "document not found"})
+		return nil, false
+	}
+
+	document, err := h.Repo.GetDocument(employee.IdentityNumber, id)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "document not found"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch document"})
+		return nil, false
+	}
+	return document, true
+}
+
+func (h *DocumentHandler) DownloadDocument() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, employee, ok := h.tenantEmployee(c)
+		if !ok {
+			return
+		}
+
+		document, ok := h.document(c, employee)
+		if !ok {
+			return
+		}
+
+		body, err := h.Files.getObject(c.Request.Context(), document.StorageKey)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to read document"})
+			return
+		}
+		defer body.Close()
+
+		c.Header("Content-Type", document.ContentType)
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", document.FileName))
+		c.Header("Content-Length", strconv.FormatInt(document.SizeBytes, 10))
+		c.Status(http.StatusOK)
+		if _, err := io.Copy(c.Writer, body); err != nil {
+			log.Printf("failed to stream document %d: %v", document.ID, err)
+		}
+	}
+}
+
+func (h *DocumentHandler) DeleteDocument() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, employee, ok := h.tenantEmployee(c)
+		if !ok {
+			return
+		}
+
+		document, ok := h.document(c, employee)
+		if !ok {
+			return
+		}
+
+		if err := h.Repo.DeleteDocument(document.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete document"})
+			return
+		}
+
+		// The record is gone, so a leftover object is only wasted storage
+		if err := h.Files.deleteObject(document.StorageKey); err != nil {
+			log.Printf("failed to remove document %s: %v", document.StorageKey, err)
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Document deleted successfully"})
+	}
+}
+
+func (h *DocumentHandler) GetExpiringDocuments() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		if !requireRole(c, v, models.RoleAdmin) {
+			return
+		}
+
+		days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+		if err != nil || days < 0 || days > 3650 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 0 and 3650"})
+			return
+		}
+		includeExpired := c.Query("includeExpired") == "true"
+
+		documents, err := h.Repo.GetExpiringDocuments(v.UserID, days, includeExpired)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch documents"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"asOf":      time.Now().Format(time.DateOnly),
+			"days":      days,
+			"documents": documents,
+		})
+	}
+}
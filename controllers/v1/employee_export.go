@@ -0,0 +1,74 @@
+package v1
+
+import (
+	"go-go-manager/models"
+	"go-go-manager/utils"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+var employeeExportColumns = []string{"identityNumber", "name", "gender", "departmentId", "departmentName", "employeeImageUri", "status"}
+
+func (h *EmployeeHandler) ExportEmployees() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Same filters as GET /employee, without pagination
+		filters, err := employeeFiltersFromQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		filters["userId"] = strconv.Itoa(int(v.UserID))
+
+		customFieldFilter, err := customFieldFilterFromQuery(c, v.UserID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if customFieldFilter != "" {
+			filters["customFields"] = customFieldFilter
+		}
+
+		writer, err := startExport(c, "employees", employeeExportColumns)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		err = h.Repo.StreamEmployees(c.Request.Context(), filters, func(row models.EmployeeExportRow) error {
+			return writer.Write(row, []string{
+				row.IdentityNumber,
+				row.Name,
+				string(row.Gender),
+				row.DepartmentID,
+				row.DepartmentName,
+				row.EmployeeImageURI,
+				string(row.Status),
+			})
+		})
+		if err == nil {
+			err = writer.Close()
+		}
+		if err != nil {
+			// Headers are already sent, so the client only sees a truncated file
+			log.Printf("Failed to export employees: %v", err)
+			c.Abort()
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"go-go-manager/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseAsOf accepts either a date or an RFC 3339 timestamp. A date means the
+// state at the end of that day, so it resolves to the following midnight UTC.
+func parseAsOf(value string) (time.Time, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date.AddDate(0, 0, 1), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func (h *EmployeeHandler) GetEmployeeHistory() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		identityNumber := c.Param("identityNumber")
+
+		// History outlives the employee row, so deleted employees are still found here
+		versions, err := h.Repo.GetEmployeeHistory(v.UserID, identityNumber)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch employee history"})
+			return
+		}
+
+		if len(versions) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, versions)
+	}
+}
@@ -0,0 +1,376 @@
+package v1
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"go-go-manager/models"
+	"go-go-manager/utils"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	maxImportFileSize = 5 * 1024 * 1024 // 5 MiB
+	maxImportRows     = 5000
+)
+
+// importColumns maps normalized header names to employee fields. Departments
+// can be referenced by name or by id in any of the department columns.
+var importColumns = map[string]string{
+	"identitynumber":   "identityNumber",
+	"name":             "name",
+	"gender":           "gender",
+	"department":       "department",
+	"departmentid":     "department",
+	"departmentname":   "department",
+	"employeeimageuri": "employeeImageUri",
+	"imageuri":         "employeeImageUri",
+}
+
+var requiredImportColumns = []string{"identityNumber", "name", "gender", "department", "employeeImageUri"}
+
+type ImportRowResult struct {
+	Row            int      `json:"row"`
+	IdentityNumber string   `json:"identityNumber"`
+	Errors         []string `json:"errors"`
+}
+
+type ImportReport struct {
+	Mode        string            `json:"mode"`
+	TotalRows   int               `json:"totalRows"`
+	ValidRows   int               `json:"validRows"`
+	InvalidRows int               `json:"invalidRows"`
+	Imported    int               `json:"imported"`
+	Errors      []ImportRowResult `json:"errors"`
+}
+
+func (h *EmployeeHandler) ImportEmployees() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		mode := c.DefaultQuery("mode", "dry-run")
+		if mode != "dry-run" && mode != "commit" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "mode must be dry-run or commit"})
+			return
+		}
+
+		_, fileHeader, err := c.Request.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read the file"})
+			return
+		}
+
+		if fileHeader.Size > maxImportFileSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "File size exceeds the maximum limit of 5 MiB"})
+			return
+		}
+
+		records, err := readSpreadsheet(fileHeader)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if len(records) < 2 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "File must contain a header row and at least one employee"})
+			return
+		}
+
+		if len(records)-1 > maxImportRows {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("File exceeds the maximum of %d rows", maxImportRows)})
+			return
+		}
+
+		columns, err := mapImportColumns(records[0])
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		departments, err := models.FindDepartmentsByUser(v.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch departments"})
+			return
+		}
+
+		// Columns named after a custom field key fill that custom field
+		definitions, err := models.GetCustomFieldDefinitions(v.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch custom fields"})
+			return
+		}
+
+		scheme, err := models.FindIdentityScheme(v.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch identity scheme"})
+			return
+		}
+
+		customFieldColumns := make(map[int]models.CustomFieldDefinition)
+		for i, name := range records[0] {
+			for _, definition := range definitions {
+				if strings.EqualFold(strings.TrimSpace(name), definition.Key) {
+					customFieldColumns[i] = definition
+				}
+			}
+		}
+
+		departmentIDs := make(map[string]string)
+		for _, dept := range departments {
+			id := strconv.Itoa(int(dept.ID))
+			departmentIDs[id] = id
+			departmentIDs[strings.ToLower(dept.Name)] = id
+		}
+
+		report := ImportReport{Mode: mode, Errors: []ImportRowResult{}}
+		employees := []models.Employee{}
+		rowNumbers := []int{}
+		seen := make(map[string]int)
+
+		for i, record := range records[1:] {
+			rowNumber := i + 2 // 1-based, after the header row
+			if isBlankRecord(record) {
+				continue
+			}
+			report.TotalRows++
+
+			cell := func(field string) string {
+				index, ok := columns[field]
+				if !ok || index >= len(record) {
+					return ""
+				}
+				return strings.TrimSpace(record[index])
+			}
+
+			employee := models.Employee{
+				IdentityNumber:   cell("identityNumber"),
+				Name:             cell("name"),
+				Gender:           models.Gender(cell("gender")),
+				EmployeeImageURI: cell("employeeImageUri"),
+			}
+
+			rowErrors := []string{}
+
+			department := cell("department")
+			if id, ok := departmentIDs[strings.ToLower(department)]; ok {
+				employee.DepartmentID = id
+			} else {
+				rowErrors = append(rowErrors, fmt.Sprintf("department %q not found", department))
+				employee.DepartmentID = department
+			}
+
+			// Same rules as the JSON binding on POST /employee
+			if err := binding.Validator.ValidateStruct(&employee); err != nil {
+				rowErrors = append(rowErrors, describeValidationError(err)...)
+			} else if employee.Gender != models.Male && employee.Gender != models.Female {
+				rowErrors = append(rowErrors, "Invalid gender value")
+			} else if err := checkIdentityNumber(scheme, employee, nil); err != nil {
+				rowErrors = append(rowErrors, err.Error())
+			}
+
+			customFields := make(map[string]interface{})
+			for index, definition := range customFieldColumns {
+				if index >= len(record) || strings.TrimSpace(record[index]) == "" {
+					continue
+				}
+				value, err := definition.Parse(strings.TrimSpace(record[index]))
+				if err != nil {
+					rowErrors = append(rowErrors, err.Error())
+					continue
+				}
+				customFields[definition.Key] = value
+			}
+			var problems []string
+			employee.CustomFields, problems = models.ValidateCustomFields(definitions, customFields)
+			rowErrors = append(rowErrors, problems...)
+
+			if employee.IdentityNumber != "" {
+				if firstRow, ok := seen[employee.IdentityNumber]; ok {
+					rowErrors = append(rowErrors, fmt.Sprintf("identity number duplicates row %d", firstRow))
+				} else {
+					seen[employee.IdentityNumber] = rowNumber
+				}
+			}
+
+			if len(rowErrors) > 0 {
+				report.Errors = append(report.Errors, ImportRowResult{
+					Row:            rowNumber,
+					IdentityNumber: employee.IdentityNumber,
+					Errors:         rowErrors,
+				})
+				continue
+			}
+
+			employees = append(employees, employee)
+			rowNumbers = append(rowNumbers, rowNumber)
+		}
+
+		identityNumbers := make([]string, 0, len(employees))
+		for _, employee := range employees {
+			identityNumbers = append(identityNumbers, employee.IdentityNumber)
+		}
+
+		existing, err := h.Repo.ExistingIdentityNumbers(identityNumbers)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check identity numbers"})
+			return
+		}
+
+		valid := make([]models.Employee, 0, len(employees))
+		for i, employee := range employees {
+			if existing[employee.IdentityNumber] {
+				report.Errors = append(report.Errors, ImportRowResult{
+					Row:            rowNumbers[i],
+					IdentityNumber: employee.IdentityNumber,
+					Errors:         []string{"Identity number conflict"},
+				})
+				continue
+			}
+			valid = append(valid, employee)
+		}
+
+		report.ValidRows = len(valid)
+		report.InvalidRows = report.TotalRows - report.ValidRows
+
+		if mode == "dry-run" {
+			c.JSON(http.StatusOK, report)
+			return
+		}
+
+		// Commit mode is all or nothing
+		if report.InvalidRows > 0 {
+			c.JSON(http.StatusUnprocessableEntity, report)
+			return
+		}
+
+		if err := h.Repo.ImportEmployees(v.UserID, valid); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import employees", "details": err.Error()})
+			return
+		}
+
+		report.Imported = len(valid)
+		c.JSON(http.StatusCreated, report)
+	}
+}
+
+// readSpreadsheet returns all rows of a CSV file or of the first sheet of an XLSX workbook.
+func readSpreadsheet(fileHeader *multipart.FileHeader) ([][]string, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, errors.New("Failed to read the file")
+	}
+	defer file.Close()
+
+	switch ext := strings.ToLower(fileHeader.Filename[strings.LastIndex(fileHeader.Filename, ".")+1:]); ext {
+	case "csv":
+		reader := csv.NewReader(file)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+
+		var records [][]string
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("Invalid CSV file: %v", err)
+			}
+			records = append(records, record)
+		}
+		return records, nil
+	case "xlsx":
+		workbook, err := excelize.OpenReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid XLSX file: %v", err)
+		}
+		defer workbook.Close()
+
+		records, err := workbook.GetRows(workbook.GetSheetName(0))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid XLSX file: %v", err)
+		}
+		return records, nil
+	default:
+		return nil, errors.New("Invalid file type. Allowed types: csv, xlsx")
+	}
+}
+
+// mapImportColumns resolves the header row to column indexes per employee field.
+func mapImportColumns(header []string) (map[string]int, error) {
+	columns := make(map[string]int)
+	for i, name := range header {
+		normalized := strings.Map(func(r rune) rune {
+			if r == '_' || r == '-' || r == ' ' {
+				return -1
+			}
+			return r
+		}, strings.ToLower(strings.TrimSpace(name)))
+
+		if field, ok := importColumns[normalized]; ok {
+			if _, exists := columns[field]; !exists {
+				columns[field] = i
+			}
+		}
+	}
+
+	missing := []string{}
+	for _, field := range requiredImportColumns {
+		if _, ok := columns[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("Missing columns: %s", strings.Join(missing, ", "))
+	}
+
+	return columns, nil
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// describeValidationError turns binding errors into one message per field.
+func describeValidationError(err error) []string {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return []string{err.Error()}
+	}
+
+	messages := make([]string, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		if fe.Param() != "" {
+			messages = append(messages, fmt.Sprintf("%s failed on '%s=%s'", fe.Field(), fe.Tag(), fe.Param()))
+		} else {
+			messages = append(messages, fmt.Sprintf("%s failed on '%s'", fe.Field(), fe.Tag()))
+		}
+	}
+	return messages
+}
@@ -0,0 +1,61 @@
+package v1
+
+import (
+	"go-go-manager/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *EmployeeHandler) SearchEmployees() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		term := strings.TrimSpace(c.Query("q"))
+		if utf8.RuneCountInString(term) < 2 || utf8.RuneCountInString(term) > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "q must be between 2 and 100 characters"})
+			return
+		}
+
+		// The usual list filters narrow the search further
+		filters, err := employeeFiltersFromQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		filters["userId"] = strconv.Itoa(int(v.UserID))
+
+		// Pagination
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+		if err != nil || limit <= 0 || limit > 100 {
+			limit = 10
+		}
+		offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+		if err != nil || offset < 0 {
+			offset = 0
+		}
+
+		results, err := h.Repo.SearchEmployees(filters, term, limit, offset)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search employees"})
+			return
+		}
+
+		c.JSON(http.StatusOK, results)
+	}
+}
@@ -0,0 +1,470 @@
+package v1
+
+import (
+	"database/sql"
+	"errors"
+	"go-go-manager/models"
+	"go-go-manager/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// maxSkillCriteria bounds how many skills one search may combine.
+const maxSkillCriteria = 10
+
+type EmployeeSkillRequest struct {
+	Level int `json:"level" binding:"required,min=1,max=5"`
+}
+
+// skillCriteriaFromQuery parses the repeated skill parameter, each either a
+// skill id or "<skillId>:<minLevel>". minLevel applies to skills without a
+// level of their own.
+func skillCriteriaFromQuery(c *gin.Context, userID uint) ([]models.SkillCriterion, error) {
+	values := c.QueryArray("skill")
+	if len(values) == 0 {
+		return nil, errors.New("at least one skill is required")
+	}
+	if len(values) > maxSkillCriteria {
+		return nil, errors.New("at most 10 skills can be combined")
+	}
+
+	defaultLevel, err := strconv.Atoi(c.DefaultQuery("minLevel", "1"))
+	if err != nil || defaultLevel < 1 || defaultLevel > 5 {
+		return nil, errors.New("minLevel must be between 1 and 5")
+	}
+
+	criteria := make([]models.SkillCriterion, 0, len(values))
+	for _, value := range values {
+		id, levelStr, hasLevel := strings.Cut(value, ":")
+
+		skill, err := models.FindSkillById(userID, id)
+		if err != nil {
+			return nil, errors.New("unknown skill " + id)
+		}
+
+		level := defaultLevel
+		if hasLevel {
+			if level, err = strconv.Atoi(levelStr); err != nil || level < 1 || level > 5 {
+				return nil, errors.New("skill levels must be between 1 and 5")
+			}
+		}
+		criteria = append(criteria, models.SkillCriterion{SkillID: skill.ID, MinLevel: level})
+	}
+
+	return criteria, nil
+}
+
+func (h *EmployeeHandler) GetEmployeeSkills() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(c.Param("identityNumber"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		skills, err := h.Repo.GetEmployeeSkills(employee.IdentityNumber)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch skills"})
+			return
+		}
+
+		c.JSON(http.StatusOK, skills)
+	}
+}
+
+func (h *EmployeeHandler) SetEmployeeSkill() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		var req EmployeeSkillRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(c.Param("identityNumber"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		skill, err := models.FindSkillById(v.UserID, c.Param("skillId"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "skill not found"})
+			return
+		}
+
+		rating, err := h.Repo.SetEmployeeSkill(employee.IdentityNumber, skill, req.Level)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save skill"})
+			return
+		}
+
+		c.JSON(http.StatusOK, rating)
+	}
+}
+
+func (h *EmployeeHandler) RemoveEmployeeSkill() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(c.Param("identityNumber"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		skill, err := models.FindSkillById(v.UserID, c.Param("skillId"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "skill not found"})
+			return
+		}
+
+		removed, err := h.Repo.RemoveEmployeeSkill(employee.IdentityNumber, skill.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove skill"})
+			return
+		}
+		if !removed {
+			c.JSON(http.StatusNotFound, gin.H{"error": "skill not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Skill removed"})
+	}
+}
+
+func (h *EmployeeHandler) SearchEmployeesBySkills() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		criteria, err := skillCriteriaFromQuery(c, v.UserID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// The usual list filters narrow the search further; ratings are not
+		// versioned, so there is no point in time to search as of
+		filters, err := employeeFiltersFromQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		delete(filters, "asOf")
+		delete(filters, "asOfDate")
+		filters["userId"] = strconv.Itoa(int(v.UserID))
+
+		// Pagination
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+		if err != nil || limit <= 0 || limit > 100 {
+			limit = 10
+		}
+		offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+		if err != nil || offset < 0 {
+			offset = 0
+		}
+
+		matches, err := h.Repo.SearchEmployeesBySkills(filters, criteria, limit, offset)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search employees"})
+			return
+		}
+
+		c.JSON(http.StatusOK, matches)
+	}
+}
+
+func (h *EmployeeHandler) GetCertifications() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(c.Param("identityNumber"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		certifications, err := h.Repo.GetCertifications(employee.IdentityNumber)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch certifications"})
+			return
+		}
+
+		c.JSON(http.StatusOK, certifications)
+	}
+}
+
+func (h *EmployeeHandler) AddCertification() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		var req models.Certification
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+		if req.ExpiresOn != nil && *req.ExpiresOn < req.IssuedOn {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expiresOn cannot be before issuedOn"})
+			return
+		}
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(c.Param("identityNumber"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		req.IdentityNumber = employee.IdentityNumber
+		certification, err := h.Repo.AddCertification(req)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save certification"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, certification)
+	}
+}
+
+func (h *EmployeeHandler) UpdateCertification() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(c.Param("identityNumber"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		id, err := strconv.Atoi(c.Param("certificationId"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "certification not found"})
+			return
+		}
+		existing, err := h.Repo.GetCertification(employee.IdentityNumber, id)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "certification not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch certification"})
+			return
+		}
+
+		var updated models.Certification
+		if status, err := applyPatch(c, existing, &updated); err != nil {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		updated.ID = existing.ID
+		updated.IdentityNumber = existing.IdentityNumber
+
+		if err := binding.Validator.ValidateStruct(&updated); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+		if updated.ExpiresOn != nil && *updated.ExpiresOn < updated.IssuedOn {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expiresOn cannot be before issuedOn"})
+			return
+		}
+
+		certification, err := h.Repo.UpdateCertification(updated)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update certification"})
+			return
+		}
+
+		c.JSON(http.StatusOK, certification)
+	}
+}
+
+func (h *EmployeeHandler) DeleteCertification() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(c.Param("identityNumber"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		id, err := strconv.Atoi(c.Param("certificationId"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "certification not found"})
+			return
+		}
+		if _, err := h.Repo.GetCertification(employee.IdentityNumber, id); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "certification not found"})
+			return
+		}
+
+		if err := h.Repo.DeleteCertification(id); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete certification"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Certification deleted"})
+	}
+}
+
+func (h *EmployeeHandler) GetLapsingCertifications() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+		if err != nil || days < 0 || days > 3650 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 0 and 3650"})
+			return
+		}
+
+		certifications, err := h.Repo.GetLapsingCertifications(v.UserID, days, c.Query("flagged") == "true")
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch certifications"})
+			return
+		}
+
+		c.JSON(http.StatusOK, certifications)
+	}
+}
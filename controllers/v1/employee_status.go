@@ -0,0 +1,163 @@
+package v1
+
+import (
+	"go-go-manager/models"
+	"go-go-manager/repositories"
+	"go-go-manager/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatusTransitionRequest struct {
+	Status        models.EmploymentStatus `json:"status" binding:"required"`
+	EffectiveDate string                  `json:"effectiveDate" binding:"required,datetime=2006-01-02"`
+	Reason        string                  `json:"reason" binding:"required,max=255"`
+
+	// Only allowed on terminations; those without a type count as unclassified
+	// in the turnover metrics
+	TerminationType *models.TerminationType `json:"terminationType" binding:"omitempty,oneof=voluntary involuntary"`
+	Regretted       *bool                   `json:"regretted"`
+}
+
+func (h *EmployeeHandler) TransitionEmployeeStatus() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		if c.GetHeader("Content-Type") != "application/json" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing content-type"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		identityNumber := c.Param("identityNumber")
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(identityNumber)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		var req StatusTransitionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if !req.Status.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status value"})
+			return
+		}
+
+		if req.Status != models.StatusTerminated && (req.TerminationType != nil || req.Regretted != nil) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "terminationType and regretted are only allowed when terminating"})
+			return
+		}
+
+		latest, err := h.Repo.GetLatestTransition(identityNumber)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch employee status"})
+			return
+		}
+
+		transition := models.StatusTransition{
+			IdentityNumber:  identityNumber,
+			ToStatus:        req.Status,
+			EffectiveDate:   req.EffectiveDate,
+			Reason:          req.Reason,
+			TerminationType: req.TerminationType,
+		}
+		if req.Regretted != nil {
+			transition.Regretted = *req.Regretted
+		}
+
+		if latest != nil {
+			if !latest.ToStatus.CanTransitionTo(req.Status) {
+				c.JSON(http.StatusConflict, gin.H{"error": "Cannot change status from " + string(latest.ToStatus) + " to " + string(req.Status)})
+				return
+			}
+
+			// Transitions must be recorded in order so the timeline stays unambiguous
+			effectiveDate, _ := time.Parse(time.DateOnly, req.EffectiveDate)
+			latestDate, _ := time.Parse(time.DateOnly, latest.EffectiveDate)
+			if effectiveDate.Before(latestDate) {
+				c.JSON(http.StatusConflict, gin.H{"error": "effectiveDate cannot be before the previous transition on " + latest.EffectiveDate})
+				return
+			}
+
+			transition.FromStatus = latest.ToStatus
+		}
+
+		// A termination starts the offboarding, due from the termination date
+		err = h.Repo.Transaction(func(txRepo *repositories.EmployeeRepository) error {
+			if transition, err = txRepo.AddStatusTransition(transition); err != nil {
+				return err
+			}
+			if req.Status != models.StatusTerminated {
+				return nil
+			}
+			effectiveDate, _ := time.Parse(time.DateOnly, req.EffectiveDate)
+			return startOffboarding(txRepo, v.UserID, *employee, effectiveDate)
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update employee status", "details": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusCreated, transition)
+	}
+}
+
+func (h *EmployeeHandler) GetEmployeeStatusHistory() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		identityNumber := c.Param("identityNumber")
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(identityNumber)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		transitions, err := h.Repo.GetStatusTransitions(identityNumber)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch employee status"})
+			return
+		}
+
+		c.JSON(http.StatusOK, transitions)
+	}
+}
@@ -0,0 +1,142 @@
+package v1
+
+import (
+	"go-go-manager/models"
+	"go-go-manager/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TransferRequest struct {
+	DepartmentID  string  `json:"departmentId" binding:"required"`
+	EffectiveDate string  `json:"effectiveDate" binding:"required,datetime=2006-01-02"`
+	Reason        string  `json:"reason" binding:"required,max=255"`
+	Approver      *string `json:"approver" binding:"omitempty,min=1,max=100"`
+}
+
+func (h *EmployeeHandler) TransferEmployee() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		if c.GetHeader("Content-Type") != "application/json" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing content-type"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		identityNumber := c.Param("identityNumber")
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(identityNumber)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		var req TransferRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		_, err = models.FindDepartmentById(v.UserID, req.DepartmentID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Department ID"})
+			return
+		}
+
+		if req.DepartmentID == employee.DepartmentID {
+			c.JSON(http.StatusConflict, gin.H{"error": "Employee is already in this department"})
+			return
+		}
+
+		// The move is applied immediately, so it cannot be scheduled ahead
+		effectiveDate, _ := time.Parse(time.DateOnly, req.EffectiveDate)
+		if effectiveDate.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "effectiveDate cannot be in the future"})
+			return
+		}
+
+		latest, err := h.Repo.GetLatestTransfer(identityNumber)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch employee transfers"})
+			return
+		}
+		if latest != nil {
+			latestDate, _ := time.Parse(time.DateOnly, latest.EffectiveDate)
+			if effectiveDate.Before(latestDate) {
+				c.JSON(http.StatusConflict, gin.H{"error": "effectiveDate cannot be before the previous transfer on " + latest.EffectiveDate})
+				return
+			}
+		}
+
+		transfer, err := h.Repo.TransferEmployee(models.EmployeeTransfer{
+			IdentityNumber:   identityNumber,
+			FromDepartmentID: employee.DepartmentID,
+			ToDepartmentID:   req.DepartmentID,
+			EffectiveDate:    req.EffectiveDate,
+			Reason:           req.Reason,
+			Approver:         req.Approver,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to transfer employee", "details": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusCreated, transfer)
+	}
+}
+
+func (h *EmployeeHandler) GetEmployeeTimeline() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		identityNumber := c.Param("identityNumber")
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(identityNumber)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		events, err := h.Repo.GetTimeline(identityNumber)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch employee timeline"})
+			return
+		}
+
+		c.JSON(http.StatusOK, events)
+	}
+}
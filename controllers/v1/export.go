@@ -0,0 +1,132 @@
+package v1
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+)
+
+var exportContentTypes = map[string]string{
+	"csv":    "text/csv",
+	"xlsx":   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"ndjson": "application/x-ndjson",
+}
+
+// exportWriter writes one row at a time in the requested format. CSV and XLSX
+// use the flat record, NDJSON encodes the row value itself.
+type exportWriter interface {
+	Write(row interface{}, record []string) error
+	Close() error
+}
+
+// startExport validates the format, sets the download headers and returns a
+// writer for the response body.
+func startExport(c *gin.Context, name string, columns []string) (exportWriter, error) {
+	format := c.DefaultQuery("format", "csv")
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		return nil, errors.New("format must be csv, xlsx or ndjson")
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102-150405"), format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(200)
+
+	switch format {
+	case "xlsx":
+		return newXLSXExportWriter(c.Writer, columns)
+	case "ndjson":
+		return &ndjsonExportWriter{encoder: json.NewEncoder(c.Writer)}, nil
+	default:
+		return newCSVExportWriter(c.Writer, columns)
+	}
+}
+
+type csvExportWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVExportWriter(w io.Writer, columns []string) (*csvExportWriter, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return nil, err
+	}
+	return &csvExportWriter{writer: writer}, nil
+}
+
+func (w *csvExportWriter) Write(_ interface{}, record []string) error {
+	return w.writer.Write(record)
+}
+
+func (w *csvExportWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type ndjsonExportWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *ndjsonExportWriter) Write(row interface{}, _ []string) error {
+	return w.encoder.Encode(row)
+}
+
+func (w *ndjsonExportWriter) Close() error {
+	return nil
+}
+
+// xlsxExportWriter uses the excelize stream writer, which spills rows to a
+// temporary file instead of keeping the whole sheet in memory. Close zips the
+// workbook straight into the response without buffering it first.
+type xlsxExportWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXExportWriter(w io.Writer, columns []string) (*xlsxExportWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter(file.GetSheetName(0))
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	writer := &xlsxExportWriter{out: w, file: file, stream: stream}
+	if err := writer.Write(nil, columns); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (w *xlsxExportWriter) Write(_ interface{}, record []string) error {
+	w.row++
+	cell, err := excelize.CoordinatesToCellName(1, w.row)
+	if err != nil {
+		return err
+	}
+
+	values := make([]interface{}, len(record))
+	for i, value := range record {
+		values[i] = value
+	}
+	return w.stream.SetRow(cell, values)
+}
+
+func (w *xlsxExportWriter) Close() error {
+	defer w.file.Close()
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+	_, err := w.file.WriteTo(w.out)
+	return err
+}
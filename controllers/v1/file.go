@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"go-go-manager/config"
 	"go-go-manager/utils"
+	"io"
 	"log"
 	"mime/multipart"
 	"net/http"
@@ -141,6 +142,43 @@ func (h *FileHandler) uploadToS3(email string, fileHeader *multipart.FileHeader)
 	return s3URI, nil
 }
 
+// putObject stores the uploaded file under key without making it public.
+func (h *FileHandler) putObject(key string, contentType string, fileHeader *multipart.FileHeader) error {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = h.uploader.Upload(context.TODO(), &s3.PutObjectInput{
+		Bucket:      &h.s3Bucket,
+		Key:         &key,
+		Body:        file,
+		ContentType: &contentType,
+	})
+	return err
+}
+
+// getObject opens the object stored under key. The caller must close it.
+func (h *FileHandler) getObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := h.s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: &h.s3Bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+func (h *FileHandler) deleteObject(key string) error {
+	_, err := h.s3Client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
+		Bucket: &h.s3Bucket,
+		Key:    &key,
+	})
+	return err
+}
+
 func isValidFileType(filename string) bool {
 	ext := strings.ToLower(strings.TrimSpace(filename[strings.LastIndex(filename, ".")+1:]))
 	return ext == "jpeg" || ext == "jpg" || ext == "png"
@@ -154,6 +192,8 @@ func getContentType(filename string) string {
 		"png":  "image/png",
 		"gif":  "image/gif",
 		"pdf":  "application/pdf",
+		"doc":  "application/msword",
+		"docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
 		"txt":  "text/plain",
 	}
 
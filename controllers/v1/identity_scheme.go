@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"go-go-manager/models"
+	"go-go-manager/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IdentitySchemeRequest struct {
+	Scheme string `json:"scheme" binding:"required"`
+}
+
+// checkIdentityNumber validates the employee's identity number against the
+// tenant's scheme. Records that already exist are only checked when the
+// identity number or gender changes, so switching schemes does not lock
+// earlier employees out of unrelated updates.
+func checkIdentityNumber(scheme string, employee models.Employee, existing *models.Employee) error {
+	if existing != nil && existing.IdentityNumber == employee.IdentityNumber && existing.Gender == employee.Gender {
+		return nil
+	}
+	return utils.CheckIdentityNumber(scheme, employee.IdentityNumber, utils.IdentityHolder{Gender: string(employee.Gender)})
+}
+
+func GetIdentityScheme(c *gin.Context) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+
+	if !strings.HasPrefix(auth, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		return
+	}
+
+	auth = auth[7:]
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	scheme, err := models.FindIdentityScheme(v.UserID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"scheme":    scheme,
+		"available": utils.IdentitySchemes(),
+	})
+}
+
+func UpdateIdentityScheme(c *gin.Context) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+
+	if !strings.HasPrefix(auth, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		return
+	}
+
+	auth = auth[7:]
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !requireRole(c, v, models.RoleAdmin) {
+		return
+	}
+
+	var req IdentitySchemeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+		return
+	}
+
+	if !utils.IsIdentityScheme(req.Scheme) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown identity scheme", "details": utils.IdentitySchemes()})
+		return
+	}
+
+	if err := models.UpdateIdentityScheme(v.UserID, req.Scheme); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update identity scheme"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"scheme":    req.Scheme,
+		"available": utils.IdentitySchemes(),
+	})
+}
@@ -0,0 +1,485 @@
+package v1
+
+import (
+	"database/sql"
+	"errors"
+	"go-go-manager/models"
+	"go-go-manager/repositories"
+	"go-go-manager/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LeaveRequestBody struct {
+	LeaveTypeID int    `json:"leaveTypeId" binding:"required"`
+	StartDate   string `json:"startDate" binding:"required,datetime=2006-01-02"`
+	EndDate     string `json:"endDate" binding:"required,datetime=2006-01-02"`
+	Reason      string `json:"reason" binding:"max=255"`
+}
+
+// LeaveDecisionRequest is the optional body of a leave decision. The decision
+// is recorded as taken by whoever the token belongs to.
+type LeaveDecisionRequest struct {
+	Note *string `json:"note" binding:"omitempty,max=255"`
+}
+
+func (h *EmployeeHandler) RequestLeave() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		if c.GetHeader("Content-Type") != "application/json" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing content-type"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		identityNumber := c.Param("identityNumber")
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(identityNumber)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		var req LeaveRequestBody
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		leaveType, err := models.FindLeaveTypeById(v.UserID, strconv.Itoa(req.LeaveTypeID))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid leave type"})
+			return
+		}
+
+		startDate, _ := time.Parse(time.DateOnly, req.StartDate)
+		endDate, _ := time.Parse(time.DateOnly, req.EndDate)
+		if endDate.Before(startDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "endDate cannot be before startDate"})
+			return
+		}
+
+		days := models.CountLeaveDays(startDate, endDate)
+		if days == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Leave must include at least one working day"})
+			return
+		}
+
+		// Balances are kept per calendar year, so leave running into the next
+		// year is checked against each year's balance for its own days
+		if leaveType.Accrual != models.AccrualNone {
+			for year := startDate.Year(); year <= endDate.Year(); year++ {
+				from := maxTime(startDate, time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC))
+				to := minTime(endDate, time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC))
+				yearDays := models.CountLeaveDays(from, to)
+				if yearDays == 0 {
+					continue
+				}
+
+				balances, err := h.Repo.GetLeaveBalances(identityNumber, []models.LeaveType{leaveType}, year, balanceDate(year))
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch leave balance"})
+					return
+				}
+				if available := *balances[0].Available; yearDays > available {
+					c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Insufficient leave balance", "year": year, "requested": yearDays, "available": available})
+					return
+				}
+			}
+		}
+
+		leave, err := h.Repo.CreateLeaveRequest(models.LeaveRequest{
+			IdentityNumber: identityNumber,
+			LeaveTypeID:    leaveType.ID,
+			StartDate:      req.StartDate,
+			EndDate:        req.EndDate,
+			Days:           days,
+			Reason:         req.Reason,
+		})
+		if errors.Is(err, repositories.ErrLeaveOverlap) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Leave overlaps another pending or approved request"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to request leave", "details": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusCreated, leave)
+	}
+}
+
+// balanceDate is the date monthly accrual is counted up to: today for the
+// current year and the end of the year for any other year.
+func balanceDate(year int) time.Time {
+	now := time.Now()
+	if year == now.Year() {
+		return now
+	}
+	return time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+}
+
+func minTime(a time.Time, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a time.Time, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func (h *EmployeeHandler) GetEmployeeLeave() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		identityNumber := c.Param("identityNumber")
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(identityNumber)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		filters := map[string]string{"identityNumber": identityNumber}
+		if status := c.Query("status"); status != "" {
+			filters["status"] = status
+		}
+
+		requests, err := h.Repo.GetLeaveRequests(filters)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch leave requests"})
+			return
+		}
+
+		c.JSON(http.StatusOK, requests)
+	}
+}
+
+func (h *EmployeeHandler) GetLeaveBalances() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		identityNumber := c.Param("identityNumber")
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(identityNumber)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		year := time.Now().Year()
+		if yearStr := c.Query("year"); yearStr != "" {
+			year, err = strconv.Atoi(yearStr)
+			if err != nil || year < 1900 || year > 9999 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "year must be a four-digit year"})
+				return
+			}
+		}
+
+		leaveTypes, err := models.GetLeaveTypes(v.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch leave types"})
+			return
+		}
+
+		balances, err := h.Repo.GetLeaveBalances(identityNumber, leaveTypes, year, balanceDate(year))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch leave balance"})
+			return
+		}
+
+		c.JSON(http.StatusOK, balances)
+	}
+}
+
+func (h *EmployeeHandler) GetLeaveRequests() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		filters := map[string]string{"userId": strconv.Itoa(int(v.UserID))}
+		if status := c.Query("status"); status != "" {
+			filters["status"] = status
+		}
+		if departmentID := c.Query("departmentId"); departmentID != "" {
+			filters["departmentId"] = departmentID
+		}
+		if identityNumber := c.Query("identityNumber"); identityNumber != "" {
+			filters["identityNumber"] = identityNumber
+		}
+
+		requests, err := h.Repo.GetLeaveRequests(filters)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch leave requests"})
+			return
+		}
+
+		c.JSON(http.StatusOK, requests)
+	}
+}
+
+// DecideLeave moves a leave request to status on behalf of an admin; heads
+// of department decide through DecideLeaveAsHead. Pending requests can be
+// approved or rejected; pending and approved requests can be cancelled until
+// the leave starts.
+func (h *EmployeeHandler) DecideLeave(status models.LeaveStatus) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		if !requireRole(c, v, models.RoleAdmin) {
+			return
+		}
+
+		leaveID, err := strconv.Atoi(c.Param("leaveId"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Leave request not found"})
+			return
+		}
+
+		leave, err := h.Repo.GetLeaveRequest(leaveID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Leave request not found"})
+			return
+		}
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(leave.IdentityNumber)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Leave request not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Leave request not found"})
+			return
+		}
+
+		h.decideLeave(c, *leave, status, v.Email)
+	}
+}
+
+// DecideLeaveAsHead lets department heads approve or reject the leave of the
+// employees in their department with their self-service token. The decision
+// is recorded under the head's identity number.
+func (h *EmployeeHandler) DecideLeaveAsHead(status models.LeaveStatus) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		account, approver, ok := h.selfEmployee(c)
+		if !ok {
+			return
+		}
+
+		leaveID, err := strconv.Atoi(c.Param("leaveId"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Leave request not found"})
+			return
+		}
+
+		leave, err := h.Repo.GetLeaveRequest(leaveID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Leave request not found"})
+			return
+		}
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(leave.IdentityNumber)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Leave request not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(account.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Leave request not found"})
+			return
+		}
+
+		head, err := models.FindDepartmentHead(employee.DepartmentID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch department head"})
+			return
+		}
+		if head == nil || *head != approver.IdentityNumber {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the department head or an admin can decide on leave"})
+			return
+		}
+		if leave.IdentityNumber == approver.IdentityNumber {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Department heads cannot decide on their own leave"})
+			return
+		}
+
+		h.decideLeave(c, *leave, status, approver.IdentityNumber)
+	}
+}
+
+// decideLeave applies a decision by decidedBy once the caller has checked
+// they may take it.
+func (h *EmployeeHandler) decideLeave(c *gin.Context, leave models.LeaveRequest, status models.LeaveStatus, decidedBy string) {
+	// The body is optional
+	var req LeaveDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	from := []models.LeaveStatus{models.LeavePending}
+	if status == models.LeaveCancelled {
+		startDate, _ := time.Parse(time.DateOnly, leave.StartDate)
+		if leave.Status == models.LeaveApproved && !startDate.After(time.Now()) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Leave that has already started cannot be cancelled"})
+			return
+		}
+		from = append(from, models.LeaveApproved)
+	}
+
+	decided, err := h.Repo.DecideLeaveRequest(leave.ID, from, status, decidedBy, req.Note)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusConflict, gin.H{"error": "Leave request is already " + string(leave.Status)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update leave request", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, decided)
+}
+
+func (h *EmployeeHandler) GetLeaveCalendar() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Defaults to the current month
+		now := time.Now()
+		from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		to := from.AddDate(0, 1, -1)
+
+		if fromStr := c.Query("from"); fromStr != "" {
+			if from, err = time.Parse(time.DateOnly, fromStr); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date (YYYY-MM-DD)"})
+				return
+			}
+		}
+		if toStr := c.Query("to"); toStr != "" {
+			if to, err = time.Parse(time.DateOnly, toStr); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date (YYYY-MM-DD)"})
+				return
+			}
+		}
+		if to.Before(from) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to cannot be before from"})
+			return
+		}
+		if to.Sub(from) > 366*24*time.Hour {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "The calendar covers at most one year"})
+			return
+		}
+
+		filters := map[string]string{}
+		if departmentID := c.Query("departmentId"); departmentID != "" {
+			filters["departmentId"] = departmentID
+		}
+		if c.Query("includePending") == "true" {
+			filters["includePending"] = "true"
+		}
+
+		entries, err := h.Repo.GetLeaveCalendar(v.UserID, from.Format(time.DateOnly), to.Format(time.DateOnly), filters)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch leave calendar"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"from":    from.Format(time.DateOnly),
+			"to":      to.Format(time.DateOnly),
+			"entries": entries,
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package v1
+
+import (
+	"go-go-manager/models"
+	"go-go-manager/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+func CreateLeaveType(c *gin.Context) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+
+	if !strings.HasPrefix(auth, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		return
+	}
+
+	if c.GetHeader("Content-Type") != "application/json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing content-type"})
+		return
+	}
+
+	auth = auth[7:]
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req models.LeaveType
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+		return
+	}
+
+	if _, err := models.FindLeaveTypeByName(v.UserID, req.Name); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Leave type already exists"})
+		return
+	}
+
+	leaveType, err := models.CreateLeaveType(v.UserID, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create leave type"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, leaveType)
+}
+
+func GetLeaveTypes(c *gin.Context) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+
+	if !strings.HasPrefix(auth, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		return
+	}
+
+	auth = auth[7:]
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	leaveTypes, err := models.GetLeaveTypes(v.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch leave types"})
+		return
+	}
+
+	c.JSON(http.StatusOK, leaveTypes)
+}
+
+func UpdateLeaveType(c *gin.Context) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+
+	if !strings.HasPrefix(auth, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		return
+	}
+
+	auth = auth[7:]
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	existing, err := models.FindLeaveTypeById(v.UserID, c.Param("leaveTypeId"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Leave type not found"})
+		return
+	}
+
+	var updated models.LeaveType
+	if status, err := applyPatch(c, existing, &updated); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	updated.ID = existing.ID
+
+	if err := binding.Validator.ValidateStruct(&updated); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+		return
+	}
+
+	if other, err := models.FindLeaveTypeByName(v.UserID, updated.Name); err == nil && other.ID != existing.ID {
+		c.JSON(http.StatusConflict, gin.H{"error": "Leave type already exists"})
+		return
+	}
+
+	leaveType, err := models.UpdateLeaveType(v.UserID, updated)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update leave type"})
+		return
+	}
+
+	c.JSON(http.StatusOK, leaveType)
+}
@@ -0,0 +1,149 @@
+package v1
+
+import (
+	"go-go-manager/models"
+	"go-go-manager/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MemberRequest adds an account to the admin's tenant. The account signs in
+// at POST /auth like the tenant's owner.
+type MemberRequest struct {
+	Email    string      `json:"email" binding:"required,email"`
+	Password string      `json:"password" binding:"required,min=8,max=32"`
+	Role     models.Role `json:"role" binding:"required,oneof=admin hr manager"`
+}
+
+type MemberRoleRequest struct {
+	Role models.Role `json:"role" binding:"required,oneof=admin hr manager"`
+}
+
+// memberAdmin validates the token and checks that the account is an admin.
+func memberAdmin(c *gin.Context) (*utils.Claims, bool) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return nil, false
+	}
+
+	if !strings.HasPrefix(auth, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		return nil, false
+	}
+
+	auth = auth[7:]
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	if !requireRole(c, v, models.RoleAdmin) {
+		return nil, false
+	}
+	return v, true
+}
+
+func GetMembers(c *gin.Context) {
+	v, ok := memberAdmin(c)
+	if !ok {
+		return
+	}
+
+	members, err := models.GetMembers(v.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch members"})
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+func CreateMember(c *gin.Context) {
+	v, ok := memberAdmin(c)
+	if !ok {
+		return
+	}
+
+	var req MemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+		return
+	}
+
+	if _, err := models.FindUserByEmail(req.Email); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Email already exists"})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hashing password"})
+		return
+	}
+
+	member, err := models.CreateMember(v.UserID, req.Email, string(hashedPassword), req.Role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create member"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+func UpdateMember(c *gin.Context) {
+	v, ok := memberAdmin(c)
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.ParseUint(c.Param("memberId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+		return
+	}
+
+	var req MemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+		return
+	}
+
+	member, err := models.UpdateMemberRole(v.UserID, uint(memberID), req.Role)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+func DeleteMember(c *gin.Context) {
+	v, ok := memberAdmin(c)
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.ParseUint(c.Param("memberId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+		return
+	}
+
+	deleted, err := models.DeleteMember(v.UserID, uint(memberID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete member"})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member deleted"})
+}
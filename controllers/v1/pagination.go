@@ -0,0 +1,112 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"go-go-manager/utils"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxPageLimit bounds the limit query parameter of the list endpoints.
+const maxPageLimit = 100
+
+// pageFromQuery reads the sort, cursor, limit, offset and withTotal query
+// parameters shared by the list endpoints. A limit that is not positive falls
+// back to the default and one above maxPageLimit is clamped to it. GET
+// /employee used to return every employee for limit=0; clients that need the
+// whole list now follow the next link instead.
+func pageFromQuery(c *gin.Context, keyset utils.Keyset, defaultSort string, defaultLimit int) (utils.Page, error) {
+	page := utils.Page{Sort: c.Query("sort"), Limit: defaultLimit}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			page.Limit = min(parsedLimit, maxPageLimit)
+		}
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			page.Offset = parsedOffset
+		}
+	}
+
+	if token := c.Query("cursor"); token != "" {
+		cursor, err := utils.DecodeCursor(token)
+		if err != nil {
+			return page, err
+		}
+		// A cursor carries its sort, so clients only need to pass it along
+		if page.Sort == "" {
+			page.Sort = cursor.Sort
+		}
+		page.Cursor = &cursor
+	}
+
+	if page.Sort == "" {
+		page.Sort = defaultSort
+	}
+	if !keyset.Allows(page.Field()) {
+		return page, fmt.Errorf("sort must be one of %s", strings.Join(sortFields(keyset), ", "))
+	}
+	if page.Cursor != nil && page.Cursor.Sort != page.Sort {
+		return page, errors.New("cursor does not match the requested sort")
+	}
+
+	page.WithTotal = c.Query("withTotal") == "true"
+	return page, nil
+}
+
+func sortFields(keyset utils.Keyset) []string {
+	fields := make([]string, 0, len(keyset.Columns))
+	for field := range keyset.Columns {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// writePage responds with the page as a plain array, as before, with the
+// neighbouring pages in a Link header and the total in X-Total-Count. With
+// envelope=true the same information is returned in the body instead.
+func writePage(c *gin.Context, data interface{}, info utils.PageInfo) {
+	links := []string{}
+	for _, link := range [][2]string{{"next", info.Next}, {"prev", info.Prev}} {
+		rel, cursor := link[0], link[1]
+		if cursor == "" {
+			continue
+		}
+		u := *c.Request.URL
+		query := u.Query()
+		query.Set("cursor", cursor)
+		query.Del("offset")
+		u.RawQuery = query.Encode()
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel))
+	}
+	if len(links) > 0 {
+		c.Header("Link", strings.Join(links, ", "))
+	}
+	if info.Total != nil {
+		c.Header("X-Total-Count", strconv.Itoa(*info.Total))
+	}
+
+	if c.Query("envelope") != "true" {
+		c.JSON(http.StatusOK, data)
+		return
+	}
+
+	envelope := gin.H{"data": data, "next": nil, "prev": nil}
+	if info.Next != "" {
+		envelope["next"] = info.Next
+	}
+	if info.Prev != "" {
+		envelope["prev"] = info.Prev
+	}
+	if info.Total != nil {
+		envelope["total"] = *info.Total
+	}
+	c.JSON(http.StatusOK, envelope)
+}
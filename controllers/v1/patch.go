@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
+)
+
+// applyPatch applies the request body to original and decodes the result into
+// target. A plain JSON body is treated as an RFC 7396 merge patch, so clients
+// only send the fields they change; RFC 6902 JSON Patch is also accepted. It
+// returns the status code to respond with when the patch cannot be applied.
+func applyPatch(c *gin.Context, original interface{}, target interface{}) (int, error) {
+	contentType := c.GetHeader("Content-Type")
+	if contentType == "" {
+		return http.StatusBadRequest, errors.New("Missing content-type")
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return http.StatusBadRequest, errors.New("Invalid content-type")
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return http.StatusBadRequest, errors.New("Invalid request body")
+	}
+
+	originalJSON, err := json.Marshal(original)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	var patched []byte
+	switch mediaType {
+	case "application/json", mergePatchContentType:
+		if !json.Valid(body) {
+			return http.StatusBadRequest, errors.New("Invalid request body")
+		}
+		patched, err = jsonpatch.MergePatch(originalJSON, body)
+	case jsonPatchContentType:
+		var patch jsonpatch.Patch
+		patch, err = jsonpatch.DecodePatch(body)
+		if err == nil {
+			patched, err = patch.Apply(originalJSON)
+		}
+	default:
+		return http.StatusUnsupportedMediaType, errors.New("Unsupported content-type")
+	}
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	if err := json.Unmarshal(patched, target); err != nil {
+		return http.StatusBadRequest, errors.New("Invalid request body")
+	}
+
+	return http.StatusOK, nil
+}
@@ -0,0 +1,171 @@
+package v1
+
+import (
+	"go-go-manager/models"
+	"go-go-manager/repositories"
+	"go-go-manager/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// personalInfoRoles may read and change home addresses and emergency contacts.
+var personalInfoRoles = []models.Role{models.RoleAdmin, models.RoleHR}
+
+func (h *EmployeeHandler) GetPersonalInfo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		if !requireRole(c, v, personalInfoRoles...) {
+			return
+		}
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(c.Param("identityNumber"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		// The read is only served once it has been logged
+		var info models.PersonalInfo
+		err = h.Repo.Transaction(func(txRepo *repositories.EmployeeRepository) error {
+			if err := txRepo.LogPersonalInfoAccess(v.UserID, employee.IdentityNumber, v.Email, models.PersonalInfoRead); err != nil {
+				return err
+			}
+			info, err = txRepo.GetPersonalInfo(employee.IdentityNumber)
+			return err
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch personal information"})
+			return
+		}
+
+		c.JSON(http.StatusOK, info)
+	}
+}
+
+func (h *EmployeeHandler) UpdatePersonalInfo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		if !requireRole(c, v, personalInfoRoles...) {
+			return
+		}
+
+		var req models.PersonalInfo
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(c.Param("identityNumber"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		req.IdentityNumber = employee.IdentityNumber
+		var info models.PersonalInfo
+		err = h.Repo.Transaction(func(txRepo *repositories.EmployeeRepository) error {
+			if err := txRepo.LogPersonalInfoAccess(v.UserID, employee.IdentityNumber, v.Email, models.PersonalInfoUpdate); err != nil {
+				return err
+			}
+			info, err = txRepo.SavePersonalInfo(req)
+			return err
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save personal information"})
+			return
+		}
+
+		c.JSON(http.StatusOK, info)
+	}
+}
+
+func (h *EmployeeHandler) GetPersonalInfoAccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Only admins audit who looked at personal information
+		if !requireRole(c, v, models.RoleAdmin) {
+			return
+		}
+
+		filters := map[string]string{"userId": strconv.Itoa(int(v.UserID))}
+		if identityNumber := c.Query("identityNumber"); identityNumber != "" {
+			filters["identityNumber"] = identityNumber
+		}
+		if actor := c.Query("actor"); actor != "" {
+			filters["actor"] = actor
+		}
+		if action := c.Query("action"); action != "" {
+			if action != string(models.PersonalInfoRead) && action != string(models.PersonalInfoUpdate) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "action must be read or update"})
+				return
+			}
+			filters["action"] = action
+		}
+
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+		if err != nil || limit <= 0 || limit > 500 {
+			limit = 50
+		}
+		offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+		if err != nil || offset < 0 {
+			offset = 0
+		}
+
+		entries, err := h.Repo.GetPersonalInfoAccessLog(filters, limit, offset)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch access log"})
+			return
+		}
+
+		c.JSON(http.StatusOK, entries)
+	}
+}
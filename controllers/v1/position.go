@@ -0,0 +1,397 @@
+package v1
+
+import (
+	"errors"
+	"go-go-manager/models"
+	"go-go-manager/repositories"
+	"go-go-manager/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// checkPositionHolder writes the error response and returns false when the
+// employee cannot fill a position of departmentID: they must work in that
+// department and not be terminated.
+func (h *EmployeeHandler) checkPositionHolder(c *gin.Context, userID uint, identityNumber string, departmentID string) bool {
+	ok, err := models.IsTenantEmployee(userID, identityNumber)
+	if err != nil || !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid identity number"})
+		return false
+	}
+
+	employee, err := h.Repo.GetEmployeeByIdentityNumber(identityNumber)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid identity number"})
+		return false
+	}
+	if employee.DepartmentID != departmentID {
+		c.JSON(http.StatusConflict, gin.H{"error": "Employee is not in the position's department"})
+		return false
+	}
+
+	latest, err := h.Repo.GetLatestTransition(identityNumber)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch employee status"})
+		return false
+	}
+	if latest != nil && latest.ToStatus == models.StatusTerminated {
+		c.JSON(http.StatusConflict, gin.H{"error": "A terminated employee cannot fill a position"})
+		return false
+	}
+
+	return true
+}
+
+func (h *EmployeeHandler) CreatePosition() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		if c.GetHeader("Content-Type") != "application/json" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing content-type"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		var req models.Position
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+
+		if _, err := models.FindDepartmentById(v.UserID, req.DepartmentID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Department ID"})
+			return
+		}
+		if req.IdentityNumber != nil && !h.checkPositionHolder(c, v.UserID, *req.IdentityNumber, req.DepartmentID) {
+			return
+		}
+
+		position, err := h.Repo.CreatePosition(v.UserID, req)
+		if err != nil {
+			if errors.Is(err, repositories.ErrEmployeeHasPosition) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create position"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, position)
+	}
+}
+
+func (h *EmployeeHandler) GetPositions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		filters := map[string]string{"userId": strconv.Itoa(int(v.UserID))}
+		if departmentID := c.Query("departmentId"); departmentID != "" {
+			filters["departmentId"] = departmentID
+		}
+		if status := c.Query("status"); status != "" {
+			if status != "filled" && status != "vacant" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "status must be filled or vacant"})
+				return
+			}
+			filters["status"] = status
+		}
+
+		positions, err := h.Repo.GetPositions(filters)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch positions"})
+			return
+		}
+
+		c.JSON(http.StatusOK, positions)
+	}
+}
+
+func (h *EmployeeHandler) GetPosition() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		positionID, err := strconv.Atoi(c.Param("positionId"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Position not found"})
+			return
+		}
+
+		position, err := h.Repo.GetPosition(v.UserID, positionID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Position not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, position)
+	}
+}
+
+// UpdatePosition also fills and vacates the position: identityNumber sets the
+// employee in it and null leaves it vacant.
+func (h *EmployeeHandler) UpdatePosition() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		positionID, err := strconv.Atoi(c.Param("positionId"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Position not found"})
+			return
+		}
+		existing, err := h.Repo.GetPosition(v.UserID, positionID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Position not found"})
+			return
+		}
+
+		var updated models.Position
+		if status, err := applyPatch(c, existing, &updated); err != nil {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		updated.ID = existing.ID
+
+		if err := binding.Validator.ValidateStruct(&updated); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+
+		if updated.DepartmentID != existing.DepartmentID {
+			if _, err := models.FindDepartmentById(v.UserID, updated.DepartmentID); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Department ID"})
+				return
+			}
+		}
+		if updated.IdentityNumber != nil && !h.checkPositionHolder(c, v.UserID, *updated.IdentityNumber, updated.DepartmentID) {
+			return
+		}
+
+		position, err := h.Repo.UpdatePosition(v.UserID, updated)
+		if err != nil {
+			if errors.Is(err, repositories.ErrEmployeeHasPosition) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update position"})
+			return
+		}
+
+		c.JSON(http.StatusOK, position)
+	}
+}
+
+func (h *EmployeeHandler) DeletePosition() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		positionID, err := strconv.Atoi(c.Param("positionId"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Position not found"})
+			return
+		}
+		if _, err := h.Repo.GetPosition(v.UserID, positionID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Position not found"})
+			return
+		}
+
+		if err := h.Repo.DeletePosition(v.UserID, positionID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete position"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Position deleted successfully"})
+	}
+}
+
+func (h *EmployeeHandler) GetVacancies() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		vacancies, err := h.Repo.GetVacancies(v.UserID, c.Query("departmentId"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch vacancies"})
+			return
+		}
+
+		c.JSON(http.StatusOK, vacancies)
+	}
+}
+
+func (h *EmployeeHandler) GetStaffingAnalytics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		departments, err := h.Repo.GetStaffing(v.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute staffing"})
+			return
+		}
+
+		c.JSON(http.StatusOK, departments)
+	}
+}
+
+func SaveHeadcountPlan(c *gin.Context) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+
+	if !strings.HasPrefix(auth, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		return
+	}
+
+	if c.GetHeader("Content-Type") != "application/json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing content-type"})
+		return
+	}
+
+	auth = auth[7:]
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	departmentID := c.Param("departmentId")
+	if _, err := models.FindDepartmentById(v.UserID, departmentID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
+		return
+	}
+
+	var req models.HeadcountPlan
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+		return
+	}
+	req.DepartmentID = departmentID
+
+	plan, err := models.SaveHeadcountPlan(req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save headcount plan"})
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+func DeleteHeadcountPlan(c *gin.Context) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+
+	if !strings.HasPrefix(auth, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		return
+	}
+
+	auth = auth[7:]
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	departmentID := c.Param("departmentId")
+	if _, err := models.FindDepartmentById(v.UserID, departmentID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
+		return
+	}
+
+	if err := models.DeleteHeadcountPlan(departmentID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete headcount plan"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Headcount plan deleted successfully"})
+}
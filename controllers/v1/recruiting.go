@@ -0,0 +1,662 @@
+package v1
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-go-manager/models"
+	"go-go-manager/repositories"
+	"go-go-manager/utils"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// recruitingRoles may manage openings and candidates, whose CVs and notes are
+// personal data.
+var recruitingRoles = []models.Role{models.RoleAdmin, models.RoleHR}
+
+type RecruitingHandler struct {
+	Repo  *repositories.EmployeeRepository
+	Files *FileHandler
+}
+
+func NewRecruitingHandler(db *sql.DB, files *FileHandler) *RecruitingHandler {
+	return &RecruitingHandler{
+		Repo:  repositories.NewEmployeeRepository(db),
+		Files: files,
+	}
+}
+
+type CandidateStageRequest struct {
+	Stage models.CandidateStage `json:"stage" binding:"required"`
+}
+
+type CandidateCVUploadRequest struct {
+	File *multipart.FileHeader `form:"file" binding:"required"`
+}
+
+// HireRequest completes the candidate's details into an employee. Name and
+// phone come from the candidate.
+type HireRequest struct {
+	IdentityNumber   string                 `json:"identityNumber" binding:"required"`
+	Gender           models.Gender          `json:"gender" binding:"required"`
+	DepartmentID     string                 `json:"departmentId"` // Defaults to the opening's department
+	EmployeeImageURI string                 `json:"employeeImageUri" binding:"required"`
+	CustomFields     map[string]interface{} `json:"customFields"`
+}
+
+func isValidCVType(filename string) bool {
+	switch strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), ".")) {
+	case "pdf", "doc", "docx":
+		return true
+	}
+	return false
+}
+
+// candidateCVKey builds a storage key that is unique even when the same file is uploaded twice.
+func candidateCVKey(userID uint, candidateID int, filename string) (string, error) {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("candidates/%d/%d/%s-%s", userID, candidateID, hex.EncodeToString(suffix), filepath.Base(filename)), nil
+}
+
+// recruiter validates the token and requires a recruiting role. It writes the
+// error response itself.
+func (h *RecruitingHandler) recruiter(c *gin.Context) (*utils.Claims, bool) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+		return nil, false
+	}
+
+	auth = auth[7:] // Remove "Bearer " prefix
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	if !requireRole(c, v, recruitingRoles...) {
+		return nil, false
+	}
+
+	return v, true
+}
+
+// opening resolves the tenant's job opening in the path.
+func (h *RecruitingHandler) opening(c *gin.Context, userID uint) (models.JobOpening, bool) {
+	id, err := strconv.Atoi(c.Param("openingId"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job opening not found"})
+		return models.JobOpening{}, false
+	}
+
+	opening, err := h.Repo.GetJobOpening(userID, id)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job opening not found"})
+		return models.JobOpening{}, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch job opening"})
+		return models.JobOpening{}, false
+	}
+	return opening, true
+}
+
+// candidate resolves the candidate in the path, scoped to the tenant's openings.
+func (h *RecruitingHandler) candidate(c *gin.Context, userID uint) (models.Candidate, bool) {
+	id, err := strconv.Atoi(c.Param("candidateId"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Candidate not found"})
+		return models.Candidate{}, false
+	}
+
+	candidate, err := h.Repo.GetCandidate(userID, id)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Candidate not found"})
+		return models.Candidate{}, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch candidate"})
+		return models.Candidate{}, false
+	}
+	return candidate, true
+}
+
+// checkOpening checks the opening's department and position belong to the
+// tenant, and that the position is a seat in that department.
+func (h *RecruitingHandler) checkOpening(c *gin.Context, userID uint, opening models.JobOpening) bool {
+	if _, err := models.FindDepartmentById(userID, opening.DepartmentID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Department ID"})
+		return false
+	}
+
+	if opening.PositionID != nil {
+		position, err := h.Repo.GetPosition(userID, *opening.PositionID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Position ID"})
+			return false
+		}
+		if position.DepartmentID != opening.DepartmentID {
+			c.JSON(http.StatusConflict, gin.H{"error": "Position is not in the opening's department"})
+			return false
+		}
+	}
+
+	return true
+}
+
+func (h *RecruitingHandler) CreateJobOpening() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Content-Type") != "application/json" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing content-type"})
+			return
+		}
+
+		v, ok := h.recruiter(c)
+		if !ok {
+			return
+		}
+
+		var req models.JobOpening
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+
+		if !h.checkOpening(c, v.UserID, req) {
+			return
+		}
+
+		opening, err := h.Repo.CreateJobOpening(v.UserID, req)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job opening"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, opening)
+	}
+}
+
+func (h *RecruitingHandler) GetJobOpenings() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		v, ok := h.recruiter(c)
+		if !ok {
+			return
+		}
+
+		filters := map[string]string{"userId": strconv.Itoa(int(v.UserID))}
+		if departmentID := c.Query("departmentId"); departmentID != "" {
+			filters["departmentId"] = departmentID
+		}
+		if status := c.Query("status"); status != "" {
+			if status != string(models.OpeningOpen) && status != string(models.OpeningClosed) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "status must be open or closed"})
+				return
+			}
+			filters["status"] = status
+		}
+
+		openings, err := h.Repo.GetJobOpenings(filters)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch job openings"})
+			return
+		}
+
+		c.JSON(http.StatusOK, openings)
+	}
+}
+
+func (h *RecruitingHandler) GetJobOpening() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		v, ok := h.recruiter(c)
+		if !ok {
+			return
+		}
+
+		opening, ok := h.opening(c, v.UserID)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, opening)
+	}
+}
+
+func (h *RecruitingHandler) UpdateJobOpening() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		v, ok := h.recruiter(c)
+		if !ok {
+			return
+		}
+
+		existing, ok := h.opening(c, v.UserID)
+		if !ok {
+			return
+		}
+
+		var updated models.JobOpening
+		if status, err := applyPatch(c, existing, &updated); err != nil {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		updated.ID = existing.ID
+
+		if err := binding.Validator.ValidateStruct(&updated); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+		if updated.Status == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "status must be open or closed"})
+			return
+		}
+
+		if !h.checkOpening(c, v.UserID, updated) {
+			return
+		}
+
+		opening, err := h.Repo.UpdateJobOpening(v.UserID, updated)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job opening"})
+			return
+		}
+
+		c.JSON(http.StatusOK, opening)
+	}
+}
+
+func (h *RecruitingHandler) AddCandidate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Content-Type") != "application/json" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing content-type"})
+			return
+		}
+
+		v, ok := h.recruiter(c)
+		if !ok {
+			return
+		}
+
+		opening, ok := h.opening(c, v.UserID)
+		if !ok {
+			return
+		}
+		if opening.Status != models.OpeningOpen {
+			c.JSON(http.StatusConflict, gin.H{"error": "Job opening is closed"})
+			return
+		}
+
+		var req models.Candidate
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+		req.OpeningID = opening.ID
+
+		candidate, err := h.Repo.AddCandidate(req)
+		if err != nil {
+			if errors.Is(err, repositories.ErrCandidateExists) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add candidate"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, candidate)
+	}
+}
+
+func (h *RecruitingHandler) GetCandidates() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		v, ok := h.recruiter(c)
+		if !ok {
+			return
+		}
+
+		opening, ok := h.opening(c, v.UserID)
+		if !ok {
+			return
+		}
+
+		stage := c.Query("stage")
+		if stage != "" && !models.CandidateStage(stage).IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid stage"})
+			return
+		}
+
+		candidates, err := h.Repo.GetCandidates(opening.ID, stage)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch candidates"})
+			return
+		}
+
+		c.JSON(http.StatusOK, candidates)
+	}
+}
+
+func (h *RecruitingHandler) GetCandidate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		v, ok := h.recruiter(c)
+		if !ok {
+			return
+		}
+
+		candidate, ok := h.candidate(c, v.UserID)
+		if !ok {
+			return
+		}
+
+		notes, err := h.Repo.GetCandidateNotes(candidate.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch candidate notes"})
+			return
+		}
+
+		c.JSON(http.StatusOK, models.CandidateDetails{Candidate: candidate, Notes: notes})
+	}
+}
+
+func (h *RecruitingHandler) MoveCandidate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Content-Type") != "application/json" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing content-type"})
+			return
+		}
+
+		v, ok := h.recruiter(c)
+		if !ok {
+			return
+		}
+
+		candidate, ok := h.candidate(c, v.UserID)
+		if !ok {
+			return
+		}
+
+		var req CandidateStageRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+
+		if !req.Stage.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid stage"})
+			return
+		}
+		if req.Stage == models.StageHired {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Candidates are hired through the hire action"})
+			return
+		}
+		if !candidate.Stage.CanMoveTo(req.Stage) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Cannot move candidate from " + string(candidate.Stage) + " to " + string(req.Stage)})
+			return
+		}
+
+		if err := h.Repo.MoveCandidate(candidate.ID, req.Stage); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move candidate"})
+			return
+		}
+		candidate.Stage = req.Stage
+
+		c.JSON(http.StatusOK, candidate)
+	}
+}
+
+func (h *RecruitingHandler) AddCandidateNote() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Content-Type") != "application/json" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing content-type"})
+			return
+		}
+
+		v, ok := h.recruiter(c)
+		if !ok {
+			return
+		}
+
+		candidate, ok := h.candidate(c, v.UserID)
+		if !ok {
+			return
+		}
+
+		var req models.CandidateNote
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+		req.CandidateID = candidate.ID
+		req.Stage = candidate.Stage
+		req.Author = v.Email
+
+		note, err := h.Repo.AddCandidateNote(req)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add note"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, note)
+	}
+}
+
+// UploadCandidateCV stores the CV, replacing any earlier one.
+func (h *RecruitingHandler) UploadCandidateCV() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxDocumentSize+1024*1024)
+
+		v, ok := h.recruiter(c)
+		if !ok {
+			return
+		}
+
+		candidate, ok := h.candidate(c, v.UserID)
+		if !ok {
+			return
+		}
+
+		var req CandidateCVUploadRequest
+		if err := c.ShouldBind(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+
+		if !isValidCVType(req.File.Filename) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file type. Allowed types: pdf, doc, docx"})
+			return
+		}
+
+		if req.File.Size > maxDocumentSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "File size exceeds the maximum limit of 10 MiB"})
+			return
+		}
+
+		key, err := candidateCVKey(v.UserID, candidate.ID, req.File.Filename)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store CV"})
+			return
+		}
+
+		cv := models.CandidateCV{
+			FileName:    filepath.Base(req.File.Filename),
+			ContentType: getContentType(strings.ToLower(req.File.Filename)),
+			SizeBytes:   req.File.Size,
+			StorageKey:  key,
+		}
+		if err := h.Files.putObject(key, cv.ContentType, req.File); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store CV"})
+			return
+		}
+
+		if err := h.Repo.SetCandidateCV(candidate.ID, cv); err != nil {
+			if err := h.Files.deleteObject(key); err != nil {
+				log.Printf("failed to remove orphaned CV %s: %v", key, err)
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save CV"})
+			return
+		}
+
+		// The record points at the new file, so a leftover old one is only wasted storage
+		if candidate.CV != nil {
+			if err := h.Files.deleteObject(candidate.CV.StorageKey); err != nil {
+				log.Printf("failed to remove replaced CV %s: %v", candidate.CV.StorageKey, err)
+			}
+		}
+		candidate.CV = &cv
+
+		c.JSON(http.StatusOK, candidate)
+	}
+}
+
+func (h *RecruitingHandler) DownloadCandidateCV() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		v, ok := h.recruiter(c)
+		if !ok {
+			return
+		}
+
+		candidate, ok := h.candidate(c, v.UserID)
+		if !ok {
+			return
+		}
+		if candidate.CV == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "CV not found"})
+			return
+		}
+
+		body, err := h.Files.getObject(c.Request.Context(), candidate.CV.StorageKey)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to read CV"})
+			return
+		}
+		defer body.Close()
+
+		c.Header("Content-Type", candidate.CV.ContentType)
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", candidate.CV.FileName))
+		c.Header("Content-Length", strconv.FormatInt(candidate.CV.SizeBytes, 10))
+		c.Status(http.StatusOK)
+		if _, err := io.Copy(c.Writer, body); err != nil {
+			log.Printf("failed to stream CV of candidate %d: %v", candidate.ID, err)
+		}
+	}
+}
+
+// DeleteCandidate removes the candidate with their notes and CV. An employee
+// created by hiring them is kept.
+func (h *RecruitingHandler) DeleteCandidate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		v, ok := h.recruiter(c)
+		if !ok {
+			return
+		}
+
+		candidate, ok := h.candidate(c, v.UserID)
+		if !ok {
+			return
+		}
+
+		if err := h.Repo.DeleteCandidate(candidate.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete candidate"})
+			return
+		}
+
+		// The record is gone, so a leftover object is only wasted storage
+		if candidate.CV != nil {
+			if err := h.Files.deleteObject(candidate.CV.StorageKey); err != nil {
+				log.Printf("failed to remove CV %s: %v", candidate.CV.StorageKey, err)
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Candidate deleted successfully"})
+	}
+}
+
+// HireCandidate creates the employee from a candidate with an offer, with the
+// same checks as POST /employee, and starts their onboarding.
+func (h *RecruitingHandler) HireCandidate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Content-Type") != "application/json" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing content-type"})
+			return
+		}
+
+		v, ok := h.recruiter(c)
+		if !ok {
+			return
+		}
+
+		candidate, ok := h.candidate(c, v.UserID)
+		if !ok {
+			return
+		}
+		if candidate.Stage != models.StageOffer {
+			c.JSON(http.StatusConflict, gin.H{"error": repositories.ErrCandidateNotOffered.Error()})
+			return
+		}
+
+		var req HireRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+
+		if req.DepartmentID == "" {
+			opening, err := h.Repo.GetJobOpening(v.UserID, candidate.OpeningID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch job opening"})
+				return
+			}
+			req.DepartmentID = opening.DepartmentID
+		}
+
+		employee := models.Employee{
+			IdentityNumber:   req.IdentityNumber,
+			Name:             candidate.Name,
+			Gender:           req.Gender,
+			DepartmentID:     req.DepartmentID,
+			EmployeeImageURI: req.EmployeeImageURI,
+			Phone:            candidate.Phone,
+			CustomFields:     req.CustomFields,
+		}
+		if err := binding.Validator.ValidateStruct(&employee); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+		if !validateNewEmployee(c, h.Repo, v.UserID, &employee) {
+			return
+		}
+
+		hired, err := h.Repo.HireCandidate(v.UserID, candidate.ID, employee)
+		if err != nil {
+			if errors.Is(err, repositories.ErrCandidateNotOffered) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hire candidate", "details": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"candidate": hired,
+			"employee": gin.H{
+				"departmentId":     employee.DepartmentID,
+				"name":             employee.Name,
+				"identityNumber":   employee.IdentityNumber,
+				"gender":           employee.Gender,
+				"employeeImageUri": employee.EmployeeImageURI,
+				"customFields":     employee.CustomFields,
+			},
+		})
+	}
+}
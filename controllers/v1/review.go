@@ -0,0 +1,642 @@
+package v1
+
+import (
+	"database/sql"
+	"errors"
+	"go-go-manager/models"
+	"go-go-manager/repositories"
+	"go-go-manager/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewCycleRequest struct {
+	Name          string   `json:"name" binding:"required,min=2,max=100"`
+	TemplateID    int      `json:"templateId" binding:"required"`
+	Period        string   `json:"period" binding:"required,oneof=quarterly annual"`
+	PeriodStart   string   `json:"periodStart" binding:"required,datetime=2006-01-02"`
+	PeriodEnd     string   `json:"periodEnd" binding:"required,datetime=2006-01-02"`
+	SelfDue       string   `json:"selfDue" binding:"required,datetime=2006-01-02"`
+	ManagerDue    string   `json:"managerDue" binding:"required,datetime=2006-01-02"`
+	DepartmentIDs []string `json:"departmentIds" binding:"max=100"` // Every department when empty
+}
+
+type SelfAssessmentRequest struct {
+	Answers map[string]interface{} `json:"answers" binding:"required"`
+}
+
+// ManagerAssessmentRequest is recorded as submitted by whoever the token
+// belongs to: an admin, or the assigned reviewer with their self-service token.
+type ManagerAssessmentRequest struct {
+	Answers       map[string]interface{} `json:"answers" binding:"required"`
+	OverallRating *float64               `json:"overallRating" binding:"required"`
+}
+
+func CreateReviewTemplate(c *gin.Context) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+
+	if !strings.HasPrefix(auth, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		return
+	}
+
+	if c.GetHeader("Content-Type") != "application/json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing content-type"})
+		return
+	}
+
+	auth = auth[7:]
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req models.ReviewTemplate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+		return
+	}
+	if err := req.Check(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := models.FindReviewTemplateByName(v.UserID, req.Name); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Review template already exists"})
+		return
+	}
+
+	template, err := models.CreateReviewTemplate(v.UserID, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create review template"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+func GetReviewTemplates(c *gin.Context) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+
+	if !strings.HasPrefix(auth, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		return
+	}
+
+	auth = auth[7:]
+	v, err := utils.ValidateJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	templates, err := models.GetReviewTemplates(v.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch review templates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+func (h *EmployeeHandler) CreateReviewCycle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		var req ReviewCycleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+
+		// Dates are validated as YYYY-MM-DD, so they compare as strings
+		if req.PeriodEnd < req.PeriodStart {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "periodEnd cannot be before periodStart"})
+			return
+		}
+		if req.ManagerDue < req.SelfDue {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "managerDue cannot be before selfDue"})
+			return
+		}
+
+		template, err := models.FindReviewTemplateById(v.UserID, req.TemplateID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Review template not found"})
+			return
+		}
+
+		for _, departmentID := range req.DepartmentIDs {
+			if _, err := models.FindDepartmentById(v.UserID, departmentID); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Department " + departmentID + " not found"})
+				return
+			}
+		}
+
+		cycle, err := h.Repo.CreateReviewCycle(v.UserID, models.ReviewCycle{
+			TemplateID:  template.ID,
+			Template:    template,
+			Name:        req.Name,
+			Period:      models.ReviewPeriod(req.Period),
+			PeriodStart: req.PeriodStart,
+			PeriodEnd:   req.PeriodEnd,
+			SelfDue:     req.SelfDue,
+			ManagerDue:  req.ManagerDue,
+			CreatedBy:   v.Email,
+		}, req.DepartmentIDs)
+		if errors.Is(err, repositories.ErrReviewCycleExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Review cycle already exists"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create review cycle"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, cycle)
+	}
+}
+
+func (h *EmployeeHandler) GetReviewCycles() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		status := c.Query("status")
+		if status != "" && status != string(models.CycleOpen) && status != string(models.CycleClosed) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status value"})
+			return
+		}
+
+		cycles, err := h.Repo.GetReviewCycles(v.UserID, status)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch review cycles"})
+			return
+		}
+
+		c.JSON(http.StatusOK, cycles)
+	}
+}
+
+func (h *EmployeeHandler) CloseReviewCycle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		cycleID, err := strconv.Atoi(c.Param("cycleId"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review cycle not found"})
+			return
+		}
+		if _, err := h.Repo.GetReviewCycle(v.UserID, cycleID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review cycle not found"})
+			return
+		}
+
+		cycle, err := h.Repo.CloseReviewCycle(v.UserID, cycleID)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusConflict, gin.H{"error": "Review cycle is already closed"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to close review cycle"})
+			return
+		}
+
+		c.JSON(http.StatusOK, cycle)
+	}
+}
+
+// GetReviewDashboard reports the progress of a cycle per department.
+func (h *EmployeeHandler) GetReviewDashboard() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		cycleID, err := strconv.Atoi(c.Param("cycleId"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review cycle not found"})
+			return
+		}
+		cycle, err := h.Repo.GetReviewCycle(v.UserID, cycleID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review cycle not found"})
+			return
+		}
+
+		progress, err := h.Repo.GetReviewProgress(cycle.ID, time.Now().Format(time.DateOnly))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch review progress"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"cycle":       cycle,
+			"departments": progress,
+		})
+	}
+}
+
+func (h *EmployeeHandler) GetCycleReviews() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		cycleID, err := strconv.Atoi(c.Param("cycleId"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review cycle not found"})
+			return
+		}
+		if _, err := h.Repo.GetReviewCycle(v.UserID, cycleID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review cycle not found"})
+			return
+		}
+
+		filters := map[string]string{
+			"userId":  strconv.Itoa(int(v.UserID)),
+			"cycleId": strconv.Itoa(cycleID),
+		}
+		if departmentID := c.Query("departmentId"); departmentID != "" {
+			filters["departmentId"] = departmentID
+		}
+		if status := c.Query("status"); status != "" {
+			filters["status"] = status
+		}
+
+		reviews, err := h.Repo.GetReviews(filters)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reviews"})
+			return
+		}
+
+		c.JSON(http.StatusOK, reviews)
+	}
+}
+
+func (h *EmployeeHandler) GetReview() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		reviewID, err := strconv.Atoi(c.Param("reviewId"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+			return
+		}
+		review, err := h.Repo.GetReview(v.UserID, reviewID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, review)
+	}
+}
+
+func (h *EmployeeHandler) SubmitSelfAssessment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		var req SelfAssessmentRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+
+		reviewID, err := strconv.Atoi(c.Param("reviewId"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+			return
+		}
+		review, err := h.Repo.GetReview(v.UserID, reviewID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+			return
+		}
+
+		cycle, err := h.Repo.GetReviewCycle(v.UserID, review.CycleID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch review cycle"})
+			return
+		}
+		if cycle.Status != models.CycleOpen {
+			c.JSON(http.StatusConflict, gin.H{"error": "Review cycle is closed"})
+			return
+		}
+
+		answers, problems := cycle.Template.ValidateAnswers(req.Answers)
+		if len(problems) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid answers", "details": problems})
+			return
+		}
+
+		err = h.Repo.SubmitSelfAssessment(review.ID, answers)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusConflict, gin.H{"error": "The manager has already assessed this review"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save self assessment"})
+			return
+		}
+
+		updated, err := h.Repo.GetReview(v.UserID, review.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch review"})
+			return
+		}
+
+		c.JSON(http.StatusOK, updated)
+	}
+}
+
+func (h *EmployeeHandler) SubmitManagerAssessment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		reviewID, err := strconv.Atoi(c.Param("reviewId"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+			return
+		}
+		review, err := h.Repo.GetReview(v.UserID, reviewID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+			return
+		}
+
+		h.submitManagerAssessment(c, v.UserID, review, v.Email)
+	}
+}
+
+// SubmitReviewerAssessment lets the reviewer assigned at launch assess the
+// review with their self-service token. The assessment is recorded under the
+// reviewer's identity number.
+func (h *EmployeeHandler) SubmitReviewerAssessment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		account, reviewer, ok := h.selfEmployee(c)
+		if !ok {
+			return
+		}
+
+		reviewID, err := strconv.Atoi(c.Param("reviewId"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+			return
+		}
+		review, err := h.Repo.GetReview(account.UserID, reviewID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+			return
+		}
+
+		if review.ReviewerIdentityNumber == nil || *review.ReviewerIdentityNumber != reviewer.IdentityNumber {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the assigned reviewer or an admin can assess this review"})
+			return
+		}
+
+		h.submitManagerAssessment(c, account.UserID, review, reviewer.IdentityNumber)
+	}
+}
+
+// submitManagerAssessment saves the assessment by submittedBy once the caller
+// has checked they may submit it.
+func (h *EmployeeHandler) submitManagerAssessment(c *gin.Context, userID uint, review models.Review, submittedBy string) {
+	var req ManagerAssessmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+		return
+	}
+
+	cycle, err := h.Repo.GetReviewCycle(userID, review.CycleID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch review cycle"})
+		return
+	}
+	if cycle.Status != models.CycleOpen {
+		c.JSON(http.StatusConflict, gin.H{"error": "Review cycle is closed"})
+		return
+	}
+
+	answers, problems := cycle.Template.ValidateAnswers(req.Answers)
+	if !cycle.Template.InScale(*req.OverallRating) {
+		problems = append(problems, "overallRating must be on the review's scale")
+	}
+	if len(problems) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid answers", "details": problems})
+		return
+	}
+
+	err = h.Repo.SubmitManagerAssessment(review.ID, answers, *req.OverallRating, submittedBy)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusConflict, gin.H{"error": "Review is already finalized"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save manager assessment"})
+		return
+	}
+
+	updated, err := h.Repo.GetReview(userID, review.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch review"})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *EmployeeHandler) FinalizeReview() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		reviewID, err := strconv.Atoi(c.Param("reviewId"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+			return
+		}
+		review, err := h.Repo.GetReview(v.UserID, reviewID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+			return
+		}
+
+		err = h.Repo.FinalizeReview(review.ID, v.Email)
+		if err == sql.ErrNoRows {
+			if review.Status == models.ReviewFinalized {
+				c.JSON(http.StatusConflict, gin.H{"error": "Review is already finalized"})
+			} else {
+				c.JSON(http.StatusConflict, gin.H{"error": "The manager assessment is missing"})
+			}
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to finalize review"})
+			return
+		}
+
+		updated, err := h.Repo.GetReview(v.UserID, review.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch review"})
+			return
+		}
+
+		c.JSON(http.StatusOK, updated)
+	}
+}
+
+// GetEmployeeReviews lists the employee's finalized reviews, which can no longer change.
+func (h *EmployeeHandler) GetEmployeeReviews() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Validate the token
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+			return
+		}
+
+		auth = auth[7:] // Remove "Bearer " prefix
+		v, err := utils.ValidateJWT(auth)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		employee, err := h.Repo.GetEmployeeByIdentityNumber(c.Param("identityNumber"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if _, err := models.FindDepartmentById(v.UserID, employee.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+
+		reviews, err := h.Repo.GetReviews(map[string]string{
+			"userId":         strconv.Itoa(int(v.UserID)),
+			"identityNumber": employee.IdentityNumber,
+			"status":         string(models.ReviewFinalized),
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reviews"})
+			return
+		}
+
+		c.JSON(http.StatusOK, reviews)
+	}
+}
@@ -0,0 +1,27 @@
+package v1
+
+import (
+	"go-go-manager/models"
+	"go-go-manager/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requireRole aborts with 403 unless the signed-in account has one of roles.
+func requireRole(c *gin.Context, v *utils.Claims, roles ...models.Role) bool {
+	role, err := models.FindUserRole(v.Account())
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		return false
+	}
+
+	for _, r := range roles {
+		if role == r {
+			return true
+		}
+	}
+
+	c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+	return false
+}
@@ -0,0 +1,207 @@
+package v1
+
+import (
+	"database/sql"
+	"go-go-manager/models"
+	"go-go-manager/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type AccountActivationRequest struct {
+	InviteToken string `json:"inviteToken" binding:"required"`
+	Password    string `json:"password" binding:"required,min=8,max=32"`
+}
+
+type SelfAuthRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=8,max=32"`
+}
+
+// SelfProfile holds the fields employees may change on their own record.
+type SelfProfile struct {
+	Phone            *string `json:"phone" binding:"omitempty,e164"`
+	EmployeeImageURI string  `json:"employeeImageUri" binding:"required,uri,isImage"`
+}
+
+// selfEmployee resolves the employee behind a self-service token. The account
+// is looked up on every request, so revoking it or deleting the employee
+// locks the token out straight away.
+func (h *EmployeeHandler) selfEmployee(c *gin.Context) (models.EmployeeAccount, *models.Employee, bool) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing request token"})
+		return models.EmployeeAccount{}, nil, false
+	}
+
+	auth = auth[7:] // Remove "Bearer " prefix
+	v, err := utils.ValidateEmployeeJWT(auth)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return models.EmployeeAccount{}, nil, false
+	}
+
+	account, err := h.Repo.FindEmployeeAccountById(int(v.AccountID))
+	if err != nil || account.Status != models.AccountActive {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "account not found"})
+		return models.EmployeeAccount{}, nil, false
+	}
+
+	employee, err := h.Repo.GetEmployeeByIdentityNumber(account.IdentityNumber)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "account not found"})
+		return models.EmployeeAccount{}, nil, false
+	}
+
+	return account, employee, true
+}
+
+func (h *EmployeeHandler) ActivateAccount() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req AccountActivationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hashing password"})
+			return
+		}
+
+		account, err := h.Repo.ActivateEmployeeAccount(hashInviteToken(req.InviteToken), string(hashedPassword))
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invite token is invalid or has expired"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to activate account"})
+			return
+		}
+
+		token, err := utils.GenerateEmployeeJWT(uint(account.ID), account.Email)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"email": account.Email,
+			"token": token,
+		})
+	}
+}
+
+func (h *EmployeeHandler) SelfAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req SelfAuthRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		account, password, err := h.Repo.FindEmployeeAccountByEmail(req.Email)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Email not found"})
+			return
+		}
+
+		// check password validity
+		if err := bcrypt.CompareHashAndPassword([]byte(password), []byte(req.Password)); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Password mismatch"})
+			return
+		}
+
+		token, err := utils.GenerateEmployeeJWT(uint(account.ID), account.Email)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"email": account.Email,
+			"token": token,
+		})
+	}
+}
+
+func (h *EmployeeHandler) GetSelf() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, employee, ok := h.selfEmployee(c)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, employee)
+	}
+}
+
+func (h *EmployeeHandler) UpdateSelf() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, employee, ok := h.selfEmployee(c)
+		if !ok {
+			return
+		}
+
+		// Fields other than those of SelfProfile are dropped from the patch
+		existing := SelfProfile{Phone: employee.Phone, EmployeeImageURI: employee.EmployeeImageURI}
+		var updated SelfProfile
+		if status, err := applyPatch(c, existing, &updated); err != nil {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := binding.Validator.ValidateStruct(&updated); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": describeValidationError(err)})
+			return
+		}
+
+		employee.Phone = updated.Phone
+		employee.EmployeeImageURI = updated.EmployeeImageURI
+		if err := h.Repo.UpdateEmployee(employee.IdentityNumber, *employee); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update employee"})
+			return
+		}
+
+		c.JSON(http.StatusOK, employee)
+	}
+}
+
+func (h *EmployeeHandler) GetSelfLeaveBalances() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		account, employee, ok := h.selfEmployee(c)
+		if !ok {
+			return
+		}
+
+		year := time.Now().Year()
+		if yearStr := c.Query("year"); yearStr != "" {
+			var err error
+			year, err = strconv.Atoi(yearStr)
+			if err != nil || year < 1900 || year > 9999 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "year must be a four-digit year"})
+				return
+			}
+		}
+
+		leaveTypes, err := models.GetLeaveTypes(account.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch leave types"})
+			return
+		}
+
+		balances, err := h.Repo.GetLeaveBalances(employee.IdentityNumber, leaveTypes, year, balanceDate(year))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch leave balance"})
+			return
+		}
+
+		c.JSON(http.StatusOK, balances)
+	}
+}
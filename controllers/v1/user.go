@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 func GetUsers(c *gin.Context) {
@@ -30,7 +31,7 @@ func GetUsers(c *gin.Context) {
 		return
 	}
 
-	user, err := models.FindUserById(v.UserID)
+	user, err := models.FindUserById(v.Account())
 
 	res := models.UserRequest{
 		Email:           user.Email,
@@ -62,7 +63,7 @@ func UpdateUser(c *gin.Context) {
 		return
 	}
 
-	if c.GetHeader("Content-Type") != "application/json" {
+	if c.GetHeader("Content-Type") == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing content-type"})
 		return
 	}
@@ -75,25 +76,49 @@ func UpdateUser(c *gin.Context) {
 		return
 	}
 
+	user, err := models.FindUserById(v.Account())
+	if err != nil {
+		c.JSON(404, gin.H{"error": err.Error()})
+		return
+	}
+
+	current := models.UserRequest{
+		Email:           user.Email,
+		Name:            user.Name.String,
+		UserImageUri:    user.UserImageUri.String,
+		CompanyName:     user.CompanyName.String,
+		CompanyImageUri: user.CompanyImageUri.String,
+	}
+
+	// Only the fields in the patch change; validation runs on the merged profile
 	var body models.UserRequest
-	if err := c.ShouldBindJSON(&body); err != nil {
+	if status, err := applyPatch(c, current, &body); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := binding.Validator.ValidateStruct(&body); err != nil {
 		c.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
 
+	// A null in a merge patch must not clear a field the profile requires
 	if body.Email == "" || body.Name == "" || body.CompanyName == "" || body.UserImageUri == "" || body.CompanyImageUri == "" {
 		c.JSON(400, gin.H{"error": "All fields are required"})
 		return
 	}
 
-	ed, _ := models.CheckEmailDuplicate(body.Email, v.UserID)
+	ed, _ := models.CheckEmailDuplicate(body.Email, v.Account())
 
 	if ed {
 		c.JSON(http.StatusConflict, gin.H{"error": "Email already exists"})
 		return
 	}
 
-	models.UpdateProfile(body, v.UserID)
+	if _, err := models.UpdateProfile(body, v.Account()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
 	c.JSON(200, body)
 
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS employee_status_transitions;
+
+DROP INDEX IF EXISTS idx_employees_employment_id;
+
+ALTER TABLE employees
+DROP COLUMN IF EXISTS employment_id;
\ No newline at end of file
@@ -0,0 +1,30 @@
+-- Every row of employees is one employment. Deleting the employee and adding
+-- them again, in this tenant or another, starts a new one under the same
+-- identity number, so records kept past a deletion are keyed by employment.
+ALTER TABLE employees
+ADD COLUMN IF NOT EXISTS employment_id SERIAL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_employees_employment_id ON employees (employment_id);
+
+-- There is deliberately no foreign key to employees: the transitions outlive a
+-- deleted employee so dates before the deletion still resolve their status.
+CREATE TABLE IF NOT EXISTS employee_status_transitions (
+    id SERIAL PRIMARY KEY,
+    employment_id INTEGER NOT NULL,
+    identity_number VARCHAR(50) NOT NULL,
+    from_status VARCHAR(20) CHECK (from_status IN ('onboarding', 'active', 'on_leave', 'terminated')),
+    to_status VARCHAR(20) CHECK (to_status IN ('onboarding', 'active', 'on_leave', 'terminated')) NOT NULL,
+    effective_date DATE NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_employee_status_transitions_employment_id
+    ON employee_status_transitions (employment_id, effective_date DESC, id DESC);
+
+INSERT INTO employee_status_transitions (employment_id, identity_number, to_status, effective_date, reason)
+SELECT e.employment_id, e.identity_number, 'active', CURRENT_DATE, 'initial status'
+FROM employees e
+WHERE NOT EXISTS (
+    SELECT 1 FROM employee_status_transitions t WHERE t.employment_id = e.employment_id
+);
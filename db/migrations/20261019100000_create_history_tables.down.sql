@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS trg_department_history ON department;
+DROP TRIGGER IF EXISTS trg_employees_history ON employees;
+DROP TRIGGER IF EXISTS trg_employees_identity_number ON employees;
+DROP FUNCTION IF EXISTS record_department_history();
+DROP FUNCTION IF EXISTS record_employee_history();
+DROP FUNCTION IF EXISTS prevent_identity_number_change();
+DROP TABLE IF EXISTS department_history;
+DROP TABLE IF EXISTS employees_history;
\ No newline at end of file
@@ -0,0 +1,125 @@
+CREATE TABLE IF NOT EXISTS employees_history (
+    id SERIAL PRIMARY KEY,
+    employment_id INTEGER NOT NULL,
+    identity_number VARCHAR(50) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    gender VARCHAR(10) NOT NULL,
+    department_id INTEGER NOT NULL,
+    employee_image_uri TEXT NOT NULL,
+    valid_from TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    valid_to TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_employees_history_identity_number
+    ON employees_history (identity_number, valid_from);
+CREATE INDEX IF NOT EXISTS idx_employees_history_employment_id
+    ON employees_history (employment_id, valid_from);
+CREATE INDEX IF NOT EXISTS idx_employees_history_validity
+    ON employees_history (valid_from, valid_to);
+
+CREATE TABLE IF NOT EXISTS department_history (
+    id SERIAL PRIMARY KEY,
+    department_id INTEGER NOT NULL,
+    name VARCHAR(255),
+    userId INT,
+    valid_from TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    valid_to TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_department_history_department_id
+    ON department_history (department_id, valid_from);
+CREATE INDEX IF NOT EXISTS idx_department_history_validity
+    ON department_history (userId, valid_from, valid_to);
+
+-- Every write to employees closes the open version and, unless the row was
+-- deleted, opens a new one. The version copies every column the two tables
+-- share, so a migration adding an employee column only adds it to
+-- employees_history as well and never redefines this function.
+CREATE OR REPLACE FUNCTION record_employee_history() RETURNS TRIGGER AS $$
+DECLARE
+    version employees_history;
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        IF TG_OP = 'UPDATE' AND ROW(NEW.*) IS NOT DISTINCT FROM ROW(OLD.*) THEN
+            RETURN NEW;
+        END IF;
+
+        UPDATE employees_history
+        SET valid_to = NOW()
+        WHERE employment_id = OLD.employment_id AND valid_to IS NULL;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        version := jsonb_populate_record(NULL::employees_history, to_jsonb(NEW));
+        version.id := nextval(pg_get_serial_sequence('employees_history', 'id'));
+        version.valid_from := NOW();
+        version.valid_to := NULL;
+        INSERT INTO employees_history SELECT version.*;
+        RETURN NEW;
+    END IF;
+
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION record_department_history() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        IF TG_OP = 'UPDATE' AND NEW.name IS NOT DISTINCT FROM OLD.name AND NEW.userId IS NOT DISTINCT FROM OLD.userId THEN
+            RETURN NEW;
+        END IF;
+
+        UPDATE department_history
+        SET valid_to = NOW()
+        WHERE department_id = OLD.id AND valid_to IS NULL;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO department_history (department_id, name, userId, valid_from)
+        VALUES (NEW.id, NEW.name, NEW.userId, NOW());
+        RETURN NEW;
+    END IF;
+
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Every record kept about the employee is looked up by their identity number,
+-- so it cannot change once assigned.
+CREATE OR REPLACE FUNCTION prevent_identity_number_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'identity number % cannot be changed', OLD.identity_number
+        USING ERRCODE = 'check_violation';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_employees_identity_number ON employees;
+CREATE TRIGGER trg_employees_identity_number
+BEFORE UPDATE OF identity_number ON employees
+FOR EACH ROW WHEN (NEW.identity_number IS DISTINCT FROM OLD.identity_number)
+EXECUTE FUNCTION prevent_identity_number_change();
+
+DROP TRIGGER IF EXISTS trg_employees_history ON employees;
+CREATE TRIGGER trg_employees_history
+AFTER INSERT OR UPDATE OR DELETE ON employees
+FOR EACH ROW EXECUTE FUNCTION record_employee_history();
+
+DROP TRIGGER IF EXISTS trg_department_history ON department;
+CREATE TRIGGER trg_department_history
+AFTER INSERT OR UPDATE OR DELETE ON department
+FOR EACH ROW EXECUTE FUNCTION record_department_history();
+
+-- Seed an open version for rows that existed before history was tracked
+INSERT INTO employees_history (employment_id, identity_number, name, gender, department_id, employee_image_uri, valid_from)
+SELECT e.employment_id, e.identity_number, e.name, e.gender, e.department_id, e.employee_image_uri, NOW()
+FROM employees e
+WHERE NOT EXISTS (
+    SELECT 1 FROM employees_history h WHERE h.employment_id = e.employment_id
+);
+
+INSERT INTO department_history (department_id, name, userId, valid_from)
+SELECT d.id, d.name, d.userId, COALESCE(d.created_at, NOW())
+FROM department d
+WHERE NOT EXISTS (
+    SELECT 1 FROM department_history h WHERE h.department_id = d.id
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS employee_transfers;
\ No newline at end of file
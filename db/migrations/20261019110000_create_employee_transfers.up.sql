@@ -0,0 +1,24 @@
+-- Transfers are events and are kept when the employee or a department is
+-- deleted; a deleted department leaves its side of the transfer empty. Like
+-- status transitions they belong to one employment, so a later employment
+-- under the same identity number does not inherit them.
+CREATE TABLE IF NOT EXISTS employee_transfers (
+    id SERIAL PRIMARY KEY,
+    employment_id INTEGER NOT NULL,
+    identity_number VARCHAR(50) NOT NULL,
+    from_department_id INTEGER,
+    to_department_id INTEGER,
+    effective_date DATE NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    approver VARCHAR(100),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (from_department_id) REFERENCES department(id) ON DELETE SET NULL,
+    FOREIGN KEY (to_department_id) REFERENCES department(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_employee_transfers_employment_id
+    ON employee_transfers (employment_id, effective_date);
+CREATE INDEX IF NOT EXISTS idx_employee_transfers_from_department
+    ON employee_transfers (from_department_id, effective_date);
+CREATE INDEX IF NOT EXISTS idx_employee_transfers_to_department
+    ON employee_transfers (to_department_id, effective_date);
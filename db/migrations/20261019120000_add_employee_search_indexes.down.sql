@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_employees_identity_number_prefix;
+DROP INDEX IF EXISTS idx_employees_name_ilike;
+DROP INDEX IF EXISTS idx_department_name_trgm;
+DROP INDEX IF EXISTS idx_department_name_tsv;
+DROP INDEX IF EXISTS idx_employees_name_trgm;
+DROP INDEX IF EXISTS idx_employees_name_tsv;
+DROP FUNCTION IF EXISTS immutable_unaccent(text);
\ No newline at end of file
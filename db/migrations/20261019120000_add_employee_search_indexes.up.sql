@@ -0,0 +1,23 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent() is only STABLE, so it cannot be used in an index expression directly
+CREATE OR REPLACE FUNCTION immutable_unaccent(text) RETURNS text AS $$
+    SELECT public.unaccent('public.unaccent', $1)
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+-- Full-text and typo-tolerant matching for the search endpoint
+CREATE INDEX IF NOT EXISTS idx_employees_name_tsv
+    ON employees USING gin (to_tsvector('simple', immutable_unaccent(name)));
+CREATE INDEX IF NOT EXISTS idx_employees_name_trgm
+    ON employees USING gin (immutable_unaccent(lower(name)) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_department_name_tsv
+    ON department USING gin (to_tsvector('simple', immutable_unaccent(COALESCE(name, ''))));
+CREATE INDEX IF NOT EXISTS idx_department_name_trgm
+    ON department USING gin (immutable_unaccent(lower(COALESCE(name, ''))) gin_trgm_ops);
+
+-- Lets the existing name ILIKE and identity_number prefix filters use an index
+CREATE INDEX IF NOT EXISTS idx_employees_name_ilike
+    ON employees USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_employees_identity_number_prefix
+    ON employees (identity_number varchar_pattern_ops);
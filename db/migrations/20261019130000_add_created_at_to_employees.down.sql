@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_department_userid_created_at_id;
+DROP INDEX IF EXISTS idx_department_userid_name_id;
+DROP INDEX IF EXISTS idx_employees_created_at_identity_number;
+DROP INDEX IF EXISTS idx_employees_name_identity_number;
+
+ALTER TABLE employees_history
+DROP COLUMN IF EXISTS created_at;
+
+ALTER TABLE employees
+DROP COLUMN IF EXISTS created_at;
\ No newline at end of file
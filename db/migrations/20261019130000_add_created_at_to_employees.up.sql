@@ -0,0 +1,27 @@
+ALTER TABLE employees
+ADD COLUMN IF NOT EXISTS created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- record_employee_history copies the column into new versions
+ALTER TABLE employees_history
+ADD COLUMN IF NOT EXISTS created_at TIMESTAMP;
+
+UPDATE employees_history h
+SET created_at = e.created_at
+FROM employees e
+WHERE h.employment_id = e.employment_id AND h.created_at IS NULL;
+
+-- Versions of employees that were deleted before this migration
+UPDATE employees_history h
+SET created_at = first_version.valid_from
+FROM (
+    SELECT employment_id, MIN(valid_from) AS valid_from
+    FROM employees_history
+    GROUP BY employment_id
+) first_version
+WHERE h.employment_id = first_version.employment_id AND h.created_at IS NULL;
+
+-- Stable ordering for keyset pagination
+CREATE INDEX IF NOT EXISTS idx_employees_name_identity_number ON employees (name, identity_number);
+CREATE INDEX IF NOT EXISTS idx_employees_created_at_identity_number ON employees (created_at, identity_number);
+CREATE INDEX IF NOT EXISTS idx_department_userid_name_id ON department (userId, (COALESCE(name, '')), id);
+CREATE INDEX IF NOT EXISTS idx_department_userid_created_at_id ON department (userId, (COALESCE(created_at, 'epoch'::TIMESTAMP)), id);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_employees_custom_fields;
+
+ALTER TABLE employees_history
+DROP COLUMN IF EXISTS custom_fields;
+
+ALTER TABLE employees
+DROP COLUMN IF EXISTS custom_fields;
+
+DROP TABLE IF EXISTS custom_field_definitions;
\ No newline at end of file
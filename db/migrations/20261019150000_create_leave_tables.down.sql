@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS leave_requests;
+DROP TABLE IF EXISTS leave_types;
+
+ALTER TABLE department
+DROP COLUMN IF EXISTS head_identity_number;
\ No newline at end of file
@@ -0,0 +1,50 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- The department head approves leave for the department's employees
+ALTER TABLE department
+ADD COLUMN IF NOT EXISTS head_identity_number VARCHAR(50)
+    REFERENCES employees(identity_number) ON UPDATE CASCADE ON DELETE SET NULL;
+
+-- accrual decides how days_per_year becomes available: all at once at the start
+-- of the year, one twelfth per month, or not tracked at all (e.g. unpaid leave).
+CREATE TABLE IF NOT EXISTS leave_types (
+    id SERIAL PRIMARY KEY,
+    userId INT NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    paid BOOLEAN NOT NULL DEFAULT TRUE,
+    accrual VARCHAR(10) CHECK (accrual IN ('yearly', 'monthly', 'none')) NOT NULL,
+    days_per_year NUMERIC(5, 2) NOT NULL DEFAULT 0 CHECK (days_per_year >= 0),
+    max_carry_over NUMERIC(5, 2) NOT NULL DEFAULT 0 CHECK (max_carry_over >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (userId, name),
+    FOREIGN KEY (userId) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS leave_requests (
+    id SERIAL PRIMARY KEY,
+    identity_number VARCHAR(50) NOT NULL,
+    leave_type_id INT NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    days NUMERIC(5, 2) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(10) CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled')) NOT NULL DEFAULT 'pending',
+    decided_by VARCHAR(100),
+    decision_note VARCHAR(255),
+    decided_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date >= start_date),
+    FOREIGN KEY (identity_number) REFERENCES employees(identity_number) ON UPDATE CASCADE ON DELETE CASCADE,
+    FOREIGN KEY (leave_type_id) REFERENCES leave_types(id) ON DELETE CASCADE,
+    -- Open requests of one employee can never cover the same day
+    EXCLUDE USING gist (
+        identity_number WITH =,
+        daterange(start_date, end_date, '[]') WITH &&
+    ) WHERE (status IN ('pending', 'approved'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_leave_requests_identity_number
+    ON leave_requests (identity_number, start_date);
+CREATE INDEX IF NOT EXISTS idx_leave_requests_dates
+    ON leave_requests (start_date, end_date) WHERE status IN ('pending', 'approved');
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS timesheet_approvals;
+DROP TABLE IF EXISTS attendance_events;
+DROP TABLE IF EXISTS work_schedules;
\ No newline at end of file
@@ -0,0 +1,47 @@
+-- One working schedule per tenant. work_days holds ISO weekdays (1 = Monday);
+-- hours beyond daily_hours on a work day, and all hours on other days, are overtime.
+CREATE TABLE IF NOT EXISTS work_schedules (
+    id SERIAL PRIMARY KEY,
+    userId INT NOT NULL UNIQUE,
+    daily_hours NUMERIC(4, 2) NOT NULL DEFAULT 8 CHECK (daily_hours > 0 AND daily_hours <= 24),
+    work_days JSONB NOT NULL DEFAULT '[1, 2, 3, 4, 5]',
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (userId) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS attendance_events (
+    id SERIAL PRIMARY KEY,
+    identity_number VARCHAR(50) NOT NULL,
+    type VARCHAR(3) CHECK (type IN ('in', 'out')) NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    note VARCHAR(255),
+    location VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (identity_number) REFERENCES employees(identity_number) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_attendance_events_identity_number
+    ON attendance_events (identity_number, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_attendance_events_occurred_at
+    ON attendance_events (occurred_at);
+
+-- An approved timesheet freezes the punches of its period
+CREATE TABLE IF NOT EXISTS timesheet_approvals (
+    id SERIAL PRIMARY KEY,
+    identity_number VARCHAR(50) NOT NULL,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    worked_hours NUMERIC(7, 2) NOT NULL,
+    overtime_hours NUMERIC(7, 2) NOT NULL,
+    approved_by VARCHAR(100) NOT NULL,
+    note VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (period_end >= period_start),
+    FOREIGN KEY (identity_number) REFERENCES employees(identity_number) ON UPDATE CASCADE ON DELETE CASCADE,
+    EXCLUDE USING gist (
+        identity_number WITH =,
+        daterange(period_start, period_end, '[]') WITH &&
+    )
+);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS employee_documents;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS tenant_id,
+DROP COLUMN IF EXISTS role;
\ No newline at end of file
@@ -0,0 +1,31 @@
+-- Documents are limited to admins. Existing users default to admin: each of
+-- them owns its tenant, as does every account created through signup, so none
+-- loses access. Only members an admin adds below get a narrower role.
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'admin'
+    CHECK (role IN ('admin', 'hr', 'manager'));
+
+-- Accounts an admin adds to their tenant point at the account that owns it;
+-- the owner's tenant_id is NULL. Members are added with an explicit role.
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS tenant_id INT REFERENCES users(id) ON DELETE CASCADE;
+
+CREATE TABLE IF NOT EXISTS employee_documents (
+    id SERIAL PRIMARY KEY,
+    identity_number VARCHAR(50) NOT NULL,
+    category VARCHAR(20) CHECK (category IN ('contract', 'id_scan', 'certificate', 'other')) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    storage_key TEXT NOT NULL UNIQUE,
+    expires_on DATE,
+    uploaded_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (identity_number) REFERENCES employees(identity_number) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_employee_documents_identity_number
+    ON employee_documents (identity_number, category);
+CREATE INDEX IF NOT EXISTS idx_employee_documents_expires_on
+    ON employee_documents (expires_on) WHERE expires_on IS NOT NULL;
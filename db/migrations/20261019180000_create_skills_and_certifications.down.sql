@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS certifications;
+DROP TABLE IF EXISTS employee_skills;
+DROP TABLE IF EXISTS skills;
\ No newline at end of file
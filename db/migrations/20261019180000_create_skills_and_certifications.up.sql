@@ -0,0 +1,45 @@
+-- The tenant's skills catalog; employees are rated against it on a 1-5 scale
+CREATE TABLE IF NOT EXISTS skills (
+    id SERIAL PRIMARY KEY,
+    userId INT NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    category VARCHAR(50),
+    description VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (userId, name),
+    FOREIGN KEY (userId) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS employee_skills (
+    identity_number VARCHAR(50) NOT NULL,
+    skill_id INT NOT NULL,
+    level SMALLINT NOT NULL CHECK (level BETWEEN 1 AND 5),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (identity_number, skill_id),
+    FOREIGN KEY (identity_number) REFERENCES employees(identity_number) ON UPDATE CASCADE ON DELETE CASCADE,
+    FOREIGN KEY (skill_id) REFERENCES skills(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_employee_skills_skill_id ON employee_skills (skill_id, level);
+
+-- lapse_flagged_at is set by the reminder job once the certification enters the
+-- reminder window, and cleared again when the expiry date changes.
+CREATE TABLE IF NOT EXISTS certifications (
+    id SERIAL PRIMARY KEY,
+    identity_number VARCHAR(50) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    issuer VARCHAR(100),
+    credential_id VARCHAR(100),
+    issued_on DATE NOT NULL,
+    expires_on DATE,
+    lapse_flagged_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (expires_on IS NULL OR expires_on >= issued_on),
+    FOREIGN KEY (identity_number) REFERENCES employees(identity_number) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_certifications_identity_number ON certifications (identity_number);
+CREATE INDEX IF NOT EXISTS idx_certifications_expires_on
+    ON certifications (expires_on) WHERE expires_on IS NOT NULL;
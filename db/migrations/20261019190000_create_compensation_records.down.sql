@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS trg_compensation_history ON compensation_records;
+DROP FUNCTION IF EXISTS record_compensation_history();
+DROP TABLE IF EXISTS compensation_history;
+DROP TABLE IF EXISTS compensation_records;
\ No newline at end of file
@@ -0,0 +1,67 @@
+-- Pay is effective-dated: the record in force on a day is the latest one whose
+-- effective_date is on or before it. allowances is a JSON array of
+-- {"name", "amount"} paid every period on top of base_salary.
+CREATE TABLE IF NOT EXISTS compensation_records (
+    id SERIAL PRIMARY KEY,
+    identity_number VARCHAR(50) NOT NULL,
+    effective_date DATE NOT NULL,
+    base_salary NUMERIC(14, 2) NOT NULL CHECK (base_salary >= 0),
+    currency CHAR(3) NOT NULL,
+    pay_frequency VARCHAR(12) CHECK (pay_frequency IN ('annual', 'monthly', 'semimonthly', 'biweekly', 'weekly')) NOT NULL,
+    allowances JSONB NOT NULL DEFAULT '[]',
+    reason VARCHAR(255),
+    recorded_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (identity_number, effective_date),
+    FOREIGN KEY (identity_number) REFERENCES employees(identity_number) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+-- Every insert, correction and deletion of a record is kept, with the account
+-- that made it. The application sets app.actor for the transaction; writes made
+-- outside the application fall back to the database user.
+CREATE TABLE IF NOT EXISTS compensation_history (
+    id SERIAL PRIMARY KEY,
+    compensation_id INT NOT NULL,
+    identity_number VARCHAR(50) NOT NULL,
+    operation VARCHAR(6) CHECK (operation IN ('insert', 'update', 'delete')) NOT NULL,
+    effective_date DATE NOT NULL,
+    base_salary NUMERIC(14, 2) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    pay_frequency VARCHAR(12) NOT NULL,
+    allowances JSONB NOT NULL,
+    reason VARCHAR(255),
+    changed_by VARCHAR(255) NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_compensation_history_identity_number
+    ON compensation_history (identity_number, changed_at);
+
+CREATE OR REPLACE FUNCTION record_compensation_history() RETURNS TRIGGER AS $$
+DECLARE
+    row compensation_records;
+    actor TEXT := COALESCE(NULLIF(current_setting('app.actor', true), ''), current_user);
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        row := OLD;
+    ELSE
+        IF TG_OP = 'UPDATE' AND ROW(NEW.*) IS NOT DISTINCT FROM ROW(OLD.*) THEN
+            RETURN NEW;
+        END IF;
+        row := NEW;
+    END IF;
+
+    INSERT INTO compensation_history (compensation_id, identity_number, operation, effective_date, base_salary,
+        currency, pay_frequency, allowances, reason, changed_by)
+    VALUES (row.id, row.identity_number, lower(TG_OP), row.effective_date, row.base_salary,
+        row.currency, row.pay_frequency, row.allowances, row.reason, actor);
+
+    RETURN row;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_compensation_history ON compensation_records;
+CREATE TRIGGER trg_compensation_history
+AFTER INSERT OR UPDATE OR DELETE ON compensation_records
+FOR EACH ROW EXECUTE FUNCTION record_compensation_history();
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS reviews;
+DROP TABLE IF EXISTS review_cycles;
+DROP TABLE IF EXISTS review_templates;
\ No newline at end of file
@@ -0,0 +1,64 @@
+-- questions is a JSON array of {"key", "text", "type", "required"}; rating
+-- questions are answered on the template's scale from scale_min to scale_max.
+CREATE TABLE IF NOT EXISTS review_templates (
+    id SERIAL PRIMARY KEY,
+    userId INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    questions JSONB NOT NULL,
+    scale_min SMALLINT NOT NULL DEFAULT 1,
+    scale_max SMALLINT NOT NULL DEFAULT 5,
+    scale_labels JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (scale_min < scale_max),
+    UNIQUE (userId, name),
+    FOREIGN KEY (userId) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- A cycle copies its template so later template changes cannot alter reviews in flight
+CREATE TABLE IF NOT EXISTS review_cycles (
+    id SERIAL PRIMARY KEY,
+    userId INT NOT NULL,
+    template_id INT NOT NULL,
+    template JSONB NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    period VARCHAR(10) CHECK (period IN ('quarterly', 'annual')) NOT NULL,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    self_due DATE NOT NULL,
+    manager_due DATE NOT NULL,
+    status VARCHAR(10) CHECK (status IN ('open', 'closed')) NOT NULL DEFAULT 'open',
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    closed_at TIMESTAMP,
+    CHECK (period_end >= period_start),
+    CHECK (manager_due >= self_due),
+    UNIQUE (userId, name),
+    FOREIGN KEY (userId) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (template_id) REFERENCES review_templates(id)
+);
+
+-- reviewer_identity_number is the department head when the cycle was launched;
+-- without one, the tenant's admins write the manager assessment.
+CREATE TABLE IF NOT EXISTS reviews (
+    id SERIAL PRIMARY KEY,
+    cycle_id INT NOT NULL,
+    identity_number VARCHAR(50) NOT NULL,
+    department_id INT NOT NULL,
+    reviewer_identity_number VARCHAR(50),
+    status VARCHAR(20) CHECK (status IN ('pending', 'self_submitted', 'manager_submitted', 'finalized')) NOT NULL DEFAULT 'pending',
+    self_answers JSONB,
+    self_submitted_at TIMESTAMP,
+    manager_answers JSONB,
+    manager_submitted_at TIMESTAMP,
+    manager_submitted_by VARCHAR(255),
+    overall_rating NUMERIC(4, 2),
+    finalized_at TIMESTAMP,
+    finalized_by VARCHAR(255),
+    UNIQUE (cycle_id, identity_number),
+    FOREIGN KEY (cycle_id) REFERENCES review_cycles(id) ON DELETE CASCADE,
+    FOREIGN KEY (identity_number) REFERENCES employees(identity_number) ON UPDATE CASCADE ON DELETE CASCADE,
+    FOREIGN KEY (reviewer_identity_number) REFERENCES employees(identity_number) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviews_identity_number ON reviews (identity_number);
+CREATE INDEX IF NOT EXISTS idx_reviews_cycle_department ON reviews (cycle_id, department_id, status);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS checklist_tasks;
+DROP TABLE IF EXISTS checklists;
+DROP TABLE IF EXISTS checklist_templates;
\ No newline at end of file
@@ -0,0 +1,55 @@
+-- tasks is a JSON array of {"title", "description", "assignee", "dueOffsetDays"};
+-- due dates are counted from the hire date for onboarding and from the
+-- termination date for offboarding. A template without a department applies to all.
+CREATE TABLE IF NOT EXISTS checklist_templates (
+    id SERIAL PRIMARY KEY,
+    userId INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    kind VARCHAR(12) CHECK (kind IN ('onboarding', 'offboarding')) NOT NULL,
+    department_id INT,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    tasks JSONB NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (userId, name),
+    FOREIGN KEY (userId) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (department_id) REFERENCES department(id) ON DELETE CASCADE
+);
+
+-- Checklists outlive the employee: offboarding starts when the employee is
+-- deleted, so identity_number has no foreign key and the name is copied.
+CREATE TABLE IF NOT EXISTS checklists (
+    id SERIAL PRIMARY KEY,
+    userId INT NOT NULL,
+    template_id INT,
+    name VARCHAR(100) NOT NULL,
+    kind VARCHAR(12) CHECK (kind IN ('onboarding', 'offboarding')) NOT NULL,
+    identity_number VARCHAR(50) NOT NULL,
+    employee_name VARCHAR(100) NOT NULL,
+    department_id INT NOT NULL,
+    anchor_date DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP,
+    FOREIGN KEY (userId) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (template_id) REFERENCES checklist_templates(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_checklists_identity_number ON checklists (identity_number, kind);
+CREATE INDEX IF NOT EXISTS idx_checklists_userid ON checklists (userId, completed_at);
+
+CREATE TABLE IF NOT EXISTS checklist_tasks (
+    id SERIAL PRIMARY KEY,
+    checklist_id INT NOT NULL,
+    position SMALLINT NOT NULL,
+    title VARCHAR(100) NOT NULL,
+    description VARCHAR(500),
+    assignee VARCHAR(100) NOT NULL,
+    due_date DATE NOT NULL,
+    completed_at TIMESTAMP,
+    completed_by VARCHAR(255),
+    FOREIGN KEY (checklist_id) REFERENCES checklists(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_checklist_tasks_checklist_id ON checklist_tasks (checklist_id, position);
+CREATE INDEX IF NOT EXISTS idx_checklist_tasks_open_due_date
+    ON checklist_tasks (due_date) WHERE completed_at IS NULL;
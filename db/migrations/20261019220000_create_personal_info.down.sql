@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS personal_info_access_log;
+DROP TABLE IF EXISTS emergency_contacts;
+DROP TABLE IF EXISTS employee_addresses;
\ No newline at end of file
@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS employee_addresses (
+    identity_number VARCHAR(50) PRIMARY KEY,
+    line1 VARCHAR(200) NOT NULL,
+    line2 VARCHAR(200),
+    city VARCHAR(100) NOT NULL,
+    region VARCHAR(100),
+    postal_code VARCHAR(20) NOT NULL,
+    country CHAR(2) NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (identity_number) REFERENCES employees(identity_number) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS emergency_contacts (
+    id SERIAL PRIMARY KEY,
+    identity_number VARCHAR(50) NOT NULL,
+    position INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    relationship VARCHAR(50) NOT NULL,
+    phone VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (identity_number, position),
+    FOREIGN KEY (identity_number) REFERENCES employees(identity_number) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+-- Kept after the employee is deleted, so there is no foreign key on
+-- identity_number
+CREATE TABLE IF NOT EXISTS personal_info_access_log (
+    id SERIAL PRIMARY KEY,
+    userId INT NOT NULL,
+    identity_number VARCHAR(50) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    action VARCHAR(10) CHECK (action IN ('read', 'update')) NOT NULL,
+    accessed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (userId) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_personal_info_access_log_user
+    ON personal_info_access_log (userId, accessed_at DESC);
+CREATE INDEX IF NOT EXISTS idx_personal_info_access_log_identity_number
+    ON personal_info_access_log (identity_number, accessed_at DESC);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS employee_accounts;
+
+ALTER TABLE employees_history
+DROP COLUMN IF EXISTS phone;
+
+ALTER TABLE employees
+DROP COLUMN IF EXISTS phone;
\ No newline at end of file
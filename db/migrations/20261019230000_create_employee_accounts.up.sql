@@ -0,0 +1,22 @@
+ALTER TABLE employees
+ADD COLUMN IF NOT EXISTS phone VARCHAR(20);
+
+-- record_employee_history copies the column into new versions
+ALTER TABLE employees_history
+ADD COLUMN IF NOT EXISTS phone VARCHAR(20);
+
+-- A self-service login for one employee. The account is invited first and
+-- becomes active once the employee sets a password with the invite token,
+-- of which only the SHA-256 hash is stored.
+CREATE TABLE IF NOT EXISTS employee_accounts (
+    id SERIAL PRIMARY KEY,
+    identity_number VARCHAR(50) NOT NULL UNIQUE,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    password VARCHAR(255),
+    invite_token_hash CHAR(64) UNIQUE,
+    invite_expires_at TIMESTAMP,
+    invited_by VARCHAR(255) NOT NULL,
+    invited_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    activated_at TIMESTAMP,
+    FOREIGN KEY (identity_number) REFERENCES employees(identity_number) ON UPDATE CASCADE ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+ALTER TABLE users
+DROP COLUMN IF EXISTS identity_scheme;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- The national identity number scheme employees of the tenant are checked
+-- against; the schemes themselves are registered in code
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS identity_scheme VARCHAR(20) NOT NULL DEFAULT 'none';
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_employee_status_transitions_terminations;
+
+ALTER TABLE employee_status_transitions
+DROP CONSTRAINT IF EXISTS employee_status_transitions_termination_check;
+
+ALTER TABLE employee_status_transitions
+DROP COLUMN IF EXISTS regretted,
+DROP COLUMN IF EXISTS termination_type;
\ No newline at end of file
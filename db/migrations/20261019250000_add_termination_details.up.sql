@@ -0,0 +1,13 @@
+-- How a termination came about, for the turnover metrics. Terminations
+-- recorded before these columns existed stay unclassified.
+ALTER TABLE employee_status_transitions
+ADD COLUMN IF NOT EXISTS termination_type VARCHAR(20)
+    CHECK (termination_type IN ('voluntary', 'involuntary')),
+ADD COLUMN IF NOT EXISTS regretted BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE employee_status_transitions
+ADD CONSTRAINT employee_status_transitions_termination_check
+    CHECK (to_status = 'terminated' OR (termination_type IS NULL AND NOT regretted));
+
+CREATE INDEX IF NOT EXISTS idx_employee_status_transitions_terminations
+    ON employee_status_transitions (effective_date) WHERE to_status = 'terminated';
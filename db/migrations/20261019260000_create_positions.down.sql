@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS trg_status_transitions_vacate_position ON employee_status_transitions;
+DROP TRIGGER IF EXISTS trg_employees_vacate_position ON employees;
+DROP TRIGGER IF EXISTS trg_positions_vacancy ON positions;
+DROP FUNCTION IF EXISTS vacate_terminated_employee_position();
+DROP FUNCTION IF EXISTS vacate_moved_employee_position();
+DROP FUNCTION IF EXISTS track_position_vacancy();
+DROP TABLE IF EXISTS headcount_plans;
+DROP TABLE IF EXISTS positions;
\ No newline at end of file
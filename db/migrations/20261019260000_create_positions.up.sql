@@ -0,0 +1,77 @@
+-- A position is a planned seat in a department, filled by at most one employee.
+-- identity_number is NULL while the position is vacant; vacant_since is kept
+-- by trg_positions_vacancy and is NULL while it is filled.
+CREATE TABLE IF NOT EXISTS positions (
+    id SERIAL PRIMARY KEY,
+    userId INT NOT NULL,
+    department_id INT NOT NULL,
+    job_title VARCHAR(100) NOT NULL,
+    level VARCHAR(30),
+    identity_number VARCHAR(50) UNIQUE,
+    vacant_since TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (userId) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (department_id) REFERENCES department(id) ON DELETE CASCADE,
+    FOREIGN KEY (identity_number) REFERENCES employees(identity_number) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_positions_department_id ON positions (department_id, job_title);
+CREATE INDEX IF NOT EXISTS idx_positions_vacant ON positions (userId, vacant_since) WHERE identity_number IS NULL;
+
+-- The number of employees a department is planned to have
+CREATE TABLE IF NOT EXISTS headcount_plans (
+    department_id INT PRIMARY KEY,
+    planned INT NOT NULL CHECK (planned >= 0),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (department_id) REFERENCES department(id) ON DELETE CASCADE
+);
+
+CREATE OR REPLACE FUNCTION track_position_vacancy() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.identity_number IS NOT NULL THEN
+        NEW.vacant_since = NULL;
+    ELSIF TG_OP = 'INSERT' OR OLD.identity_number IS NOT NULL THEN
+        NEW.vacant_since = CURRENT_TIMESTAMP;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- A seat belongs to its department, so moving the employee elsewhere frees it
+CREATE OR REPLACE FUNCTION vacate_moved_employee_position() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE positions
+    SET identity_number = NULL, updated_at = CURRENT_TIMESTAMP
+    WHERE identity_number = NEW.identity_number AND department_id <> NEW.department_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- A termination frees the seat as soon as it is recorded, so the replacement
+-- can be hired during the notice period
+CREATE OR REPLACE FUNCTION vacate_terminated_employee_position() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE positions
+    SET identity_number = NULL, updated_at = CURRENT_TIMESTAMP
+    WHERE identity_number = NEW.identity_number;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_positions_vacancy ON positions;
+CREATE TRIGGER trg_positions_vacancy
+BEFORE INSERT OR UPDATE ON positions
+FOR EACH ROW EXECUTE FUNCTION track_position_vacancy();
+
+DROP TRIGGER IF EXISTS trg_employees_vacate_position ON employees;
+CREATE TRIGGER trg_employees_vacate_position
+AFTER UPDATE OF department_id ON employees
+FOR EACH ROW WHEN (NEW.department_id IS DISTINCT FROM OLD.department_id)
+EXECUTE FUNCTION vacate_moved_employee_position();
+
+DROP TRIGGER IF EXISTS trg_status_transitions_vacate_position ON employee_status_transitions;
+CREATE TRIGGER trg_status_transitions_vacate_position
+AFTER INSERT ON employee_status_transitions
+FOR EACH ROW WHEN (NEW.to_status = 'terminated')
+EXECUTE FUNCTION vacate_terminated_employee_position();
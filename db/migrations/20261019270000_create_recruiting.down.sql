@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS candidate_notes;
+DROP TABLE IF EXISTS candidates;
+DROP TABLE IF EXISTS job_openings;
\ No newline at end of file
@@ -0,0 +1,57 @@
+-- An opening advertises a role in a department. Linking it to a position lets
+-- the hire fill that seat; such an opening closes once it is filled.
+CREATE TABLE IF NOT EXISTS job_openings (
+    id SERIAL PRIMARY KEY,
+    userId INT NOT NULL,
+    department_id INT NOT NULL,
+    position_id INT,
+    title VARCHAR(100) NOT NULL,
+    description VARCHAR(2000),
+    status VARCHAR(6) CHECK (status IN ('open', 'closed')) NOT NULL DEFAULT 'open',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (userId) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (department_id) REFERENCES department(id) ON DELETE CASCADE,
+    FOREIGN KEY (position_id) REFERENCES positions(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_openings_userid ON job_openings (userId, status);
+
+-- cv_key is the storage key of the uploaded CV. identity_number is set by the
+-- hire and links the candidate to the employee created from them.
+CREATE TABLE IF NOT EXISTS candidates (
+    id SERIAL PRIMARY KEY,
+    opening_id INT NOT NULL,
+    name VARCHAR(33) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    phone VARCHAR(20),
+    stage VARCHAR(9) CHECK (stage IN ('applied', 'screening', 'interview', 'offer', 'hired', 'rejected')) NOT NULL DEFAULT 'applied',
+    cv_key VARCHAR(512),
+    cv_file_name VARCHAR(255),
+    cv_content_type VARCHAR(100),
+    cv_size_bytes BIGINT,
+    identity_number VARCHAR(50),
+    hired_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (opening_id, email),
+    FOREIGN KEY (opening_id) REFERENCES job_openings(id) ON DELETE CASCADE,
+    FOREIGN KEY (identity_number) REFERENCES employees(identity_number) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_candidates_opening_id ON candidates (opening_id, stage);
+
+-- Interview and screening feedback; stage is the candidate's stage when the
+-- note was written
+CREATE TABLE IF NOT EXISTS candidate_notes (
+    id SERIAL PRIMARY KEY,
+    candidate_id INT NOT NULL,
+    stage VARCHAR(9) NOT NULL,
+    rating SMALLINT CHECK (rating BETWEEN 1 AND 5),
+    note VARCHAR(2000) NOT NULL,
+    author VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (candidate_id) REFERENCES candidates(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_candidate_notes_candidate_id ON candidate_notes (candidate_id, created_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_employees_department_id;
+DROP INDEX IF EXISTS idx_positions_job_title_trgm;
+DROP INDEX IF EXISTS idx_positions_job_title_tsv;
\ No newline at end of file
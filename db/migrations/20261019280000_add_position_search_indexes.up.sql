@@ -0,0 +1,8 @@
+-- Lets the employee search match position titles through an index
+CREATE INDEX IF NOT EXISTS idx_positions_job_title_tsv
+    ON positions USING gin (to_tsvector('simple', immutable_unaccent(job_title)));
+CREATE INDEX IF NOT EXISTS idx_positions_job_title_trgm
+    ON positions USING gin (immutable_unaccent(lower(job_title)) gin_trgm_ops);
+
+-- Finds the employees of the departments a search matched
+CREATE INDEX IF NOT EXISTS idx_employees_department_id ON employees (department_id);
@@ -0,0 +1,33 @@
+package jobs
+
+import (
+	"context"
+	"go-go-manager/repositories"
+	"log"
+	"time"
+)
+
+// RunCertificationReminders flags certifications that lapse within days, once
+// at start-up and then every interval, until ctx is cancelled. Flagged
+// certifications are listed by GET /certification/lapsing?flagged=true.
+func RunCertificationReminders(ctx context.Context, repo *repositories.EmployeeRepository, days int, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		flagged, err := repo.FlagLapsingCertifications(ctx, days)
+		if err != nil {
+			log.Printf("certification reminders: %v", err)
+		}
+		for _, c := range flagged {
+			log.Printf("certification reminders: %q of %s (%s) expires on %s",
+				c.Name, c.EmployeeName, c.IdentityNumber, *c.ExpiresOn)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -5,8 +5,12 @@ import (
 	"fmt"
 	"go-go-manager/config"
 	"go-go-manager/db"
+	"go-go-manager/jobs"
+	"go-go-manager/repositories"
 	"go-go-manager/routes"
 	"log"
+	"strconv"
+	"time"
 
 	awsSdkCfg "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
@@ -45,6 +49,17 @@ func main() {
 	// Create S3 client
 	s3Client := s3.NewFromConfig(awsCfg)
 
+	// Scheduled jobs
+	reminderDays, err := strconv.Atoi(cfg.CertificationReminderDays)
+	if err != nil || reminderDays < 0 {
+		log.Fatalf("Invalid CERTIFICATION_REMINDER_DAYS: %q", cfg.CertificationReminderDays)
+	}
+	reminderInterval, err := time.ParseDuration(cfg.CertificationReminderInterval)
+	if err != nil || reminderInterval <= 0 {
+		log.Fatalf("Invalid CERTIFICATION_REMINDER_INTERVAL: %q", cfg.CertificationReminderInterval)
+	}
+	go jobs.RunCertificationReminders(context.Background(), repositories.NewEmployeeRepository(db.DB), reminderDays, reminderInterval)
+
 	r := routes.SetupRouter(cfg, db.DB, s3Client, bucketName)
 
 	fmt.Printf("Starting server on port %s...\n", cfg.AppPort)
@@ -0,0 +1,199 @@
+package models
+
+import "math"
+
+// TenureBuckets are the tenure ranges of the headcount report, in order.
+var TenureBuckets = []string{"<1y", "1-2y", "2-5y", "5-10y", "10y+"}
+
+type DepartmentHeadcount struct {
+	DepartmentID string `json:"departmentId"`
+	Name         string `json:"name"`
+	Headcount    int    `json:"headcount"`
+	Male         int    `json:"male"`
+	Female       int    `json:"female"`
+}
+
+// AnalyticsPoint is one labelled value of a chart series.
+type AnalyticsPoint struct {
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// MonthlyFlow counts the employees hired (or rehired) and terminated in a
+// month, given as YYYY-MM.
+type MonthlyFlow struct {
+	Month   string `json:"month"`
+	Hires   int    `json:"hires"`
+	Leavers int    `json:"leavers"`
+}
+
+// HeadcountReport describes the workforce on To and how it changed from From.
+// Every series lists all of its labels, including those with a zero count.
+type HeadcountReport struct {
+	From         string                `json:"from"`
+	To           string                `json:"to"`
+	Total        int                   `json:"total"`
+	Departments  []DepartmentHeadcount `json:"departments"`
+	Gender       []AnalyticsPoint      `json:"gender"`
+	Tenure       []AnalyticsPoint      `json:"tenure"`
+	MonthlyFlows []MonthlyFlow         `json:"monthlyFlows"`
+}
+
+// TurnoverMonth holds the attrition figures of one month. Rates are
+// percentages and nil when there is nothing to divide by; the 90-day retention
+// only covers hires whose first 90 days have passed.
+type TurnoverMonth struct {
+	Month                string   `json:"month"`
+	StartHeadcount       int      `json:"startHeadcount"`
+	EndHeadcount         int      `json:"endHeadcount"`
+	Stayed               int      `json:"-"` // Employed at both the start and the end
+	Hires                int      `json:"hires"`
+	Leavers              int      `json:"leavers"`
+	VoluntaryLeavers     int      `json:"voluntaryLeavers"`
+	InvoluntaryLeavers   int      `json:"involuntaryLeavers"`
+	UnclassifiedLeavers  int      `json:"unclassifiedLeavers"` // Terminations recorded without a type
+	RegrettedLeavers     int      `json:"regrettedLeavers"`
+	TurnoverRate         *float64 `json:"turnoverRate"`
+	VoluntaryRate        *float64 `json:"voluntaryRate"`
+	InvoluntaryRate      *float64 `json:"involuntaryRate"`
+	RetentionRate        *float64 `json:"retentionRate"` // Share of the starting headcount still employed at the end
+	TenureDays           int      `json:"-"`
+	AverageTenureYears   *float64 `json:"averageTenureYears"`
+	NewHireCohort        int      `json:"newHireCohort"`
+	NewHireRetained      int      `json:"newHireRetained"`
+	NewHireRetentionRate *float64 `json:"newHireRetentionRate"`
+}
+
+// TurnoverCounts are the raw figures of one month for one department and
+// gender.
+type TurnoverCounts struct {
+	TurnoverMonth
+	DepartmentID   string
+	DepartmentName string
+	Gender         Gender
+}
+
+func (m *TurnoverMonth) add(c TurnoverMonth) {
+	m.StartHeadcount += c.StartHeadcount
+	m.EndHeadcount += c.EndHeadcount
+	m.Stayed += c.Stayed
+	m.Hires += c.Hires
+	m.Leavers += c.Leavers
+	m.VoluntaryLeavers += c.VoluntaryLeavers
+	m.InvoluntaryLeavers += c.InvoluntaryLeavers
+	m.UnclassifiedLeavers += c.UnclassifiedLeavers
+	m.RegrettedLeavers += c.RegrettedLeavers
+	m.TenureDays += c.TenureDays
+	m.NewHireCohort += c.NewHireCohort
+	m.NewHireRetained += c.NewHireRetained
+}
+
+func percentage(part int, whole float64) *float64 {
+	if whole <= 0 {
+		return nil
+	}
+	rate := math.Round(float64(part)/whole*10000) / 100
+	return &rate
+}
+
+// computeRates fills the rates from the counts. Turnover is measured against
+// the average of the starting and ending headcount.
+func (m *TurnoverMonth) computeRates() {
+	average := float64(m.StartHeadcount+m.EndHeadcount) / 2
+	m.TurnoverRate = percentage(m.Leavers, average)
+	m.VoluntaryRate = percentage(m.VoluntaryLeavers, average)
+	m.InvoluntaryRate = percentage(m.InvoluntaryLeavers, average)
+	m.RetentionRate = percentage(m.Stayed, float64(m.StartHeadcount))
+	m.NewHireRetentionRate = percentage(m.NewHireRetained, float64(m.NewHireCohort))
+	if m.EndHeadcount > 0 {
+		years := math.Round(float64(m.TenureDays)/float64(m.EndHeadcount)/365.25*100) / 100
+		m.AverageTenureYears = &years
+	}
+}
+
+type DepartmentTurnover struct {
+	DepartmentID string          `json:"departmentId"`
+	Name         string          `json:"name"`
+	Months       []TurnoverMonth `json:"months"`
+}
+
+type GenderTurnover struct {
+	Gender Gender          `json:"gender"`
+	Months []TurnoverMonth `json:"months"`
+}
+
+type TurnoverReport struct {
+	From        string               `json:"from"`
+	To          string               `json:"to"`
+	Overall     []TurnoverMonth      `json:"overall"`
+	Departments []DepartmentTurnover `json:"departments"`
+	Genders     []GenderTurnover     `json:"genders"`
+}
+
+// NewTurnoverReport rolls the counts up into the overall, per-department and
+// per-gender series. Every series has an entry for each of months, in order.
+func NewTurnoverReport(from string, to string, months []string, counts []TurnoverCounts) TurnoverReport {
+	index := make(map[string]int, len(months))
+	series := func() []TurnoverMonth {
+		s := make([]TurnoverMonth, len(months))
+		for i, month := range months {
+			s[i].Month = month
+			index[month] = i
+		}
+		return s
+	}
+
+	report := TurnoverReport{
+		From:    from,
+		To:      to,
+		Overall: series(),
+		Genders: []GenderTurnover{
+			{Gender: Male, Months: series()},
+			{Gender: Female, Months: series()},
+		},
+		Departments: []DepartmentTurnover{},
+	}
+
+	departments := make(map[string]int)
+	for _, c := range counts {
+		i, ok := index[c.Month]
+		if !ok {
+			continue
+		}
+		report.Overall[i].add(c.TurnoverMonth)
+
+		for g := range report.Genders {
+			if report.Genders[g].Gender == c.Gender {
+				report.Genders[g].Months[i].add(c.TurnoverMonth)
+			}
+		}
+
+		d, ok := departments[c.DepartmentID]
+		if !ok {
+			d = len(report.Departments)
+			departments[c.DepartmentID] = d
+			report.Departments = append(report.Departments, DepartmentTurnover{
+				DepartmentID: c.DepartmentID,
+				Name:         c.DepartmentName,
+				Months:       series(),
+			})
+		}
+		report.Departments[d].Months[i].add(c.TurnoverMonth)
+	}
+
+	for i := range report.Overall {
+		report.Overall[i].computeRates()
+	}
+	for g := range report.Genders {
+		for i := range report.Genders[g].Months {
+			report.Genders[g].Months[i].computeRates()
+		}
+	}
+	for d := range report.Departments {
+		for i := range report.Departments[d].Months {
+			report.Departments[d].Months[i].computeRates()
+		}
+	}
+
+	return report
+}
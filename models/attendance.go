@@ -0,0 +1,253 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"go-go-manager/db"
+	"math"
+	"sort"
+	"time"
+)
+
+type AttendanceEventType string
+
+const (
+	ClockIn  AttendanceEventType = "in"
+	ClockOut AttendanceEventType = "out"
+)
+
+type AttendanceEvent struct {
+	ID             int                 `json:"id"`
+	IdentityNumber string              `json:"identityNumber"`
+	Type           AttendanceEventType `json:"type"`
+	OccurredAt     time.Time           `json:"occurredAt"`
+	Note           *string             `json:"note"`
+	Location       *string             `json:"location"`
+	CreatedAt      string              `json:"createdAt"`
+}
+
+// WorkSchedule is the tenant's standard working week. WorkDays holds ISO
+// weekdays, 1 for Monday to 7 for Sunday. Days are cut in Timezone.
+type WorkSchedule struct {
+	DailyHours float64 `json:"dailyHours" binding:"required,gt=0,lte=24"`
+	WorkDays   []int   `json:"workDays" binding:"required,max=7,dive,min=1,max=7"`
+	Timezone   string  `json:"timezone" binding:"required,max=64"`
+}
+
+var DefaultWorkSchedule = WorkSchedule{DailyHours: 8, WorkDays: []int{1, 2, 3, 4, 5}, Timezone: "UTC"}
+
+func (s WorkSchedule) Location() (*time.Location, error) {
+	return time.LoadLocation(s.Timezone)
+}
+
+func (s WorkSchedule) IsWorkDay(day time.Time) bool {
+	weekday := int(day.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	for _, d := range s.WorkDays {
+		if d == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+type TimesheetDay struct {
+	Date           string     `json:"date"`
+	WorkDay        bool       `json:"workDay"`
+	FirstIn        *time.Time `json:"firstIn"`
+	LastOut        *time.Time `json:"lastOut"`
+	WorkedHours    float64    `json:"workedHours"`
+	ExpectedHours  float64    `json:"expectedHours"`
+	OvertimeHours  float64    `json:"overtimeHours"`
+	MissingPunches []string   `json:"missingPunches"`
+}
+
+type TimesheetWeek struct {
+	WeekStart     string  `json:"weekStart"` // Monday
+	WorkedHours   float64 `json:"workedHours"`
+	ExpectedHours float64 `json:"expectedHours"`
+	OvertimeHours float64 `json:"overtimeHours"`
+}
+
+type TimesheetApproval struct {
+	ID             int     `json:"id"`
+	IdentityNumber string  `json:"identityNumber"`
+	PeriodStart    string  `json:"periodStart"`
+	PeriodEnd      string  `json:"periodEnd"`
+	WorkedHours    float64 `json:"workedHours"`
+	OvertimeHours  float64 `json:"overtimeHours"`
+	ApprovedBy     string  `json:"approvedBy"`
+	Note           *string `json:"note"`
+	CreatedAt      string  `json:"createdAt"`
+}
+
+type Timesheet struct {
+	IdentityNumber string              `json:"identityNumber"`
+	From           string              `json:"from"`
+	To             string              `json:"to"`
+	WorkedHours    float64             `json:"workedHours"`
+	OvertimeHours  float64             `json:"overtimeHours"`
+	MissingPunches int                 `json:"missingPunches"`
+	Days           []TimesheetDay      `json:"days"`
+	Weeks          []TimesheetWeek     `json:"weeks"`
+	Approvals      []TimesheetApproval `json:"approvals"`
+}
+
+type MissingPunch struct {
+	IdentityNumber string `json:"identityNumber"`
+	Name           string `json:"name"`
+	DepartmentID   string `json:"departmentId"`
+	Date           string `json:"date"`
+	Issue          string `json:"issue"`
+}
+
+// maxShift is how long a clock-in may stay open before its clock-out counts as missing.
+const maxShift = 24 * time.Hour
+
+func roundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
+
+// BuildTimesheet pairs clock-ins with the following clock-outs and sums the
+// worked time per day from from to to, both dates in the schedule's timezone.
+// A shift counts towards the day it started on. Events outside the period may
+// be passed so that shifts crossing its edges pair up correctly.
+func BuildTimesheet(identityNumber string, events []AttendanceEvent, schedule WorkSchedule, loc *time.Location, from time.Time, to time.Time, now time.Time) Timesheet {
+	sheet := Timesheet{
+		IdentityNumber: identityNumber,
+		From:           from.Format(time.DateOnly),
+		To:             to.Format(time.DateOnly),
+		Days:           []TimesheetDay{},
+		Weeks:          []TimesheetWeek{},
+		Approvals:      []TimesheetApproval{},
+	}
+
+	days := make(map[string]*TimesheetDay)
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		day := TimesheetDay{Date: d.Format(time.DateOnly), WorkDay: schedule.IsWorkDay(d), MissingPunches: []string{}}
+		if day.WorkDay {
+			day.ExpectedHours = schedule.DailyHours
+		}
+		sheet.Days = append(sheet.Days, day)
+	}
+	for i := range sheet.Days {
+		days[sheet.Days[i].Date] = &sheet.Days[i]
+	}
+
+	dayOf := func(t time.Time) *TimesheetDay {
+		return days[t.In(loc).Format(time.DateOnly)]
+	}
+	clock := func(t time.Time) string {
+		return t.In(loc).Format("15:04")
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].OccurredAt.Before(events[j].OccurredAt) })
+
+	var open *AttendanceEvent
+	for i := range events {
+		e := &events[i]
+		switch e.Type {
+		case ClockIn:
+			if open != nil {
+				if day := dayOf(open.OccurredAt); day != nil {
+					day.MissingPunches = append(day.MissingPunches, "clock-out missing after clock-in at "+clock(open.OccurredAt))
+				}
+			}
+			open = e
+		case ClockOut:
+			if open == nil {
+				if day := dayOf(e.OccurredAt); day != nil {
+					day.MissingPunches = append(day.MissingPunches, "clock-in missing before clock-out at "+clock(e.OccurredAt))
+				}
+				continue
+			}
+
+			if day := dayOf(open.OccurredAt); day != nil {
+				day.WorkedHours += e.OccurredAt.Sub(open.OccurredAt).Hours()
+				if day.FirstIn == nil {
+					day.FirstIn = &open.OccurredAt
+				}
+				day.LastOut = &e.OccurredAt
+			}
+			open = nil
+		}
+	}
+
+	// A shift that is still running is not missing its clock-out yet
+	if open != nil && now.Sub(open.OccurredAt) > maxShift {
+		if day := dayOf(open.OccurredAt); day != nil {
+			day.MissingPunches = append(day.MissingPunches, "clock-out missing after clock-in at "+clock(open.OccurredAt))
+		}
+	}
+
+	weeks := make(map[string]int)
+	for i := range sheet.Days {
+		day := &sheet.Days[i]
+		day.WorkedHours = roundHours(day.WorkedHours)
+		day.OvertimeHours = roundHours(math.Max(0, day.WorkedHours-day.ExpectedHours))
+
+		sheet.WorkedHours += day.WorkedHours
+		sheet.OvertimeHours += day.OvertimeHours
+		sheet.MissingPunches += len(day.MissingPunches)
+
+		date, _ := time.Parse(time.DateOnly, day.Date)
+		weekStart := date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7)).Format(time.DateOnly)
+		index, ok := weeks[weekStart]
+		if !ok {
+			sheet.Weeks = append(sheet.Weeks, TimesheetWeek{WeekStart: weekStart})
+			index = len(sheet.Weeks) - 1
+			weeks[weekStart] = index
+		}
+		week := &sheet.Weeks[index]
+		week.WorkedHours = roundHours(week.WorkedHours + day.WorkedHours)
+		week.ExpectedHours = roundHours(week.ExpectedHours + day.ExpectedHours)
+		week.OvertimeHours = roundHours(week.OvertimeHours + day.OvertimeHours)
+	}
+
+	sheet.WorkedHours = roundHours(sheet.WorkedHours)
+	sheet.OvertimeHours = roundHours(sheet.OvertimeHours)
+	return sheet
+}
+
+// GetWorkSchedule returns the tenant's schedule, or the default one when the
+// tenant has not configured any.
+func GetWorkSchedule(userID uint) (WorkSchedule, error) {
+	query := "SELECT daily_hours, work_days, timezone FROM work_schedules WHERE userId = $1"
+
+	var schedule WorkSchedule
+	var workDays []byte
+	err := db.DB.QueryRow(query, userID).Scan(&schedule.DailyHours, &workDays, &schedule.Timezone)
+	if err == sql.ErrNoRows {
+		return DefaultWorkSchedule, nil
+	}
+	if err != nil {
+		return WorkSchedule{}, fmt.Errorf("failed to fetch work schedule: %v", err)
+	}
+
+	if err := json.Unmarshal(workDays, &schedule.WorkDays); err != nil {
+		return WorkSchedule{}, fmt.Errorf("failed to decode work days: %v", err)
+	}
+	return schedule, nil
+}
+
+func SaveWorkSchedule(userID uint, schedule WorkSchedule) (WorkSchedule, error) {
+	workDays, err := json.Marshal(schedule.WorkDays)
+	if err != nil {
+		return WorkSchedule{}, err
+	}
+
+	query := `
+		INSERT INTO work_schedules (userId, daily_hours, work_days, timezone)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (userId) DO UPDATE
+		SET daily_hours = EXCLUDED.daily_hours, work_days = EXCLUDED.work_days,
+			timezone = EXCLUDED.timezone, updated_at = CURRENT_TIMESTAMP
+	`
+	if _, err := db.DB.Exec(query, userID, schedule.DailyHours, workDays, schedule.Timezone); err != nil {
+		return WorkSchedule{}, fmt.Errorf("failed to save work schedule: %v", err)
+	}
+	return schedule, nil
+}
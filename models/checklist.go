@@ -0,0 +1,174 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"go-go-manager/db"
+)
+
+type ChecklistKind string
+
+const (
+	Onboarding  ChecklistKind = "onboarding"
+	Offboarding ChecklistKind = "offboarding"
+)
+
+// ChecklistTaskTemplate is due DueOffsetDays after the hire or termination
+// date; a negative offset makes it due before.
+type ChecklistTaskTemplate struct {
+	Title         string  `json:"title" binding:"required,min=1,max=100"`
+	Description   *string `json:"description" binding:"omitempty,max=500"`
+	Assignee      string  `json:"assignee" binding:"required,min=1,max=100"`
+	DueOffsetDays int     `json:"dueOffsetDays" binding:"min=-90,max=365"`
+}
+
+type ChecklistTemplate struct {
+	ID           int                     `json:"id"`
+	Name         string                  `json:"name" binding:"required,min=2,max=100"`
+	Kind         ChecklistKind           `json:"kind" binding:"required,oneof=onboarding offboarding"`
+	DepartmentID *string                 `json:"departmentId"` // Every department when null
+	Active       bool                    `json:"active"`
+	Tasks        []ChecklistTaskTemplate `json:"tasks" binding:"required,min=1,max=100,dive"`
+	CreatedAt    string                  `json:"createdAt"`
+}
+
+type ChecklistTask struct {
+	ID          int     `json:"id"`
+	ChecklistID int     `json:"checklistId"`
+	Position    int     `json:"position"`
+	Title       string  `json:"title"`
+	Description *string `json:"description"`
+	Assignee    string  `json:"assignee"`
+	DueDate     string  `json:"dueDate"`
+	CompletedAt *string `json:"completedAt"`
+	CompletedBy *string `json:"completedBy"`
+}
+
+type Checklist struct {
+	ID             int             `json:"id"`
+	TemplateID     *int            `json:"templateId"`
+	Name           string          `json:"name"`
+	Kind           ChecklistKind   `json:"kind"`
+	IdentityNumber string          `json:"identityNumber"`
+	EmployeeName   string          `json:"employeeName"`
+	DepartmentID   string          `json:"departmentId"`
+	AnchorDate     string          `json:"anchorDate"` // Hire or termination date
+	TotalTasks     int             `json:"totalTasks"`
+	CompletedTasks int             `json:"completedTasks"`
+	CreatedAt      string          `json:"createdAt"`
+	CompletedAt    *string         `json:"completedAt"`
+	Tasks          []ChecklistTask `json:"tasks,omitempty"`
+}
+
+type OverdueTask struct {
+	ChecklistTask
+	ChecklistName  string        `json:"checklistName"`
+	Kind           ChecklistKind `json:"kind"`
+	IdentityNumber string        `json:"identityNumber"`
+	EmployeeName   string        `json:"employeeName"`
+	DepartmentID   string        `json:"departmentId"`
+	DaysOverdue    int           `json:"daysOverdue"`
+}
+
+const checklistTemplateColumns = "id, name, kind, department_id::TEXT, active, tasks, created_at::TEXT"
+
+func scanChecklistTemplate(scan func(dest ...interface{}) error) (ChecklistTemplate, error) {
+	var t ChecklistTemplate
+	var tasks []byte
+	if err := scan(&t.ID, &t.Name, &t.Kind, &t.DepartmentID, &t.Active, &tasks, &t.CreatedAt); err != nil {
+		return t, err
+	}
+	err := json.Unmarshal(tasks, &t.Tasks)
+	return t, err
+}
+
+func CreateChecklistTemplate(userID uint, t ChecklistTemplate) (ChecklistTemplate, error) {
+	tasks, err := json.Marshal(t.Tasks)
+	if err != nil {
+		return ChecklistTemplate{}, err
+	}
+
+	query := `
+		INSERT INTO checklist_templates (userId, name, kind, department_id, active, tasks)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + checklistTemplateColumns
+
+	created, err := scanChecklistTemplate(db.DB.QueryRow(query, userID, t.Name, t.Kind, t.DepartmentID, t.Active, tasks).Scan)
+	if err != nil {
+		return ChecklistTemplate{}, fmt.Errorf("failed to create checklist template: %v", err)
+	}
+	return created, nil
+}
+
+// GetChecklistTemplates lists the tenant's templates. An empty kind returns both kinds.
+func GetChecklistTemplates(userID uint, kind string) ([]ChecklistTemplate, error) {
+	query := "SELECT " + checklistTemplateColumns + " FROM checklist_templates WHERE userId = $1 AND ($2 = '' OR kind = $2) ORDER BY kind, name"
+
+	rows, err := db.DB.Query(query, userID, kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch checklist templates: %v", err)
+	}
+	defer rows.Close()
+
+	templates := []ChecklistTemplate{}
+	for rows.Next() {
+		t, err := scanChecklistTemplate(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan checklist template: %v", err)
+		}
+		templates = append(templates, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating checklist templates: %v", err)
+	}
+
+	return templates, nil
+}
+
+func FindChecklistTemplateById(userID uint, id string) (ChecklistTemplate, error) {
+	query := "SELECT " + checklistTemplateColumns + " FROM checklist_templates WHERE id = $1 AND userId = $2"
+
+	t, err := scanChecklistTemplate(db.DB.QueryRow(query, id, userID).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ChecklistTemplate{}, fmt.Errorf("no checklist template found with id %s", id)
+		}
+		return ChecklistTemplate{}, err
+	}
+	return t, nil
+}
+
+func FindChecklistTemplateByName(userID uint, name string) (ChecklistTemplate, error) {
+	query := "SELECT " + checklistTemplateColumns + " FROM checklist_templates WHERE LOWER(name) = LOWER($1) AND userId = $2"
+
+	t, err := scanChecklistTemplate(db.DB.QueryRow(query, name, userID).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ChecklistTemplate{}, fmt.Errorf("no checklist template found with name %s", name)
+		}
+		return ChecklistTemplate{}, err
+	}
+	return t, nil
+}
+
+// UpdateChecklistTemplate only affects checklists started afterwards.
+func UpdateChecklistTemplate(userID uint, t ChecklistTemplate) (ChecklistTemplate, error) {
+	tasks, err := json.Marshal(t.Tasks)
+	if err != nil {
+		return ChecklistTemplate{}, err
+	}
+
+	query := `
+		UPDATE checklist_templates
+		SET name = $1, kind = $2, department_id = $3, active = $4, tasks = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $6 AND userId = $7
+		RETURNING ` + checklistTemplateColumns
+
+	updated, err := scanChecklistTemplate(db.DB.QueryRow(query, t.Name, t.Kind, t.DepartmentID, t.Active, tasks, t.ID, userID).Scan)
+	if err != nil {
+		return ChecklistTemplate{}, fmt.Errorf("failed to update checklist template: %v", err)
+	}
+	return updated, nil
+}
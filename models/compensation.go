@@ -0,0 +1,88 @@
+package models
+
+type PayFrequency string
+
+const (
+	PayAnnual      PayFrequency = "annual"
+	PayMonthly     PayFrequency = "monthly"
+	PaySemimonthly PayFrequency = "semimonthly"
+	PayBiweekly    PayFrequency = "biweekly"
+	PayWeekly      PayFrequency = "weekly"
+)
+
+// PeriodsPerYear is how many times a year pay at this frequency is paid out.
+func (f PayFrequency) PeriodsPerYear() int {
+	switch f {
+	case PayAnnual:
+		return 1
+	case PayMonthly:
+		return 12
+	case PaySemimonthly:
+		return 24
+	case PayBiweekly:
+		return 26
+	case PayWeekly:
+		return 52
+	}
+	return 0
+}
+
+// Allowance is paid every pay period on top of the base salary.
+type Allowance struct {
+	Name   string  `json:"name" binding:"required,min=1,max=50"`
+	Amount float64 `json:"amount" binding:"gte=0"`
+}
+
+type Compensation struct {
+	ID             int          `json:"id"`
+	IdentityNumber string       `json:"identityNumber"`
+	EffectiveDate  string       `json:"effectiveDate" binding:"required,datetime=2006-01-02"`
+	BaseSalary     float64      `json:"baseSalary" binding:"gte=0"`
+	Currency       string       `json:"currency" binding:"required,iso4217"`
+	PayFrequency   PayFrequency `json:"payFrequency" binding:"required,oneof=annual monthly semimonthly biweekly weekly"`
+	Allowances     []Allowance  `json:"allowances" binding:"max=20,dive"`
+	Reason         *string      `json:"reason" binding:"omitempty,max=255"`
+	RecordedBy     string       `json:"recordedBy"`
+	CreatedAt      string       `json:"createdAt"`
+}
+
+// AnnualCost is the base salary and allowances paid over a year at this rate.
+func (c Compensation) AnnualCost() (base float64, allowances float64) {
+	periods := float64(c.PayFrequency.PeriodsPerYear())
+	for _, a := range c.Allowances {
+		allowances += a.Amount
+	}
+	return c.BaseSalary * periods, allowances * periods
+}
+
+type CompensationChange struct {
+	ID             int          `json:"id"`
+	CompensationID int          `json:"compensationId"`
+	Operation      string       `json:"operation"` // insert, update or delete
+	EffectiveDate  string       `json:"effectiveDate"`
+	BaseSalary     float64      `json:"baseSalary"`
+	Currency       string       `json:"currency"`
+	PayFrequency   PayFrequency `json:"payFrequency"`
+	Allowances     []Allowance  `json:"allowances"`
+	Reason         *string      `json:"reason"`
+	ChangedBy      string       `json:"changedBy"`
+	ChangedAt      string       `json:"changedAt"`
+}
+
+// PayrollCost is the annual cost of the employees of a department paid in one currency.
+type PayrollCost struct {
+	Currency         string  `json:"currency"`
+	Employees        int     `json:"employees"`
+	AnnualBase       float64 `json:"annualBase"`
+	AnnualAllowances float64 `json:"annualAllowances"`
+	AnnualTotal      float64 `json:"annualTotal"`
+	MonthlyTotal     float64 `json:"monthlyTotal"`
+}
+
+type DepartmentPayroll struct {
+	DepartmentID  string        `json:"departmentId"`
+	Name          string        `json:"name"`
+	Headcount     int           `json:"headcount"`
+	Uncompensated int           `json:"uncompensated"` // Employees without a record in force on the report date
+	Costs         []PayrollCost `json:"costs"`
+}
@@ -0,0 +1,308 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"go-go-manager/db"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type CustomFieldType string
+
+const (
+	CustomFieldString  CustomFieldType = "string"
+	CustomFieldNumber  CustomFieldType = "number"
+	CustomFieldDate    CustomFieldType = "date"
+	CustomFieldEnum    CustomFieldType = "enum"
+	CustomFieldBoolean CustomFieldType = "boolean"
+)
+
+var customFieldKeyPattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
+// CustomFieldDefinition is an extra employee attribute defined by a tenant.
+// Min and Max bound the length of string values and the value of numbers.
+type CustomFieldDefinition struct {
+	ID        int             `json:"id"`
+	Key       string          `json:"key" binding:"required,min=1,max=50"`
+	Label     string          `json:"label" binding:"required,min=1,max=100"`
+	Type      CustomFieldType `json:"type" binding:"required,oneof=string number date enum boolean"`
+	Required  bool            `json:"required"`
+	Options   []string        `json:"options"`
+	Min       *float64        `json:"min"`
+	Max       *float64        `json:"max"`
+	CreatedAt string          `json:"createdAt"`
+}
+
+// Check reports problems with the definition itself.
+func (d CustomFieldDefinition) Check() error {
+	if !customFieldKeyPattern.MatchString(d.Key) {
+		return fmt.Errorf("key must start with a letter and contain only letters, digits and underscores")
+	}
+	if d.Type == CustomFieldEnum && len(d.Options) == 0 {
+		return fmt.Errorf("enum fields need at least one option")
+	}
+	if d.Type != CustomFieldEnum && len(d.Options) > 0 {
+		return fmt.Errorf("options are only allowed on enum fields")
+	}
+	if (d.Min != nil || d.Max != nil) && d.Type != CustomFieldString && d.Type != CustomFieldNumber {
+		return fmt.Errorf("min and max are only allowed on string and number fields")
+	}
+	if d.Min != nil && d.Max != nil && *d.Min > *d.Max {
+		return fmt.Errorf("min cannot be greater than max")
+	}
+	return nil
+}
+
+// Normalize validates a JSON-decoded value against the definition and returns
+// it in its stored form.
+func (d CustomFieldDefinition) Normalize(value interface{}) (interface{}, error) {
+	switch d.Type {
+	case CustomFieldString:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", d.Key)
+		}
+		length := float64(utf8.RuneCountInString(s))
+		if d.Min != nil && length < *d.Min {
+			return nil, fmt.Errorf("%s must be at least %v characters", d.Key, *d.Min)
+		}
+		if d.Max != nil && length > *d.Max {
+			return nil, fmt.Errorf("%s must be at most %v characters", d.Key, *d.Max)
+		}
+		return s, nil
+	case CustomFieldNumber:
+		n, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a number", d.Key)
+		}
+		if d.Min != nil && n < *d.Min {
+			return nil, fmt.Errorf("%s must be at least %v", d.Key, *d.Min)
+		}
+		if d.Max != nil && n > *d.Max {
+			return nil, fmt.Errorf("%s must be at most %v", d.Key, *d.Max)
+		}
+		return n, nil
+	case CustomFieldDate:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a date (YYYY-MM-DD)", d.Key)
+		}
+		if _, err := time.Parse(time.DateOnly, s); err != nil {
+			return nil, fmt.Errorf("%s must be a date (YYYY-MM-DD)", d.Key)
+		}
+		return s, nil
+	case CustomFieldEnum:
+		s, ok := value.(string)
+		if ok {
+			for _, option := range d.Options {
+				if s == option {
+					return s, nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("%s must be one of %s", d.Key, strings.Join(d.Options, ", "))
+	case CustomFieldBoolean:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s must be true or false", d.Key)
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("%s has an unknown type", d.Key)
+	}
+}
+
+// Parse converts a value given as text, such as a query parameter or a
+// spreadsheet cell, and validates it.
+func (d CustomFieldDefinition) Parse(raw string) (interface{}, error) {
+	switch d.Type {
+	case CustomFieldNumber:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number", d.Key)
+		}
+		return d.Normalize(n)
+	case CustomFieldBoolean:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false", d.Key)
+		}
+		return d.Normalize(b)
+	default:
+		return d.Normalize(raw)
+	}
+}
+
+// ValidateCustomFields checks values against the tenant's definitions: unknown
+// keys are rejected and required fields must be present. It returns the
+// normalized values and one message per problem.
+func ValidateCustomFields(definitions []CustomFieldDefinition, values map[string]interface{}) (map[string]interface{}, []string) {
+	byKey := make(map[string]CustomFieldDefinition, len(definitions))
+	for _, d := range definitions {
+		byKey[d.Key] = d
+	}
+
+	normalized := make(map[string]interface{}, len(values))
+	problems := []string{}
+	for key, value := range values {
+		d, ok := byKey[key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s is not a defined custom field", key))
+			continue
+		}
+		v, err := d.Normalize(value)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		normalized[key] = v
+	}
+
+	for _, d := range definitions {
+		if _, ok := values[d.Key]; d.Required && !ok {
+			problems = append(problems, fmt.Sprintf("%s is required", d.Key))
+		}
+	}
+
+	return normalized, problems
+}
+
+const customFieldColumns = "id, key, label, type, required, options, min, max, created_at::TEXT"
+
+func scanCustomFieldDefinition(scan func(dest ...interface{}) error) (CustomFieldDefinition, error) {
+	var d CustomFieldDefinition
+	var options []byte
+	var min, max sql.NullFloat64
+	if err := scan(&d.ID, &d.Key, &d.Label, &d.Type, &d.Required, &options, &min, &max, &d.CreatedAt); err != nil {
+		return CustomFieldDefinition{}, err
+	}
+	if err := json.Unmarshal(options, &d.Options); err != nil {
+		return CustomFieldDefinition{}, fmt.Errorf("failed to decode options: %v", err)
+	}
+	if min.Valid {
+		d.Min = &min.Float64
+	}
+	if max.Valid {
+		d.Max = &max.Float64
+	}
+	return d, nil
+}
+
+func CreateCustomFieldDefinition(userID uint, d CustomFieldDefinition) (CustomFieldDefinition, error) {
+	options, err := json.Marshal(append([]string{}, d.Options...))
+	if err != nil {
+		return CustomFieldDefinition{}, err
+	}
+
+	query := `
+		INSERT INTO custom_field_definitions (userId, key, label, type, required, options, min, max)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + customFieldColumns
+
+	created, err := scanCustomFieldDefinition(db.DB.QueryRow(query, userID, d.Key, d.Label, d.Type, d.Required, options, d.Min, d.Max).Scan)
+	if err != nil {
+		return CustomFieldDefinition{}, fmt.Errorf("failed to create custom field: %v", err)
+	}
+	return created, nil
+}
+
+func GetCustomFieldDefinitions(userID uint) ([]CustomFieldDefinition, error) {
+	query := "SELECT " + customFieldColumns + " FROM custom_field_definitions WHERE userId = $1 ORDER BY key"
+
+	rows, err := db.DB.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch custom fields: %v", err)
+	}
+	defer rows.Close()
+
+	definitions := []CustomFieldDefinition{}
+	for rows.Next() {
+		d, err := scanCustomFieldDefinition(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan custom field: %v", err)
+		}
+		definitions = append(definitions, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating custom fields: %v", err)
+	}
+
+	return definitions, nil
+}
+
+func FindCustomFieldDefinition(userID uint, key string) (CustomFieldDefinition, error) {
+	query := "SELECT " + customFieldColumns + " FROM custom_field_definitions WHERE userId = $1 AND key = $2"
+
+	d, err := scanCustomFieldDefinition(db.DB.QueryRow(query, userID, key).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return CustomFieldDefinition{}, fmt.Errorf("no custom field found with key %s", key)
+		}
+		return CustomFieldDefinition{}, err
+	}
+	return d, nil
+}
+
+// UpdateCustomFieldDefinition changes the label and validation rules of a
+// field. The key and type are fixed once the field exists, since stored values
+// depend on them.
+func UpdateCustomFieldDefinition(userID uint, d CustomFieldDefinition) (CustomFieldDefinition, error) {
+	options, err := json.Marshal(append([]string{}, d.Options...))
+	if err != nil {
+		return CustomFieldDefinition{}, err
+	}
+
+	query := `
+		UPDATE custom_field_definitions
+		SET label = $1, required = $2, options = $3, min = $4, max = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE userId = $6 AND key = $7
+		RETURNING ` + customFieldColumns
+
+	updated, err := scanCustomFieldDefinition(db.DB.QueryRow(query, d.Label, d.Required, options, d.Min, d.Max, userID, d.Key).Scan)
+	if err != nil {
+		return CustomFieldDefinition{}, fmt.Errorf("failed to update custom field: %v", err)
+	}
+	return updated, nil
+}
+
+// DeleteCustomFieldDefinition removes the field and its values from every
+// employee of the tenant.
+func DeleteCustomFieldDefinition(userID uint, key string) error {
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM custom_field_definitions WHERE userId = $1 AND key = $2", userID, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete custom field: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %v", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("custom field with key %s not found", key)
+	}
+
+	query := `
+		UPDATE employees
+		SET custom_fields = custom_fields - $2::TEXT
+		WHERE custom_fields ? $2::TEXT
+			AND department_id IN (SELECT id FROM department WHERE userid = $1)
+	`
+	if _, err := tx.Exec(query, userID, key); err != nil {
+		return fmt.Errorf("failed to remove custom field values: %v", err)
+	}
+
+	return tx.Commit()
+}
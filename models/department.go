@@ -4,6 +4,10 @@ import (
 	"database/sql"
 	"fmt"
 	"go-go-manager/db"
+	"go-go-manager/utils"
+	"slices"
+	"strconv"
+	"time"
 )
 
 type Department struct {
@@ -25,41 +29,111 @@ func CreateDepartment(name string, userID uint) (Department, error) {
 	return department, nil
 }
 
-func GetDepartments(userID uint, limit int, offset int, name string) ([]Department, error) {
-	query := "SELECT id, name, created_at, updated_at FROM department WHERE userid = $1"
-	params := []interface{}{userID}
-	paramCount := 1
+// DepartmentKeyset lists the fields GET /department can be sorted by.
+var DepartmentKeyset = utils.Keyset{
+	Columns: map[string]utils.SortColumn{
+		"name":         {Expr: "COALESCE(name, '')", Type: "TEXT"},
+		"createdAt":    {Expr: "COALESCE(created_at, 'epoch'::TIMESTAMP)", Type: "TIMESTAMP"},
+		"departmentId": {Expr: "id", Type: "INTEGER"},
+	},
+	Key: utils.SortColumn{Expr: "id", Type: "INTEGER"},
+}
+
+// GetDepartments lists a page of the tenant's departments. With asOf the
+// departments are read as they were at that moment, including ones renamed or
+// deleted since, and are paged and sorted the same way; their creation time is
+// when their first version was recorded.
+func GetDepartments(userID uint, name string, asOf *time.Time, page utils.Page) ([]Department, utils.PageInfo, error) {
+	params := []interface{}{}
+	arg := func(value interface{}) string {
+		params = append(params, value)
+		return fmt.Sprintf("$%d", len(params))
+	}
+
+	from := "department"
+	if asOf != nil {
+		at := arg(*asOf)
+		from = `(
+			SELECT h.department_id AS id, h.name, h.userId,
+				(SELECT MIN(f.valid_from) FROM department_history f WHERE f.department_id = h.department_id)::TIMESTAMP AS created_at,
+				h.valid_from::TIMESTAMP AS updated_at
+			FROM department_history h
+			WHERE h.valid_from <= ` + at + ` AND (h.valid_to IS NULL OR h.valid_to > ` + at + `)
+		) department`
+	}
 
+	where := " WHERE userid = " + arg(userID)
 	if name != "" {
-		paramCount++
-		query += fmt.Sprintf(" AND LOWER(name) LIKE LOWER($%d)", paramCount)
-		params = append(params, "%"+name+"%")
+		where += " AND LOWER(name) LIKE LOWER(" + arg("%"+name+"%") + ")"
 	}
 
-	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", paramCount+1, paramCount+2)
-	params = append(params, limit, offset)
+	var info utils.PageInfo
+	if page.WithTotal {
+		var total int
+		if err := db.DB.QueryRow("SELECT COUNT(*) FROM "+from+where, params...).Scan(&total); err != nil {
+			return nil, info, fmt.Errorf("failed to count departments: %v", err)
+		}
+		info.Total = &total
+	}
+
+	condition, order, err := DepartmentKeyset.Clause(page, arg)
+	if err != nil {
+		return nil, info, err
+	}
+	sortExpr := DepartmentKeyset.Columns[page.Field()].Expr
+
+	query := "SELECT id, name, COALESCE(created_at::TEXT, ''), COALESCE(updated_at::TEXT, ''), " + sortExpr + "::TEXT FROM " + from +
+		where + " AND " + condition + " ORDER BY " + order
+
+	// One extra row tells whether there is a further page
+	query += " LIMIT " + arg(page.Limit+1)
+	if page.Cursor == nil && page.Offset > 0 {
+		query += " OFFSET " + arg(page.Offset)
+	}
 
 	rows, err := db.DB.Query(query, params...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch departments: %v", err)
+		return nil, info, fmt.Errorf("failed to fetch departments: %v", err)
 	}
 	defer rows.Close()
 
 	departments := []Department{}
+	sortValues := []string{}
 	for rows.Next() {
 		var dept Department
-		err := rows.Scan(&dept.ID, &dept.Name, &dept.CreatedAt, &dept.UpdatedAt)
+		var sortValue string
+		err := rows.Scan(&dept.ID, &dept.Name, &dept.CreatedAt, &dept.UpdatedAt, &sortValue)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan department: %v", err)
+			return nil, info, fmt.Errorf("failed to scan department: %v", err)
 		}
 		departments = append(departments, dept)
+		sortValues = append(sortValues, sortValue)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating departments: %v", err)
+		return nil, info, fmt.Errorf("error iterating departments: %v", err)
 	}
 
-	return departments, nil
+	fetched := len(departments)
+	if fetched > page.Limit {
+		departments, sortValues = departments[:page.Limit], sortValues[:page.Limit]
+	}
+
+	// A backwards page was read in reverse order
+	if page.Cursor != nil && page.Cursor.Prev {
+		slices.Reverse(departments)
+		slices.Reverse(sortValues)
+	}
+
+	if len(departments) > 0 {
+		first := [2]string{sortValues[0], strconv.Itoa(int(departments[0].ID))}
+		last := [2]string{sortValues[len(sortValues)-1], strconv.Itoa(int(departments[len(departments)-1].ID))}
+		total := info.Total
+		info = page.Info(fetched, first, last)
+		info.Total = total
+	}
+
+	return departments, info, nil
 }
 
 func FindDepartmentByName(name string) (Department, error) {
@@ -138,3 +212,99 @@ func CountEmployeesByDepartment(departmentId string) (int, error) {
 	}
 	return count, nil
 }
+
+type DepartmentFlow struct {
+	DepartmentID string `json:"departmentId"`
+	From         string `json:"from"`
+	To           string `json:"to"`
+	Inflow       int    `json:"inflow"`
+	Outflow      int    `json:"outflow"`
+	Net          int    `json:"net"`
+}
+
+// GetDepartmentFlow counts transfers into and out of a department between two dates, inclusive.
+func GetDepartmentFlow(departmentId string, from string, to string) (DepartmentFlow, error) {
+	query := `SELECT
+			COUNT(*) FILTER (WHERE to_department_id = $1),
+			COUNT(*) FILTER (WHERE from_department_id = $1)
+		FROM employee_transfers
+		WHERE (to_department_id = $1 OR from_department_id = $1)
+			AND effective_date BETWEEN $2 AND $3`
+
+	flow := DepartmentFlow{DepartmentID: departmentId, From: from, To: to}
+	err := db.DB.QueryRow(query, departmentId, from, to).Scan(&flow.Inflow, &flow.Outflow)
+	if err != nil {
+		return DepartmentFlow{}, fmt.Errorf("failed to count department transfers: %v", err)
+	}
+
+	flow.Net = flow.Inflow - flow.Outflow
+	return flow, nil
+}
+
+func FindDepartmentsByUser(userID uint) ([]Department, error) {
+	query := "SELECT id, name FROM department WHERE userid = $1 ORDER BY id"
+
+	rows, err := db.DB.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch departments: %v", err)
+	}
+	defer rows.Close()
+
+	departments := []Department{}
+	for rows.Next() {
+		var dept Department
+		if err := rows.Scan(&dept.ID, &dept.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan department: %v", err)
+		}
+		departments = append(departments, dept)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating departments: %v", err)
+	}
+
+	return departments, nil
+}
+
+type DepartmentExportRow struct {
+	DepartmentID string `json:"departmentId"`
+	Name         string `json:"name"`
+	Headcount    int    `json:"headcount"`
+	CreatedAt    string `json:"createdAt"`
+}
+
+// FindDepartmentHead returns the identity number of the department's head, or
+// nil when none is assigned.
+func FindDepartmentHead(departmentID string) (*string, error) {
+	var head *string
+	err := db.DB.QueryRow("SELECT head_identity_number FROM department WHERE id = $1", departmentID).Scan(&head)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch department head: %v", err)
+	}
+	return head, nil
+}
+
+// SetDepartmentHead assigns the head of a department; nil removes it.
+func SetDepartmentHead(departmentID string, identityNumber *string) error {
+	_, err := db.DB.Exec("UPDATE department SET head_identity_number = $1 WHERE id = $2", identityNumber, departmentID)
+	if err != nil {
+		return fmt.Errorf("failed to set department head: %v", err)
+	}
+	return nil
+}
+
+// IsTenantEmployee reports whether the employee works in one of the user's departments.
+func IsTenantEmployee(userID uint, identityNumber string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM employees e
+			JOIN department d ON d.id = e.department_id
+			WHERE e.identity_number = $1 AND d.userid = $2
+		)
+	`
+	var exists bool
+	if err := db.DB.QueryRow(query, identityNumber, userID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check employee: %v", err)
+	}
+	return exists, nil
+}
@@ -0,0 +1,39 @@
+package models
+
+type DocumentCategory string
+
+const (
+	DocumentContract    DocumentCategory = "contract"
+	DocumentIDScan      DocumentCategory = "id_scan"
+	DocumentCertificate DocumentCategory = "certificate"
+	DocumentOther       DocumentCategory = "other"
+)
+
+func (c DocumentCategory) IsValid() bool {
+	switch c {
+	case DocumentContract, DocumentIDScan, DocumentCertificate, DocumentOther:
+		return true
+	}
+	return false
+}
+
+type EmployeeDocument struct {
+	ID             int              `json:"id"`
+	IdentityNumber string           `json:"identityNumber"`
+	Category       DocumentCategory `json:"category"`
+	Title          string           `json:"title"`
+	FileName       string           `json:"fileName"`
+	ContentType    string           `json:"contentType"`
+	SizeBytes      int64            `json:"sizeBytes"`
+	StorageKey     string           `json:"-"`
+	ExpiresOn      *string          `json:"expiresOn"`
+	UploadedBy     string           `json:"uploadedBy"`
+	CreatedAt      string           `json:"createdAt"`
+}
+
+type ExpiringDocument struct {
+	EmployeeDocument
+	Name         string `json:"name"`
+	DepartmentID string `json:"departmentId"`
+	DaysLeft     int    `json:"daysLeft"` // Negative once the document has expired
+}
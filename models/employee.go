@@ -7,10 +7,98 @@ const (
 	Female Gender = "female"
 )
 
+type EmploymentStatus string
+
+const (
+	StatusOnboarding EmploymentStatus = "onboarding"
+	StatusActive     EmploymentStatus = "active"
+	StatusOnLeave    EmploymentStatus = "on_leave"
+	StatusTerminated EmploymentStatus = "terminated"
+)
+
+// allowedTransitions lists the statuses an employee may move to from each status.
+// Rehiring is a transition out of terminated back to onboarding or active.
+var allowedTransitions = map[EmploymentStatus][]EmploymentStatus{
+	StatusOnboarding: {StatusActive, StatusTerminated},
+	StatusActive:     {StatusOnLeave, StatusTerminated},
+	StatusOnLeave:    {StatusActive, StatusTerminated},
+	StatusTerminated: {StatusOnboarding, StatusActive},
+}
+
+func (s EmploymentStatus) IsValid() bool {
+	_, ok := allowedTransitions[s]
+	return ok
+}
+
+func (s EmploymentStatus) CanTransitionTo(next EmploymentStatus) bool {
+	for _, allowed := range allowedTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 type Employee struct {
-	IdentityNumber   string `json:"identityNumber" binding:"required,min=5,max=33"`
-	Name             string `json:"name" binding:"required,min=4,max=33"`
-	Gender           Gender `json:"gender" binding:"required"` // Enum: "male" or "female"
-	DepartmentID     string `json:"departmentId" binding:"required"`
-	EmployeeImageURI string `json:"employeeImageUri" binding:"required,uri,isImage"` // New field
+	IdentityNumber   string                 `json:"identityNumber" binding:"required,min=5,max=33"`
+	Name             string                 `json:"name" binding:"required,min=4,max=33"`
+	Gender           Gender                 `json:"gender" binding:"required"` // Enum: "male" or "female"
+	DepartmentID     string                 `json:"departmentId" binding:"required"`
+	EmployeeImageURI string                 `json:"employeeImageUri" binding:"required,uri,isImage"` // New field
+	Phone            *string                `json:"phone,omitempty" binding:"omitempty,e164"`        // E.164, e.g. +6281234567890
+	CustomFields     map[string]interface{} `json:"customFields,omitempty"`                          // Checked against the tenant's custom field definitions
+	Status           EmploymentStatus       `json:"status,omitempty"`                                // Read-only, derived from status transitions
+	CreatedAt        string                 `json:"createdAt,omitempty"`                             // Read-only
+}
+
+type TerminationType string
+
+const (
+	TerminationVoluntary   TerminationType = "voluntary"   // The employee resigned
+	TerminationInvoluntary TerminationType = "involuntary" // The employer ended the employment
+)
+
+type StatusTransition struct {
+	ID              int              `json:"id"`
+	IdentityNumber  string           `json:"identityNumber"`
+	FromStatus      EmploymentStatus `json:"fromStatus,omitempty"`
+	ToStatus        EmploymentStatus `json:"toStatus"`
+	EffectiveDate   string           `json:"effectiveDate"`
+	Reason          string           `json:"reason"`
+	TerminationType *TerminationType `json:"terminationType,omitempty"` // Only on terminations
+	Regretted       bool             `json:"regretted,omitempty"`       // A loss the company wanted to avoid
+	CreatedAt       string           `json:"createdAt"`
+}
+
+type EmployeeVersion struct {
+	IdentityNumber   string  `json:"identityNumber"`
+	Name             string  `json:"name"`
+	Gender           Gender  `json:"gender"`
+	DepartmentID     string  `json:"departmentId"`
+	EmployeeImageURI string  `json:"employeeImageUri"`
+	ValidFrom        string  `json:"validFrom"`
+	ValidTo          *string `json:"validTo"` // nil for the current version
+}
+
+type EmployeeExportRow struct {
+	IdentityNumber   string           `json:"identityNumber"`
+	Name             string           `json:"name"`
+	Gender           Gender           `json:"gender"`
+	DepartmentID     string           `json:"departmentId"`
+	DepartmentName   string           `json:"departmentName"`
+	EmployeeImageURI string           `json:"employeeImageUri"`
+	Status           EmploymentStatus `json:"status"`
+}
+
+type EmployeeSearchResult struct {
+	IdentityNumber   string            `json:"identityNumber"`
+	Name             string            `json:"name"`
+	Gender           Gender            `json:"gender"`
+	DepartmentID     string            `json:"departmentId"`
+	DepartmentName   string            `json:"departmentName"`
+	EmployeeImageURI string            `json:"employeeImageUri"`
+	Status           EmploymentStatus  `json:"status"`
+	JobTitle         string            `json:"jobTitle,omitempty"` // Title of the position they hold
+	Score            float64           `json:"score"`
+	Highlights       map[string]string `json:"highlights"` // Matched terms wrapped in <mark>
 }
@@ -0,0 +1,22 @@
+package models
+
+type AccountStatus string
+
+const (
+	AccountInvited AccountStatus = "invited"
+	AccountActive  AccountStatus = "active"
+)
+
+// EmployeeAccount is an employee's self-service login. UserID is the tenant
+// the employee belongs to.
+type EmployeeAccount struct {
+	ID              int           `json:"id"`
+	UserID          uint          `json:"-"`
+	IdentityNumber  string        `json:"identityNumber"`
+	Email           string        `json:"email"`
+	Status          AccountStatus `json:"status"`
+	InvitedBy       string        `json:"invitedBy"`
+	InvitedAt       string        `json:"invitedAt"`
+	InviteExpiresAt *string       `json:"inviteExpiresAt"`
+	ActivatedAt     *string       `json:"activatedAt"`
+}
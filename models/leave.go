@@ -0,0 +1,199 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"go-go-manager/db"
+	"math"
+	"time"
+)
+
+type LeaveAccrual string
+
+const (
+	AccrualYearly  LeaveAccrual = "yearly"  // The whole allowance is available from the start of the year
+	AccrualMonthly LeaveAccrual = "monthly" // One twelfth of the allowance per month
+	AccrualNone    LeaveAccrual = "none"    // No balance is tracked, e.g. unpaid leave
+)
+
+type LeaveStatus string
+
+const (
+	LeavePending   LeaveStatus = "pending"
+	LeaveApproved  LeaveStatus = "approved"
+	LeaveRejected  LeaveStatus = "rejected"
+	LeaveCancelled LeaveStatus = "cancelled"
+)
+
+type LeaveType struct {
+	ID           int          `json:"id"`
+	Name         string       `json:"name" binding:"required,min=2,max=50"`
+	Paid         bool         `json:"paid"`
+	Accrual      LeaveAccrual `json:"accrual" binding:"required,oneof=yearly monthly none"`
+	DaysPerYear  float64      `json:"daysPerYear" binding:"min=0,max=365"`
+	MaxCarryOver float64      `json:"maxCarryOver" binding:"min=0,max=365"`
+	CreatedAt    string       `json:"createdAt"`
+}
+
+// Entitlement returns the days accrued in year up to asOf. Employees hired
+// during the year accrue from their hire month.
+func (t LeaveType) Entitlement(year int, hiredOn time.Time, asOf time.Time) float64 {
+	if t.Accrual == AccrualNone || year < hiredOn.Year() || year > asOf.Year() {
+		return 0
+	}
+
+	firstMonth := 1
+	if year == hiredOn.Year() {
+		firstMonth = int(hiredOn.Month())
+	}
+
+	lastMonth := 12
+	if t.Accrual == AccrualMonthly && year == asOf.Year() {
+		lastMonth = int(asOf.Month())
+	}
+
+	months := lastMonth - firstMonth + 1
+	if months <= 0 {
+		return 0
+	}
+	return math.Round(t.DaysPerYear*float64(months)/12*100) / 100
+}
+
+type LeaveRequest struct {
+	ID             int         `json:"id"`
+	IdentityNumber string      `json:"identityNumber"`
+	LeaveTypeID    int         `json:"leaveTypeId"`
+	LeaveType      string      `json:"leaveType"`
+	StartDate      string      `json:"startDate"`
+	EndDate        string      `json:"endDate"`
+	Days           float64     `json:"days"`
+	Reason         string      `json:"reason"`
+	Status         LeaveStatus `json:"status"`
+	DecidedBy      *string     `json:"decidedBy"`
+	DecisionNote   *string     `json:"decisionNote"`
+	DecidedAt      *string     `json:"decidedAt"`
+	CreatedAt      string      `json:"createdAt"`
+}
+
+// LeaveBalance is an employee's position for one leave type in one year.
+// Entitlement and Available are nil for leave types without a balance.
+type LeaveBalance struct {
+	LeaveTypeID int      `json:"leaveTypeId"`
+	LeaveType   string   `json:"leaveType"`
+	Year        int      `json:"year"`
+	Entitlement *float64 `json:"entitlement"`
+	CarriedOver float64  `json:"carriedOver"`
+	Taken       float64  `json:"taken"`
+	Pending     float64  `json:"pending"`
+	Available   *float64 `json:"available"`
+}
+
+type LeaveCalendarEntry struct {
+	LeaveID        int         `json:"leaveId"`
+	IdentityNumber string      `json:"identityNumber"`
+	Name           string      `json:"name"`
+	DepartmentID   string      `json:"departmentId"`
+	LeaveType      string      `json:"leaveType"`
+	StartDate      string      `json:"startDate"`
+	EndDate        string      `json:"endDate"`
+	Status         LeaveStatus `json:"status"`
+}
+
+// CountLeaveDays counts the working days (Monday to Friday) from start to end inclusive.
+func CountLeaveDays(start time.Time, end time.Time) float64 {
+	days := 0
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
+			days++
+		}
+	}
+	return float64(days)
+}
+
+const leaveTypeColumns = "id, name, paid, accrual, days_per_year, max_carry_over, created_at::TEXT"
+
+func scanLeaveType(scan func(dest ...interface{}) error) (LeaveType, error) {
+	var t LeaveType
+	err := scan(&t.ID, &t.Name, &t.Paid, &t.Accrual, &t.DaysPerYear, &t.MaxCarryOver, &t.CreatedAt)
+	return t, err
+}
+
+func CreateLeaveType(userID uint, t LeaveType) (LeaveType, error) {
+	query := `
+		INSERT INTO leave_types (userId, name, paid, accrual, days_per_year, max_carry_over)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + leaveTypeColumns
+
+	created, err := scanLeaveType(db.DB.QueryRow(query, userID, t.Name, t.Paid, t.Accrual, t.DaysPerYear, t.MaxCarryOver).Scan)
+	if err != nil {
+		return LeaveType{}, fmt.Errorf("failed to create leave type: %v", err)
+	}
+	return created, nil
+}
+
+func GetLeaveTypes(userID uint) ([]LeaveType, error) {
+	query := "SELECT " + leaveTypeColumns + " FROM leave_types WHERE userId = $1 ORDER BY name"
+
+	rows, err := db.DB.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch leave types: %v", err)
+	}
+	defer rows.Close()
+
+	types := []LeaveType{}
+	for rows.Next() {
+		t, err := scanLeaveType(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan leave type: %v", err)
+		}
+		types = append(types, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating leave types: %v", err)
+	}
+
+	return types, nil
+}
+
+func FindLeaveTypeById(userID uint, id string) (LeaveType, error) {
+	query := "SELECT " + leaveTypeColumns + " FROM leave_types WHERE id = $1 AND userId = $2"
+
+	t, err := scanLeaveType(db.DB.QueryRow(query, id, userID).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return LeaveType{}, fmt.Errorf("no leave type found with id %s", id)
+		}
+		return LeaveType{}, err
+	}
+	return t, nil
+}
+
+func FindLeaveTypeByName(userID uint, name string) (LeaveType, error) {
+	query := "SELECT " + leaveTypeColumns + " FROM leave_types WHERE LOWER(name) = LOWER($1) AND userId = $2"
+
+	t, err := scanLeaveType(db.DB.QueryRow(query, name, userID).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return LeaveType{}, fmt.Errorf("no leave type found with name %s", name)
+		}
+		return LeaveType{}, err
+	}
+	return t, nil
+}
+
+// UpdateLeaveType changes the policy of a leave type. Balances are always
+// computed from the current policy, so the change applies retroactively.
+func UpdateLeaveType(userID uint, t LeaveType) (LeaveType, error) {
+	query := `
+		UPDATE leave_types
+		SET name = $1, paid = $2, accrual = $3, days_per_year = $4, max_carry_over = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $6 AND userId = $7
+		RETURNING ` + leaveTypeColumns
+
+	updated, err := scanLeaveType(db.DB.QueryRow(query, t.Name, t.Paid, t.Accrual, t.DaysPerYear, t.MaxCarryOver, t.ID, userID).Scan)
+	if err != nil {
+		return LeaveType{}, fmt.Errorf("failed to update leave type: %v", err)
+	}
+	return updated, nil
+}
@@ -0,0 +1,198 @@
+package models
+
+import (
+	"math"
+	"sort"
+)
+
+// PayGapMinGroupSize is the fewest employees of each gender a group needs for
+// its pay figures to be reported.
+const PayGapMinGroupSize = 5
+
+// EmployeePay is an employee's annual pay, base plus allowances, under the
+// compensation record in force on the report date.
+type EmployeePay struct {
+	DepartmentID   string
+	DepartmentName string
+	Gender         Gender
+	Currency       string
+	AnnualPay      float64
+}
+
+// PayGapGroup compares the pay of men and women in one group. Gaps are the
+// difference as a percentage of the male figure, so a positive gap means men
+// are paid more. Pay figures are nil when the group is suppressed.
+type PayGapGroup struct {
+	Currency     string   `json:"currency"`
+	Scope        string   `json:"scope"` // overall, department or quartile
+	Group        string   `json:"group"` // The department name or Q1 (lowest paid) to Q4
+	DepartmentID *string  `json:"departmentId,omitempty"`
+	MaleCount    int      `json:"maleCount"`
+	FemaleCount  int      `json:"femaleCount"`
+	Suppressed   bool     `json:"suppressed"`
+	MaleMean     *float64 `json:"maleMean"`
+	FemaleMean   *float64 `json:"femaleMean"`
+	MeanGap      *float64 `json:"meanGap"`
+	MaleMedian   *float64 `json:"maleMedian"`
+	FemaleMedian *float64 `json:"femaleMedian"`
+	MedianGap    *float64 `json:"medianGap"`
+	FemaleShare  *float64 `json:"femaleShare,omitempty"` // Quartiles only: percentage of the quartile that is female
+}
+
+type PayGapReport struct {
+	AsOf         string        `json:"asOf"`
+	MinGroupSize int           `json:"minGroupSize"`
+	Groups       []PayGapGroup `json:"groups"`
+}
+
+func round2(v float64) *float64 {
+	rounded := math.Round(v*100) / 100
+	return &rounded
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// median expects sorted values.
+func median(values []float64) float64 {
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
+
+func newPayGapGroup(currency string, scope string, group string, pay []EmployeePay) PayGapGroup {
+	g := PayGapGroup{Currency: currency, Scope: scope, Group: group}
+
+	var male, female []float64
+	for _, p := range pay {
+		switch p.Gender {
+		case Male:
+			male = append(male, p.AnnualPay)
+		case Female:
+			female = append(female, p.AnnualPay)
+		}
+	}
+	g.MaleCount = len(male)
+	g.FemaleCount = len(female)
+
+	if g.MaleCount < PayGapMinGroupSize || g.FemaleCount < PayGapMinGroupSize {
+		g.Suppressed = true
+		return g
+	}
+
+	sort.Float64s(male)
+	sort.Float64s(female)
+	maleMean, femaleMean := mean(male), mean(female)
+	maleMedian, femaleMedian := median(male), median(female)
+
+	g.MaleMean, g.FemaleMean = round2(maleMean), round2(femaleMean)
+	g.MaleMedian, g.FemaleMedian = round2(maleMedian), round2(femaleMedian)
+	if maleMean > 0 {
+		g.MeanGap = round2((maleMean - femaleMean) / maleMean * 100)
+	}
+	if maleMedian > 0 {
+		g.MedianGap = round2((maleMedian - femaleMedian) / maleMedian * 100)
+	}
+	return g
+}
+
+// suppress withholds the group's pay figures, keeping only its head counts.
+func (g *PayGapGroup) suppress() {
+	g.Suppressed = true
+	g.MaleMean, g.FemaleMean, g.MeanGap = nil, nil, nil
+	g.MaleMedian, g.FemaleMedian, g.MedianGap = nil, nil, nil
+	g.FemaleShare = nil
+}
+
+// suppressComplement takes groups that together make up the overall figures.
+// With exactly one of them suppressed, its mean could be worked out from the
+// overall mean and the others, so the smallest of the others is suppressed
+// with it.
+func suppressComplement(groups []PayGapGroup) {
+	suppressed := 0
+	smallest := -1
+	for i, g := range groups {
+		if g.Suppressed {
+			suppressed++
+			continue
+		}
+		if smallest < 0 || g.MaleCount+g.FemaleCount < groups[smallest].MaleCount+groups[smallest].FemaleCount {
+			smallest = i
+		}
+	}
+	if suppressed == 1 && smallest >= 0 {
+		groups[smallest].suppress()
+	}
+}
+
+// NewPayGapReport computes the gaps per currency, since pay in different
+// currencies is never compared: overall, per department and per pay quartile.
+// Departments and quartiles each add up to the overall figures, so a lone
+// suppressed department or quartile takes a sibling with it.
+func NewPayGapReport(asOf string, pay []EmployeePay) PayGapReport {
+	report := PayGapReport{AsOf: asOf, MinGroupSize: PayGapMinGroupSize, Groups: []PayGapGroup{}}
+
+	byCurrency := make(map[string][]EmployeePay)
+	currencies := []string{}
+	for _, p := range pay {
+		if _, ok := byCurrency[p.Currency]; !ok {
+			currencies = append(currencies, p.Currency)
+		}
+		byCurrency[p.Currency] = append(byCurrency[p.Currency], p)
+	}
+	sort.Strings(currencies)
+
+	for _, currency := range currencies {
+		employees := byCurrency[currency]
+		report.Groups = append(report.Groups, newPayGapGroup(currency, "overall", "all", employees))
+
+		departments := []string{}
+		byDepartment := make(map[string][]EmployeePay)
+		names := make(map[string]string)
+		for _, p := range employees {
+			if _, ok := byDepartment[p.DepartmentID]; !ok {
+				departments = append(departments, p.DepartmentID)
+				names[p.DepartmentID] = p.DepartmentName
+			}
+			byDepartment[p.DepartmentID] = append(byDepartment[p.DepartmentID], p)
+		}
+		sort.Slice(departments, func(i, j int) bool { return names[departments[i]] < names[departments[j]] })
+		departmentGroups := []PayGapGroup{}
+		for _, id := range departments {
+			g := newPayGapGroup(currency, "department", names[id], byDepartment[id])
+			departmentID := id
+			g.DepartmentID = &departmentID
+			departmentGroups = append(departmentGroups, g)
+		}
+		suppressComplement(departmentGroups)
+		report.Groups = append(report.Groups, departmentGroups...)
+
+		// Quartiles split the employees ranked by pay into four groups of
+		// (nearly) equal size
+		sorted := append([]EmployeePay{}, employees...)
+		sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].AnnualPay < sorted[j].AnnualPay })
+		quartileGroups := make([]PayGapGroup, 4)
+		quartileSizes := make([]int, 4)
+		for q := range quartileGroups {
+			quartile := sorted[q*len(sorted)/4 : (q+1)*len(sorted)/4]
+			quartileGroups[q] = newPayGapGroup(currency, "quartile", "Q"+string(rune('1'+q)), quartile)
+			quartileSizes[q] = len(quartile)
+		}
+		suppressComplement(quartileGroups)
+		for q, g := range quartileGroups {
+			if !g.Suppressed {
+				g.FemaleShare = round2(float64(g.FemaleCount) / float64(quartileSizes[q]) * 100)
+			}
+			report.Groups = append(report.Groups, g)
+		}
+	}
+
+	return report
+}
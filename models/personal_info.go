@@ -0,0 +1,42 @@
+package models
+
+type HomeAddress struct {
+	Line1      string  `json:"line1" binding:"required,min=1,max=200"`
+	Line2      *string `json:"line2" binding:"omitempty,max=200"`
+	City       string  `json:"city" binding:"required,min=1,max=100"`
+	Region     *string `json:"region" binding:"omitempty,max=100"`
+	PostalCode string  `json:"postalCode" binding:"required,min=1,max=20"`
+	Country    string  `json:"country" binding:"required,iso3166_1_alpha2"`
+}
+
+type EmergencyContact struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name" binding:"required,min=2,max=100"`
+	Relationship string `json:"relationship" binding:"required,min=2,max=50"`
+	Phone        string `json:"phone" binding:"required,e164"` // E.164, e.g. +6281234567890
+}
+
+// PersonalInfo is the sensitive part of an employee record. It is kept out of
+// the employee payloads and only served to elevated roles, with every access
+// logged. Contacts are listed in order of preference; who changed what is
+// in the access log.
+type PersonalInfo struct {
+	IdentityNumber    string             `json:"identityNumber"`
+	HomeAddress       *HomeAddress       `json:"homeAddress"`
+	EmergencyContacts []EmergencyContact `json:"emergencyContacts" binding:"required,min=1,max=5,dive"`
+}
+
+type PersonalInfoAction string
+
+const (
+	PersonalInfoRead   PersonalInfoAction = "read"
+	PersonalInfoUpdate PersonalInfoAction = "update"
+)
+
+type PersonalInfoAccess struct {
+	ID             int                `json:"id"`
+	IdentityNumber string             `json:"identityNumber"`
+	Actor          string             `json:"actor"`
+	Action         PersonalInfoAction `json:"action"`
+	AccessedAt     string             `json:"accessedAt"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"fmt"
+	"go-go-manager/db"
+)
+
+// Position is a planned seat in a department. A seat belongs to its
+// department, so it is vacated when its employee moves elsewhere or is
+// terminated.
+type Position struct {
+	ID             int     `json:"id"`
+	DepartmentID   string  `json:"departmentId" binding:"required"`
+	DepartmentName string  `json:"departmentName"` // Read-only
+	JobTitle       string  `json:"jobTitle" binding:"required,min=1,max=100"`
+	Level          *string `json:"level" binding:"omitempty,max=30"` // Free-form, e.g. "Senior" or "L3"
+	IdentityNumber *string `json:"identityNumber"`                   // nil while vacant
+	EmployeeName   *string `json:"employeeName"`                     // Read-only
+	VacantSince    *string `json:"vacantSince"`                      // Read-only, nil while filled
+	CreatedAt      string  `json:"createdAt"`
+}
+
+type Vacancy struct {
+	Position
+	DaysOpen int `json:"daysOpen"`
+}
+
+type HeadcountPlan struct {
+	DepartmentID string `json:"departmentId"`
+	Planned      int    `json:"planned" binding:"min=0,max=100000"`
+	UpdatedAt    string `json:"updatedAt"`
+}
+
+// DepartmentStaffing compares a department's headcount plan with the employees
+// it has and the positions set up to seat them.
+type DepartmentStaffing struct {
+	DepartmentID string `json:"departmentId"`
+	Name         string `json:"name"`
+	Planned      *int   `json:"planned"` // nil without a headcount plan
+	Actual       int    `json:"actual"`  // Employees not terminated, including those still onboarding
+	Gap          *int   `json:"gap"`     // Planned minus actual; positive when the department is under plan
+	Positions    int    `json:"positions"`
+	Filled       int    `json:"filled"`
+	Vacant       int    `json:"vacant"`
+}
+
+// SaveHeadcountPlan sets the planned headcount of a department, which must
+// already be checked to belong to the tenant.
+func SaveHeadcountPlan(plan HeadcountPlan) (HeadcountPlan, error) {
+	query := `
+		INSERT INTO headcount_plans (department_id, planned)
+		VALUES ($1, $2)
+		ON CONFLICT (department_id) DO UPDATE
+		SET planned = EXCLUDED.planned, updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at::TEXT
+	`
+	if err := db.DB.QueryRow(query, plan.DepartmentID, plan.Planned).Scan(&plan.UpdatedAt); err != nil {
+		return HeadcountPlan{}, fmt.Errorf("failed to save headcount plan: %v", err)
+	}
+	return plan, nil
+}
+
+func DeleteHeadcountPlan(departmentID string) error {
+	if _, err := db.DB.Exec("DELETE FROM headcount_plans WHERE department_id = $1", departmentID); err != nil {
+		return fmt.Errorf("failed to delete headcount plan: %v", err)
+	}
+	return nil
+}
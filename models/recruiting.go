@@ -0,0 +1,92 @@
+package models
+
+type OpeningStatus string
+
+const (
+	OpeningOpen   OpeningStatus = "open"
+	OpeningClosed OpeningStatus = "closed"
+)
+
+type CandidateStage string
+
+const (
+	StageApplied   CandidateStage = "applied"
+	StageScreening CandidateStage = "screening"
+	StageInterview CandidateStage = "interview"
+	StageOffer     CandidateStage = "offer"
+	StageHired     CandidateStage = "hired"
+	StageRejected  CandidateStage = "rejected"
+)
+
+// allowedStages lists the stages a candidate may move to from each stage.
+// Stages may be skipped on the way to an offer, and a candidate can be rejected
+// until hired. Hired is only reached through the hire action.
+var allowedStages = map[CandidateStage][]CandidateStage{
+	StageApplied:   {StageScreening, StageInterview, StageRejected},
+	StageScreening: {StageInterview, StageRejected},
+	StageInterview: {StageOffer, StageRejected},
+	StageOffer:     {StageRejected},
+	StageHired:     {},
+	StageRejected:  {},
+}
+
+func (s CandidateStage) IsValid() bool {
+	_, ok := allowedStages[s]
+	return ok
+}
+
+func (s CandidateStage) CanMoveTo(next CandidateStage) bool {
+	for _, allowed := range allowedStages[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+type JobOpening struct {
+	ID             int                    `json:"id"`
+	DepartmentID   string                 `json:"departmentId" binding:"required"`
+	DepartmentName string                 `json:"departmentName"` // Read-only
+	PositionID     *int                   `json:"positionId"`     // The seat the hire fills, if any
+	Title          string                 `json:"title" binding:"required,min=1,max=100"`
+	Description    *string                `json:"description" binding:"omitempty,max=2000"`
+	Status         OpeningStatus          `json:"status" binding:"omitempty,oneof=open closed"`
+	Pipeline       map[CandidateStage]int `json:"pipeline"` // Read-only, candidates per stage
+	CreatedAt      string                 `json:"createdAt"`
+}
+
+type Candidate struct {
+	ID             int            `json:"id"`
+	OpeningID      int            `json:"openingId"`
+	Name           string         `json:"name" binding:"required,min=4,max=33"` // Same rules as the employee name it becomes
+	Email          string         `json:"email" binding:"required,email,max=255"`
+	Phone          *string        `json:"phone" binding:"omitempty,e164"`
+	Stage          CandidateStage `json:"stage"` // Read-only, changed through stage moves
+	CV             *CandidateCV   `json:"cv"`
+	IdentityNumber *string        `json:"identityNumber"` // Read-only, the employee created by the hire
+	HiredAt        *string        `json:"hiredAt"`
+	CreatedAt      string         `json:"createdAt"`
+}
+
+type CandidateCV struct {
+	FileName    string `json:"fileName"`
+	ContentType string `json:"contentType"`
+	SizeBytes   int64  `json:"sizeBytes"`
+	StorageKey  string `json:"-"`
+}
+
+type CandidateNote struct {
+	ID          int            `json:"id"`
+	CandidateID int            `json:"candidateId"`
+	Stage       CandidateStage `json:"stage"` // The candidate's stage when the note was written
+	Rating      *int           `json:"rating" binding:"omitempty,min=1,max=5"`
+	Note        string         `json:"note" binding:"required,min=1,max=2000"`
+	Author      string         `json:"author"`
+	CreatedAt   string         `json:"createdAt"`
+}
+
+type CandidateDetails struct {
+	Candidate
+	Notes []CandidateNote `json:"notes"`
+}
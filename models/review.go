@@ -0,0 +1,279 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"go-go-manager/db"
+	"math"
+	"unicode/utf8"
+)
+
+type ReviewQuestionType string
+
+const (
+	ReviewRating ReviewQuestionType = "rating"
+	ReviewText   ReviewQuestionType = "text"
+)
+
+type ReviewPeriod string
+
+const (
+	ReviewQuarterly ReviewPeriod = "quarterly"
+	ReviewAnnual    ReviewPeriod = "annual"
+)
+
+type ReviewCycleStatus string
+
+const (
+	CycleOpen   ReviewCycleStatus = "open"
+	CycleClosed ReviewCycleStatus = "closed"
+)
+
+type ReviewStatus string
+
+const (
+	ReviewPending          ReviewStatus = "pending"
+	ReviewSelfSubmitted    ReviewStatus = "self_submitted"
+	ReviewManagerSubmitted ReviewStatus = "manager_submitted"
+	ReviewFinalized        ReviewStatus = "finalized"
+)
+
+// maxReviewAnswerLength bounds the answer to a text question, in characters.
+const maxReviewAnswerLength = 5000
+
+type ReviewQuestion struct {
+	Key      string             `json:"key" binding:"required,min=1,max=50"`
+	Text     string             `json:"text" binding:"required,min=1,max=500"`
+	Type     ReviewQuestionType `json:"type" binding:"required,oneof=rating text"`
+	Required bool               `json:"required"`
+}
+
+// ReviewTemplate holds the questions of a review and the scale its rating
+// questions and overall rating use. ScaleLabels, when set, names each point of
+// the scale from ScaleMin up.
+type ReviewTemplate struct {
+	ID          int              `json:"id"`
+	Name        string           `json:"name" binding:"required,min=2,max=100"`
+	Questions   []ReviewQuestion `json:"questions" binding:"required,min=1,max=50,dive"`
+	ScaleMin    int              `json:"scaleMin" binding:"min=0,max=10"`
+	ScaleMax    int              `json:"scaleMax" binding:"required,min=1,max=10"`
+	ScaleLabels []string         `json:"scaleLabels" binding:"max=11,dive,min=1,max=50"`
+	CreatedAt   string           `json:"createdAt"`
+}
+
+// Check reports problems with the template itself.
+func (t ReviewTemplate) Check() error {
+	if t.ScaleMin >= t.ScaleMax {
+		return fmt.Errorf("scaleMin must be lower than scaleMax")
+	}
+	if len(t.ScaleLabels) > 0 && len(t.ScaleLabels) != t.ScaleMax-t.ScaleMin+1 {
+		return fmt.Errorf("scaleLabels needs one label for each point of the scale")
+	}
+
+	keys := make(map[string]bool)
+	for _, q := range t.Questions {
+		if !customFieldKeyPattern.MatchString(q.Key) {
+			return fmt.Errorf("question keys must start with a letter and contain only letters, digits and underscores")
+		}
+		if keys[q.Key] {
+			return fmt.Errorf("question key %s is used twice", q.Key)
+		}
+		keys[q.Key] = true
+	}
+	return nil
+}
+
+// InScale reports whether rating lies on the template's scale.
+func (t ReviewTemplate) InScale(rating float64) bool {
+	return rating >= float64(t.ScaleMin) && rating <= float64(t.ScaleMax)
+}
+
+// ValidateAnswers checks answers against the questions and returns them with
+// unknown keys rejected, or the list of problems found.
+func (t ReviewTemplate) ValidateAnswers(answers map[string]interface{}) (map[string]interface{}, []string) {
+	var problems []string
+	valid := make(map[string]interface{})
+
+	known := make(map[string]bool)
+	for _, q := range t.Questions {
+		known[q.Key] = true
+
+		value, ok := answers[q.Key]
+		if !ok || value == nil {
+			if q.Required {
+				problems = append(problems, q.Key+" is required")
+			}
+			continue
+		}
+
+		switch q.Type {
+		case ReviewRating:
+			n, ok := value.(float64)
+			if !ok || n != math.Trunc(n) || !t.InScale(n) {
+				problems = append(problems, fmt.Sprintf("%s must be a whole number from %d to %d", q.Key, t.ScaleMin, t.ScaleMax))
+				continue
+			}
+			valid[q.Key] = n
+		case ReviewText:
+			s, ok := value.(string)
+			if !ok || utf8.RuneCountInString(s) > maxReviewAnswerLength {
+				problems = append(problems, fmt.Sprintf("%s must be text of at most %d characters", q.Key, maxReviewAnswerLength))
+				continue
+			}
+			valid[q.Key] = s
+		}
+	}
+
+	for key := range answers {
+		if !known[key] {
+			problems = append(problems, key+" is not a question of this review")
+		}
+	}
+
+	return valid, problems
+}
+
+type ReviewCycle struct {
+	ID          int               `json:"id"`
+	TemplateID  int               `json:"templateId"`
+	Template    ReviewTemplate    `json:"template"`
+	Name        string            `json:"name"`
+	Period      ReviewPeriod      `json:"period"`
+	PeriodStart string            `json:"periodStart"`
+	PeriodEnd   string            `json:"periodEnd"`
+	SelfDue     string            `json:"selfDue"`
+	ManagerDue  string            `json:"managerDue"`
+	Status      ReviewCycleStatus `json:"status"`
+	Reviews     int               `json:"reviews"`
+	CreatedBy   string            `json:"createdBy"`
+	CreatedAt   string            `json:"createdAt"`
+	ClosedAt    *string           `json:"closedAt"`
+}
+
+type Review struct {
+	ID                     int                    `json:"id"`
+	CycleID                int                    `json:"cycleId"`
+	CycleName              string                 `json:"cycleName"`
+	PeriodStart            string                 `json:"periodStart"`
+	PeriodEnd              string                 `json:"periodEnd"`
+	IdentityNumber         string                 `json:"identityNumber"`
+	Name                   string                 `json:"name"`
+	DepartmentID           string                 `json:"departmentId"`
+	ReviewerIdentityNumber *string                `json:"reviewerIdentityNumber"`
+	Status                 ReviewStatus           `json:"status"`
+	SelfAnswers            map[string]interface{} `json:"selfAnswers"`
+	SelfSubmittedAt        *string                `json:"selfSubmittedAt"`
+	ManagerAnswers         map[string]interface{} `json:"managerAnswers"`
+	ManagerSubmittedAt     *string                `json:"managerSubmittedAt"`
+	ManagerSubmittedBy     *string                `json:"managerSubmittedBy"`
+	OverallRating          *float64               `json:"overallRating"`
+	FinalizedAt            *string                `json:"finalizedAt"`
+	FinalizedBy            *string                `json:"finalizedBy"`
+}
+
+// ReviewProgress counts the reviews of one department in a cycle by status.
+// Overdue reviews are still waiting for an assessment whose deadline has passed.
+type ReviewProgress struct {
+	DepartmentID     string  `json:"departmentId"`
+	Name             string  `json:"name"`
+	Total            int     `json:"total"`
+	Pending          int     `json:"pending"`
+	SelfSubmitted    int     `json:"selfSubmitted"`
+	ManagerSubmitted int     `json:"managerSubmitted"`
+	Finalized        int     `json:"finalized"`
+	OverdueSelf      int     `json:"overdueSelf"`
+	OverdueManager   int     `json:"overdueManager"`
+	CompletionRate   float64 `json:"completionRate"`
+}
+
+const reviewTemplateColumns = "id, name, questions, scale_min, scale_max, scale_labels, created_at::TEXT"
+
+func scanReviewTemplate(scan func(dest ...interface{}) error) (ReviewTemplate, error) {
+	var t ReviewTemplate
+	var questions, labels []byte
+	if err := scan(&t.ID, &t.Name, &questions, &t.ScaleMin, &t.ScaleMax, &labels, &t.CreatedAt); err != nil {
+		return t, err
+	}
+	if err := json.Unmarshal(questions, &t.Questions); err != nil {
+		return t, err
+	}
+	err := json.Unmarshal(labels, &t.ScaleLabels)
+	return t, err
+}
+
+func CreateReviewTemplate(userID uint, t ReviewTemplate) (ReviewTemplate, error) {
+	if t.ScaleLabels == nil {
+		t.ScaleLabels = []string{}
+	}
+	questions, err := json.Marshal(t.Questions)
+	if err != nil {
+		return ReviewTemplate{}, err
+	}
+	labels, err := json.Marshal(t.ScaleLabels)
+	if err != nil {
+		return ReviewTemplate{}, err
+	}
+
+	query := `
+		INSERT INTO review_templates (userId, name, questions, scale_min, scale_max, scale_labels)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + reviewTemplateColumns
+
+	created, err := scanReviewTemplate(db.DB.QueryRow(query, userID, t.Name, questions, t.ScaleMin, t.ScaleMax, labels).Scan)
+	if err != nil {
+		return ReviewTemplate{}, fmt.Errorf("failed to create review template: %v", err)
+	}
+	return created, nil
+}
+
+func GetReviewTemplates(userID uint) ([]ReviewTemplate, error) {
+	query := "SELECT " + reviewTemplateColumns + " FROM review_templates WHERE userId = $1 ORDER BY name"
+
+	rows, err := db.DB.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch review templates: %v", err)
+	}
+	defer rows.Close()
+
+	templates := []ReviewTemplate{}
+	for rows.Next() {
+		t, err := scanReviewTemplate(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan review template: %v", err)
+		}
+		templates = append(templates, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating review templates: %v", err)
+	}
+
+	return templates, nil
+}
+
+func FindReviewTemplateById(userID uint, id int) (ReviewTemplate, error) {
+	query := "SELECT " + reviewTemplateColumns + " FROM review_templates WHERE id = $1 AND userId = $2"
+
+	t, err := scanReviewTemplate(db.DB.QueryRow(query, id, userID).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ReviewTemplate{}, fmt.Errorf("no review template found with id %d", id)
+		}
+		return ReviewTemplate{}, err
+	}
+	return t, nil
+}
+
+func FindReviewTemplateByName(userID uint, name string) (ReviewTemplate, error) {
+	query := "SELECT " + reviewTemplateColumns + " FROM review_templates WHERE LOWER(name) = LOWER($1) AND userId = $2"
+
+	t, err := scanReviewTemplate(db.DB.QueryRow(query, name, userID).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ReviewTemplate{}, fmt.Errorf("no review template found with name %s", name)
+		}
+		return ReviewTemplate{}, err
+	}
+	return t, nil
+}
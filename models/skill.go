@@ -0,0 +1,151 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"go-go-manager/db"
+)
+
+type Skill struct {
+	ID          int     `json:"id"`
+	Name        string  `json:"name" binding:"required,min=1,max=50"`
+	Category    *string `json:"category" binding:"omitempty,max=50"`
+	Description *string `json:"description" binding:"omitempty,max=255"`
+	CreatedAt   string  `json:"createdAt"`
+}
+
+// EmployeeSkill is an employee's proficiency in a catalog skill, from 1 (novice) to 5 (expert).
+type EmployeeSkill struct {
+	SkillID   int     `json:"skillId"`
+	Name      string  `json:"name"`
+	Category  *string `json:"category"`
+	Level     int     `json:"level"`
+	UpdatedAt string  `json:"updatedAt"`
+}
+
+type Certification struct {
+	ID             int     `json:"id"`
+	IdentityNumber string  `json:"identityNumber"`
+	Name           string  `json:"name" binding:"required,min=1,max=100"`
+	Issuer         *string `json:"issuer" binding:"omitempty,max=100"`
+	CredentialID   *string `json:"credentialId" binding:"omitempty,max=100"`
+	IssuedOn       string  `json:"issuedOn" binding:"required,datetime=2006-01-02"`
+	ExpiresOn      *string `json:"expiresOn" binding:"omitempty,datetime=2006-01-02"`
+	LapseFlaggedAt *string `json:"lapseFlaggedAt"`
+	CreatedAt      string  `json:"createdAt"`
+}
+
+type LapsingCertification struct {
+	Certification
+	EmployeeName string `json:"employeeName"`
+	DepartmentID string `json:"departmentId"`
+	DaysLeft     int    `json:"daysLeft"` // Negative once the certification has lapsed
+}
+
+// SkillCriterion asks for employees rated at least MinLevel in a skill.
+type SkillCriterion struct {
+	SkillID  int
+	MinLevel int
+}
+
+type SkillMatch struct {
+	IdentityNumber string          `json:"identityNumber"`
+	Name           string          `json:"name"`
+	DepartmentID   string          `json:"departmentId"`
+	Skills         []EmployeeSkill `json:"skills"`
+}
+
+const skillColumns = "id, name, category, description, created_at::TEXT"
+
+func scanSkill(scan func(dest ...interface{}) error) (Skill, error) {
+	var s Skill
+	err := scan(&s.ID, &s.Name, &s.Category, &s.Description, &s.CreatedAt)
+	return s, err
+}
+
+func CreateSkill(userID uint, s Skill) (Skill, error) {
+	query := `
+		INSERT INTO skills (userId, name, category, description)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + skillColumns
+
+	created, err := scanSkill(db.DB.QueryRow(query, userID, s.Name, s.Category, s.Description).Scan)
+	if err != nil {
+		return Skill{}, fmt.Errorf("failed to create skill: %v", err)
+	}
+	return created, nil
+}
+
+// GetSkills lists the tenant's catalog. An empty category returns every skill.
+func GetSkills(userID uint, category string) ([]Skill, error) {
+	query := "SELECT " + skillColumns + " FROM skills WHERE userId = $1 AND ($2 = '' OR category = $2) ORDER BY name"
+
+	rows, err := db.DB.Query(query, userID, category)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch skills: %v", err)
+	}
+	defer rows.Close()
+
+	skills := []Skill{}
+	for rows.Next() {
+		s, err := scanSkill(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan skill: %v", err)
+		}
+		skills = append(skills, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating skills: %v", err)
+	}
+
+	return skills, nil
+}
+
+func FindSkillById(userID uint, id string) (Skill, error) {
+	query := "SELECT " + skillColumns + " FROM skills WHERE id = $1 AND userId = $2"
+
+	s, err := scanSkill(db.DB.QueryRow(query, id, userID).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Skill{}, fmt.Errorf("no skill found with id %s", id)
+		}
+		return Skill{}, err
+	}
+	return s, nil
+}
+
+func FindSkillByName(userID uint, name string) (Skill, error) {
+	query := "SELECT " + skillColumns + " FROM skills WHERE LOWER(name) = LOWER($1) AND userId = $2"
+
+	s, err := scanSkill(db.DB.QueryRow(query, name, userID).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Skill{}, fmt.Errorf("no skill found with name %s", name)
+		}
+		return Skill{}, err
+	}
+	return s, nil
+}
+
+func UpdateSkill(userID uint, s Skill) (Skill, error) {
+	query := `
+		UPDATE skills
+		SET name = $1, category = $2, description = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND userId = $5
+		RETURNING ` + skillColumns
+
+	updated, err := scanSkill(db.DB.QueryRow(query, s.Name, s.Category, s.Description, s.ID, userID).Scan)
+	if err != nil {
+		return Skill{}, fmt.Errorf("failed to update skill: %v", err)
+	}
+	return updated, nil
+}
+
+// DeleteSkill removes the skill from the catalog and from every employee rated in it.
+func DeleteSkill(userID uint, id int) error {
+	if _, err := db.DB.Exec("DELETE FROM skills WHERE id = $1 AND userId = $2", id, userID); err != nil {
+		return fmt.Errorf("failed to delete skill: %v", err)
+	}
+	return nil
+}
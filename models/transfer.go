@@ -0,0 +1,26 @@
+package models
+
+type EmployeeTransfer struct {
+	ID               int     `json:"id"`
+	IdentityNumber   string  `json:"identityNumber"`
+	FromDepartmentID string  `json:"fromDepartmentId"`
+	ToDepartmentID   string  `json:"toDepartmentId"`
+	EffectiveDate    string  `json:"effectiveDate"`
+	Reason           string  `json:"reason"`
+	Approver         *string `json:"approver"`
+	CreatedAt        string  `json:"createdAt"`
+}
+
+// TimelineEvent is a single entry in an employee's timeline. Only the fields
+// relevant to the event type are populated.
+type TimelineEvent struct {
+	Type             string           `json:"type"` // "status" or "transfer"
+	EffectiveDate    string           `json:"effectiveDate"`
+	Reason           string           `json:"reason"`
+	FromStatus       EmploymentStatus `json:"fromStatus,omitempty"`
+	ToStatus         EmploymentStatus `json:"toStatus,omitempty"`
+	FromDepartmentID string           `json:"fromDepartmentId,omitempty"`
+	ToDepartmentID   string           `json:"toDepartmentId,omitempty"`
+	Approver         string           `json:"approver,omitempty"`
+	CreatedAt        string           `json:"createdAt"`
+}
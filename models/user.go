@@ -14,6 +14,7 @@ type User struct {
 	UserImageUri    sql.NullString
 	CompanyName     sql.NullString
 	CompanyImageUri sql.NullString
+	TenantID        sql.NullInt64 // The owner's account for accounts added to a tenant
 	CreatedAt       string
 	UpdatedAt       string
 }
@@ -30,13 +31,21 @@ func CreateUser(email string, password string) (User, error) {
 	return user, nil
 }
 
+// Tenant returns the tenant the account works in.
+func (u User) Tenant() uint {
+	if u.TenantID.Valid {
+		return uint(u.TenantID.Int64)
+	}
+	return u.ID
+}
+
 func FindUserByEmail(email string) (User, error) {
-	query := "SELECT id, email, password FROM users WHERE email = $1"
+	query := "SELECT id, email, password, tenant_id FROM users WHERE email = $1"
 	var user User
 
 	row := db.DB.QueryRow(query, email)
 
-	err := row.Scan(&user.ID, &user.Email, &user.Password)
+	err := row.Scan(&user.ID, &user.Email, &user.Password, &user.TenantID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			fmt.Print("User not found")
@@ -66,3 +75,115 @@ func FindUserById(id uint) (User, error) {
 
 	return user, nil
 }
+
+type Role string
+
+const (
+	RoleAdmin   Role = "admin"
+	RoleHR      Role = "hr"
+	RoleManager Role = "manager"
+)
+
+func FindUserRole(id uint) (Role, error) {
+	var role Role
+	err := db.DB.QueryRow("SELECT role FROM users WHERE id = $1", id).Scan(&role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("no user found with id: %d", id)
+		}
+		return "", err
+	}
+	return role, nil
+}
+
+func FindIdentityScheme(userID uint) (string, error) {
+	var scheme string
+	err := db.DB.QueryRow("SELECT identity_scheme FROM users WHERE id = $1", userID).Scan(&scheme)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("no user found with id: %d", userID)
+		}
+		return "", err
+	}
+	return scheme, nil
+}
+
+func UpdateIdentityScheme(userID uint, scheme string) error {
+	_, err := db.DB.Exec("UPDATE users SET identity_scheme = $1 WHERE id = $2", scheme, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update identity scheme: %v", err)
+	}
+	return nil
+}
+
+// Member is an account that works in a tenant, including its owner.
+type Member struct {
+	ID        uint   `json:"id"`
+	Email     string `json:"email"`
+	Role      Role   `json:"role"`
+	Owner     bool   `json:"owner"`
+	CreatedAt string `json:"createdAt"`
+}
+
+const memberColumns = "id, email, role, tenant_id IS NULL, created_at::TEXT"
+
+// CreateMember adds an account with role to the tenant.
+func CreateMember(tenantID uint, email string, password string, role Role) (Member, error) {
+	query := "INSERT INTO users (email, password, role, tenant_id) VALUES ($1, $2, $3, $4) RETURNING " + memberColumns
+
+	var m Member
+	err := db.DB.QueryRow(query, email, password, role, tenantID).Scan(&m.ID, &m.Email, &m.Role, &m.Owner, &m.CreatedAt)
+	if err != nil {
+		return Member{}, fmt.Errorf("failed to create member: %v", err)
+	}
+	return m, nil
+}
+
+// GetMembers lists the tenant's accounts, its owner first.
+func GetMembers(tenantID uint) ([]Member, error) {
+	query := "SELECT " + memberColumns + " FROM users WHERE id = $1 OR tenant_id = $1 ORDER BY tenant_id NULLS FIRST, id"
+	rows, err := db.DB.Query(query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch members: %v", err)
+	}
+	defer rows.Close()
+
+	members := []Member{}
+	for rows.Next() {
+		var m Member
+		if err := rows.Scan(&m.ID, &m.Email, &m.Role, &m.Owner, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan member: %v", err)
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+// UpdateMemberRole changes the role of an account added to the tenant. The
+// owner always stays an admin, so it is not found here.
+func UpdateMemberRole(tenantID uint, id uint, role Role) (Member, error) {
+	query := "UPDATE users SET role = $1 WHERE id = $2 AND tenant_id = $3 RETURNING " + memberColumns
+
+	var m Member
+	err := db.DB.QueryRow(query, role, id, tenantID).Scan(&m.ID, &m.Email, &m.Role, &m.Owner, &m.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Member{}, fmt.Errorf("no member found with id: %d", id)
+		}
+		return Member{}, err
+	}
+	return m, nil
+}
+
+// DeleteMember removes an account added to the tenant; the owner cannot be removed.
+func DeleteMember(tenantID uint, id uint) (bool, error) {
+	result, err := db.DB.Exec("DELETE FROM users WHERE id = $1 AND tenant_id = $2", id, tenantID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete member: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
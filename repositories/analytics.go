@@ -0,0 +1,245 @@
+package repositories
+
+import (
+	"context"
+	"go-go-manager/models"
+
+	"github.com/lib/pq"
+)
+
+// GetDepartmentHeadcounts counts the filtered employees per department and
+// gender.
+func (r *EmployeeRepository) GetDepartmentHeadcounts(filters map[string]string) ([]models.DepartmentHeadcount, error) {
+	f := newEmployeeFilter(filters)
+
+	query := `
+		SELECT e.department_id, COALESCE(d.name, ''), e.gender, COUNT(*)
+		FROM ` + f.from + `
+		LEFT JOIN department d ON d.id = e.department_id
+		WHERE ` + f.where + `
+		GROUP BY e.department_id, d.name, e.gender
+		ORDER BY d.name, e.department_id
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, f.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	departments := []models.DepartmentHeadcount{}
+	for rows.Next() {
+		var departmentID, name string
+		var gender models.Gender
+		var count int
+		if err := rows.Scan(&departmentID, &name, &gender, &count); err != nil {
+			return nil, err
+		}
+
+		// Rows of one department are adjacent
+		if len(departments) == 0 || departments[len(departments)-1].DepartmentID != departmentID {
+			departments = append(departments, models.DepartmentHeadcount{DepartmentID: departmentID, Name: name})
+		}
+		department := &departments[len(departments)-1]
+		department.Headcount += count
+		switch gender {
+		case models.Male:
+			department.Male += count
+		case models.Female:
+			department.Female += count
+		}
+	}
+
+	return departments, rows.Err()
+}
+
+// GetTenureCounts counts the filtered employees per tenure bucket on asOf.
+// Tenure runs from the first status transition of the employment, like
+// GetHireDate.
+func (r *EmployeeRepository) GetTenureCounts(filters map[string]string, asOf string) (map[string]int, error) {
+	f := newEmployeeFilter(filters)
+
+	query := `
+		WITH hired AS (
+			SELECT AGE(` + f.arg(asOf) + `::DATE, COALESCE(
+				(SELECT MIN(t.effective_date) FROM employee_status_transitions t WHERE t.employment_id = e.employment_id),
+				e.created_at::DATE
+			)) AS tenure
+			FROM ` + f.from + `
+			WHERE ` + f.where + `
+		)
+		SELECT
+			CASE
+				WHEN tenure < INTERVAL '1 year' THEN '<1y'
+				WHEN tenure < INTERVAL '2 years' THEN '1-2y'
+				WHEN tenure < INTERVAL '5 years' THEN '2-5y'
+				WHEN tenure < INTERVAL '10 years' THEN '5-10y'
+				ELSE '10y+'
+			END,
+			COUNT(*)
+		FROM hired
+		GROUP BY 1
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, f.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var bucket string
+		var count int
+		if err := rows.Scan(&bucket, &count); err != nil {
+			return nil, err
+		}
+		counts[bucket] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// GetMonthlyFlows counts hires and leavers among the filtered employees per
+// month from from to to, keyed by YYYY-MM. A hire is a transition into
+// employment from no status or from terminated, so rehires count too. Pass
+// withDeleted to count the leavers whose records were deleted.
+func (r *EmployeeRepository) GetMonthlyFlows(filters map[string]string, from string, to string) (map[string]models.MonthlyFlow, error) {
+	f := newEmployeeFilter(filters)
+
+	query := `
+		SELECT TO_CHAR(t.effective_date, 'YYYY-MM'),
+			COUNT(*) FILTER (WHERE (t.from_status IS NULL OR t.from_status = 'terminated') AND t.to_status <> 'terminated'),
+			COUNT(*) FILTER (WHERE t.to_status = 'terminated')
+		FROM employee_status_transitions t
+		JOIN ` + f.from + ` ON e.employment_id = t.employment_id
+		WHERE ` + f.where + ` AND t.effective_date BETWEEN ` + f.arg(from) + ` AND ` + f.arg(to) + `
+		GROUP BY 1
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, f.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	flows := make(map[string]models.MonthlyFlow)
+	for rows.Next() {
+		var flow models.MonthlyFlow
+		if err := rows.Scan(&flow.Month, &flow.Hires, &flow.Leavers); err != nil {
+			return nil, err
+		}
+		flows[flow.Month] = flow
+	}
+
+	return flows, rows.Err()
+}
+
+// GetTurnoverCounts works out the monthly attrition figures of the filtered
+// employees per department and gender, for the months from the one holding
+// from up to the one holding to. A month is cut short at to. Employees count
+// as employed while active or on leave. Pass withDeleted so that employees
+// deleted since, whose transitions are kept, count in the months they were
+// employed and left in.
+func (r *EmployeeRepository) GetTurnoverCounts(filters map[string]string, from string, to string) ([]models.TurnoverCounts, error) {
+	f := newEmployeeFilter(filters)
+
+	fromArg := f.arg(from)
+	toArg := f.arg(to)
+	employed := f.arg(pq.Array(statusFilterValues("current")))
+	employedOn := func(date string) string {
+		return statusOnExpr(date) + " = ANY(" + employed + ")"
+	}
+	// A hire is a move into employment from no status or from terminated
+	hire := "(t.from_status IS NULL OR t.from_status = 'terminated') AND t.to_status <> 'terminated'"
+
+	query := `
+		WITH months AS (
+			SELECT m::DATE AS month_start, LEAST((m + INTERVAL '1 month')::DATE - 1, ` + toArg + `::DATE) AS month_end
+			FROM GENERATE_SERIES(DATE_TRUNC('month', ` + fromArg + `::DATE), ` + toArg + `::DATE, INTERVAL '1 month') AS m
+		), staff AS (
+			SELECT e.employment_id, e.department_id, e.gender, COALESCE(
+				(SELECT MIN(t.effective_date) FROM employee_status_transitions t WHERE t.employment_id = e.employment_id),
+				e.created_at::DATE
+			) AS hired_on
+			FROM ` + f.from + `
+			WHERE ` + f.where + `
+		), headcount AS (
+			SELECT m.month_start, e.department_id, e.gender,
+				COUNT(*) FILTER (WHERE ` + employedOn("m.month_start - 1") + `) AS start_headcount,
+				COUNT(*) FILTER (WHERE ` + employedOn("m.month_end") + `) AS end_headcount,
+				COUNT(*) FILTER (WHERE ` + employedOn("m.month_start - 1") + ` AND ` + employedOn("m.month_end") + `) AS stayed,
+				COALESCE(SUM(m.month_end - e.hired_on) FILTER (WHERE ` + employedOn("m.month_end") + `), 0) AS tenure_days
+			FROM months m
+			CROSS JOIN staff e
+			GROUP BY m.month_start, e.department_id, e.gender
+		), leavers AS (
+			SELECT DATE_TRUNC('month', t.effective_date)::DATE AS month_start, e.department_id, e.gender,
+				COUNT(*) AS leavers,
+				COUNT(*) FILTER (WHERE t.termination_type = 'voluntary') AS voluntary,
+				COUNT(*) FILTER (WHERE t.termination_type = 'involuntary') AS involuntary,
+				COUNT(*) FILTER (WHERE t.termination_type IS NULL) AS unclassified,
+				COUNT(*) FILTER (WHERE t.regretted) AS regretted
+			FROM employee_status_transitions t
+			JOIN staff e ON e.employment_id = t.employment_id
+			WHERE t.to_status = 'terminated'
+				AND t.effective_date BETWEEN DATE_TRUNC('month', ` + fromArg + `::DATE) AND ` + toArg + `::DATE
+			GROUP BY 1, e.department_id, e.gender
+		), hires AS (
+			SELECT DATE_TRUNC('month', t.effective_date)::DATE AS month_start, e.department_id, e.gender,
+				COUNT(*) AS hires,
+				COUNT(*) FILTER (WHERE t.effective_date + 90 <= CURRENT_DATE) AS cohort,
+				COUNT(*) FILTER (WHERE t.effective_date + 90 <= CURRENT_DATE AND NOT EXISTS (
+					SELECT 1
+					FROM employee_status_transitions x
+					WHERE x.employment_id = t.employment_id AND x.to_status = 'terminated'
+						AND x.effective_date >= t.effective_date AND x.effective_date < t.effective_date + 90
+				)) AS retained
+			FROM employee_status_transitions t
+			JOIN staff e ON e.employment_id = t.employment_id
+			WHERE ` + hire + `
+				AND t.effective_date BETWEEN DATE_TRUNC('month', ` + fromArg + `::DATE) AND ` + toArg + `::DATE
+			GROUP BY 1, e.department_id, e.gender
+		)
+		SELECT TO_CHAR(h.month_start, 'YYYY-MM'), h.department_id, COALESCE(d.name, ''), h.gender,
+			h.start_headcount, h.end_headcount, h.stayed, h.tenure_days,
+			COALESCE(l.leavers, 0), COALESCE(l.voluntary, 0), COALESCE(l.involuntary, 0), COALESCE(l.unclassified, 0),
+			COALESCE(l.regretted, 0), COALESCE(n.hires, 0), COALESCE(n.cohort, 0), COALESCE(n.retained, 0)
+		FROM headcount h
+		LEFT JOIN leavers l ON l.month_start = h.month_start AND l.department_id = h.department_id AND l.gender = h.gender
+		LEFT JOIN hires n ON n.month_start = h.month_start AND n.department_id = h.department_id AND n.gender = h.gender
+		LEFT JOIN department d ON d.id = h.department_id
+		ORDER BY h.month_start, d.name, h.department_id
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, f.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []models.TurnoverCounts{}
+	for rows.Next() {
+		var c models.TurnoverCounts
+		err := rows.Scan(
+			&c.Month,
+			&c.DepartmentID,
+			&c.DepartmentName,
+			&c.Gender,
+			&c.StartHeadcount,
+			&c.EndHeadcount,
+			&c.Stayed,
+			&c.TenureDays,
+			&c.Leavers,
+			&c.VoluntaryLeavers,
+			&c.InvoluntaryLeavers,
+			&c.UnclassifiedLeavers,
+			&c.RegrettedLeavers,
+			&c.Hires,
+			&c.NewHireCohort,
+			&c.NewHireRetained,
+		)
+		if err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+
+	return counts, rows.Err()
+}
@@ -0,0 +1,212 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go-go-manager/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ErrTimesheetOverlap is returned when an approval overlaps a period that is
+// already approved for the employee.
+var ErrTimesheetOverlap = errors.New("timesheet period overlaps an approved period")
+
+const attendanceEventColumns = "id, identity_number, type, occurred_at, note, location, created_at::TEXT"
+
+func scanAttendanceEvent(scan func(dest ...interface{}) error) (models.AttendanceEvent, error) {
+	var e models.AttendanceEvent
+	err := scan(
+		&e.ID,
+		&e.IdentityNumber,
+		&e.Type,
+		&e.OccurredAt,
+		&e.Note,
+		&e.Location,
+		&e.CreatedAt,
+	)
+	return e, err
+}
+
+// queryAttendanceEvent returns the single punch query finds, or nil when there is none.
+func (r *EmployeeRepository) queryAttendanceEvent(query string, args ...interface{}) (*models.AttendanceEvent, error) {
+	e, err := scanAttendanceEvent(r.conn().QueryRowContext(context.Background(), query, args...).Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// GetAdjacentAttendanceEvents returns the employee's punches right before and
+// right after at; either is nil when there is none.
+func (r *EmployeeRepository) GetAdjacentAttendanceEvents(identityNumber string, at time.Time) (*models.AttendanceEvent, *models.AttendanceEvent, error) {
+	previous, err := r.queryAttendanceEvent(`
+		SELECT `+attendanceEventColumns+`
+		FROM attendance_events
+		WHERE identity_number = $1 AND occurred_at <= $2
+		ORDER BY occurred_at DESC, id DESC
+		LIMIT 1
+	`, identityNumber, at)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	next, err := r.queryAttendanceEvent(`
+		SELECT `+attendanceEventColumns+`
+		FROM attendance_events
+		WHERE identity_number = $1 AND occurred_at > $2
+		ORDER BY occurred_at, id
+		LIMIT 1
+	`, identityNumber, at)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return previous, next, nil
+}
+
+// GetAttendanceEvent returns the employee's punch, or nil when there is none.
+func (r *EmployeeRepository) GetAttendanceEvent(identityNumber string, id int) (*models.AttendanceEvent, error) {
+	return r.queryAttendanceEvent(`
+		SELECT `+attendanceEventColumns+`
+		FROM attendance_events
+		WHERE id = $1 AND identity_number = $2
+	`, id, identityNumber)
+}
+
+func (r *EmployeeRepository) DeleteAttendanceEvent(id int) error {
+	_, err := r.conn().ExecContext(context.Background(), "DELETE FROM attendance_events WHERE id = $1", id)
+	return err
+}
+
+func (r *EmployeeRepository) AddAttendanceEvent(event models.AttendanceEvent) (models.AttendanceEvent, error) {
+	err := r.conn().QueryRowContext(context.Background(), `
+		INSERT INTO attendance_events (identity_number, type, occurred_at, note, location)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at::TEXT
+	`,
+		event.IdentityNumber,
+		event.Type,
+		event.OccurredAt,
+		event.Note,
+		event.Location,
+	).Scan(&event.ID, &event.CreatedAt)
+	return event, err
+}
+
+// GetAttendanceEvents returns the punches of the tenant's employees between
+// start and end, grouped by identity number. An empty identityNumber returns
+// every employee of the tenant.
+func (r *EmployeeRepository) GetAttendanceEvents(userID uint, identityNumber string, start time.Time, end time.Time) (map[string][]models.AttendanceEvent, error) {
+	query := `
+		SELECT a.id, a.identity_number, a.type, a.occurred_at, a.note, a.location, a.created_at::TEXT
+		FROM attendance_events a
+		JOIN employees e ON e.identity_number = a.identity_number
+		JOIN department d ON d.id = e.department_id
+		WHERE d.userid = $1
+			AND ($2 = '' OR a.identity_number = $2)
+			AND a.occurred_at >= $3 AND a.occurred_at < $4
+		ORDER BY a.identity_number, a.occurred_at, a.id
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, userID, identityNumber, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make(map[string][]models.AttendanceEvent)
+	for rows.Next() {
+		e, err := scanAttendanceEvent(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		events[e.IdentityNumber] = append(events[e.IdentityNumber], e)
+	}
+
+	return events, rows.Err()
+}
+
+// IsTimesheetApproved reports whether date falls in an approved period of the employee.
+func (r *EmployeeRepository) IsTimesheetApproved(identityNumber string, date string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM timesheet_approvals
+			WHERE identity_number = $1 AND $2::DATE BETWEEN period_start AND period_end
+		)
+	`
+	var approved bool
+	err := r.conn().QueryRowContext(context.Background(), query, identityNumber, date).Scan(&approved)
+	return approved, err
+}
+
+func (r *EmployeeRepository) ApproveTimesheet(approval models.TimesheetApproval) (models.TimesheetApproval, error) {
+	err := r.conn().QueryRowContext(context.Background(), `
+		INSERT INTO timesheet_approvals (identity_number, period_start, period_end, worked_hours, overtime_hours, approved_by, note)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at::TEXT
+	`,
+		approval.IdentityNumber,
+		approval.PeriodStart,
+		approval.PeriodEnd,
+		approval.WorkedHours,
+		approval.OvertimeHours,
+		approval.ApprovedBy,
+		approval.Note,
+	).Scan(&approval.ID, &approval.CreatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23P01" {
+			return approval, ErrTimesheetOverlap
+		}
+	}
+	return approval, err
+}
+
+// GetTimesheetApprovals returns the approvals of the tenant's employees that
+// overlap the period from to to, grouped by identity number. An empty
+// identityNumber returns every employee of the tenant.
+func (r *EmployeeRepository) GetTimesheetApprovals(userID uint, identityNumber string, from string, to string) (map[string][]models.TimesheetApproval, error) {
+	query := `
+		SELECT t.id, t.identity_number, t.period_start::TEXT, t.period_end::TEXT, t.worked_hours, t.overtime_hours,
+			t.approved_by, t.note, t.created_at::TEXT
+		FROM timesheet_approvals t
+		JOIN employees e ON e.identity_number = t.identity_number
+		JOIN department d ON d.id = e.department_id
+		WHERE d.userid = $1
+			AND ($2 = '' OR t.identity_number = $2)
+			AND t.period_start <= $4 AND t.period_end >= $3
+		ORDER BY t.identity_number, t.period_start
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, userID, identityNumber, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	approvals := make(map[string][]models.TimesheetApproval)
+	for rows.Next() {
+		var a models.TimesheetApproval
+		err := rows.Scan(
+			&a.ID,
+			&a.IdentityNumber,
+			&a.PeriodStart,
+			&a.PeriodEnd,
+			&a.WorkedHours,
+			&a.OvertimeHours,
+			&a.ApprovedBy,
+			&a.Note,
+			&a.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		approvals[a.IdentityNumber] = append(approvals[a.IdentityNumber], a)
+	}
+
+	return approvals, rows.Err()
+}
@@ -0,0 +1,294 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-go-manager/models"
+	"time"
+)
+
+// StartChecklists creates a checklist from every active template of kind that
+// applies to the employee's department, with due dates counted from anchor.
+func (r *EmployeeRepository) StartChecklists(userID uint, kind models.ChecklistKind, employee models.Employee, anchor time.Time) ([]models.Checklist, error) {
+	ctx := context.Background()
+	checklists := []models.Checklist{}
+
+	err := r.inTx(ctx, func(txRepo *EmployeeRepository) error {
+		rows, err := txRepo.conn().QueryContext(ctx, `
+			SELECT id, name, tasks
+			FROM checklist_templates
+			WHERE userId = $1 AND kind = $2 AND active AND (department_id IS NULL OR department_id::TEXT = $3)
+			ORDER BY name
+		`, userID, kind, employee.DepartmentID)
+		if err != nil {
+			return err
+		}
+
+		type template struct {
+			id    int
+			name  string
+			tasks []models.ChecklistTaskTemplate
+		}
+		var templates []template
+		for rows.Next() {
+			var t template
+			var tasks []byte
+			if err := rows.Scan(&t.id, &t.name, &tasks); err != nil {
+				rows.Close()
+				return err
+			}
+			if err := json.Unmarshal(tasks, &t.tasks); err != nil {
+				rows.Close()
+				return err
+			}
+			templates = append(templates, t)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, t := range templates {
+			var id int
+			err := txRepo.conn().QueryRowContext(ctx, `
+				INSERT INTO checklists (userId, template_id, name, kind, identity_number, employee_name, department_id, anchor_date)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+				RETURNING id
+			`, userID, t.id, t.name, kind, employee.IdentityNumber, employee.Name, employee.DepartmentID, anchor.Format(time.DateOnly)).Scan(&id)
+			if err != nil {
+				return err
+			}
+
+			for i, task := range t.tasks {
+				_, err := txRepo.conn().ExecContext(ctx, `
+					INSERT INTO checklist_tasks (checklist_id, position, title, description, assignee, due_date)
+					VALUES ($1, $2, $3, $4, $5, $6)
+				`, id, i+1, task.Title, task.Description, task.Assignee, anchor.AddDate(0, 0, task.DueOffsetDays).Format(time.DateOnly))
+				if err != nil {
+					return err
+				}
+			}
+
+			checklist, err := txRepo.GetChecklist(userID, id)
+			if err != nil {
+				return err
+			}
+			checklists = append(checklists, checklist)
+		}
+		return nil
+	})
+	return checklists, err
+}
+
+// HasOpenChecklist reports whether the employee has an unfinished checklist of kind.
+func (r *EmployeeRepository) HasOpenChecklist(identityNumber string, kind models.ChecklistKind) (bool, error) {
+	var open bool
+	err := r.conn().QueryRowContext(context.Background(), `
+		SELECT EXISTS (
+			SELECT 1 FROM checklists WHERE identity_number = $1 AND kind = $2 AND completed_at IS NULL
+		)
+	`, identityNumber, kind).Scan(&open)
+	return open, err
+}
+
+const checklistColumns = `
+	c.id, c.template_id, c.name, c.kind, c.identity_number, c.employee_name, c.department_id::TEXT,
+	c.anchor_date::TEXT, (SELECT COUNT(*) FROM checklist_tasks t WHERE t.checklist_id = c.id),
+	(SELECT COUNT(*) FROM checklist_tasks t WHERE t.checklist_id = c.id AND t.completed_at IS NOT NULL),
+	c.created_at::TEXT, c.completed_at::TEXT
+`
+
+func checklistFields(c *models.Checklist) []interface{} {
+	return []interface{}{
+		&c.ID,
+		&c.TemplateID,
+		&c.Name,
+		&c.Kind,
+		&c.IdentityNumber,
+		&c.EmployeeName,
+		&c.DepartmentID,
+		&c.AnchorDate,
+		&c.TotalTasks,
+		&c.CompletedTasks,
+		&c.CreatedAt,
+		&c.CompletedAt,
+	}
+}
+
+const checklistTaskColumns = `
+	t.id, t.checklist_id, t.position, t.title, t.description, t.assignee, t.due_date::TEXT,
+	t.completed_at::TEXT, t.completed_by
+`
+
+func checklistTaskFields(t *models.ChecklistTask) []interface{} {
+	return []interface{}{
+		&t.ID,
+		&t.ChecklistID,
+		&t.Position,
+		&t.Title,
+		&t.Description,
+		&t.Assignee,
+		&t.DueDate,
+		&t.CompletedAt,
+		&t.CompletedBy,
+	}
+}
+
+// GetChecklist returns the tenant's checklist with its tasks in order.
+func (r *EmployeeRepository) GetChecklist(userID uint, id int) (models.Checklist, error) {
+	var c models.Checklist
+	query := "SELECT " + checklistColumns + " FROM checklists c WHERE c.id = $1 AND c.userId = $2"
+	if err := r.conn().QueryRowContext(context.Background(), query, id, userID).Scan(checklistFields(&c)...); err != nil {
+		return c, err
+	}
+
+	rows, err := r.conn().QueryContext(context.Background(), `
+		SELECT `+checklistTaskColumns+`
+		FROM checklist_tasks t
+		WHERE t.checklist_id = $1
+		ORDER BY t.position, t.id
+	`, id)
+	if err != nil {
+		return c, err
+	}
+	defer rows.Close()
+
+	c.Tasks = []models.ChecklistTask{}
+	for rows.Next() {
+		var t models.ChecklistTask
+		if err := rows.Scan(checklistTaskFields(&t)...); err != nil {
+			return c, err
+		}
+		c.Tasks = append(c.Tasks, t)
+	}
+
+	return c, rows.Err()
+}
+
+// GetChecklists lists checklists without their tasks, newest first. Supported
+// filters are userId, identityNumber, kind and status (open or completed).
+func (r *EmployeeRepository) GetChecklists(filters map[string]string) ([]models.Checklist, error) {
+	where := "1=1"
+	args := []interface{}{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if userID, ok := filters["userId"]; ok {
+		where += " AND c.userId = " + arg(userID)
+	}
+	if identityNumber, ok := filters["identityNumber"]; ok {
+		where += " AND c.identity_number = " + arg(identityNumber)
+	}
+	if kind, ok := filters["kind"]; ok {
+		where += " AND c.kind = " + arg(kind)
+	}
+	switch filters["status"] {
+	case "open":
+		where += " AND c.completed_at IS NULL"
+	case "completed":
+		where += " AND c.completed_at IS NOT NULL"
+	}
+
+	query := "SELECT " + checklistColumns + " FROM checklists c WHERE " + where + " ORDER BY c.created_at DESC, c.id DESC"
+	rows, err := r.conn().QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checklists := []models.Checklist{}
+	for rows.Next() {
+		var c models.Checklist
+		if err := rows.Scan(checklistFields(&c)...); err != nil {
+			return nil, err
+		}
+		checklists = append(checklists, c)
+	}
+
+	return checklists, rows.Err()
+}
+
+// GetChecklistTask returns the task when it belongs to one of the tenant's checklists.
+func (r *EmployeeRepository) GetChecklistTask(userID uint, id int) (models.ChecklistTask, error) {
+	var t models.ChecklistTask
+	query := `
+		SELECT ` + checklistTaskColumns + `
+		FROM checklist_tasks t
+		JOIN checklists c ON c.id = t.checklist_id
+		WHERE t.id = $1 AND c.userId = $2
+	`
+	err := r.conn().QueryRowContext(context.Background(), query, id, userID).Scan(checklistTaskFields(&t)...)
+	return t, err
+}
+
+// UpdateChecklistTask saves the task and marks its checklist completed once
+// every task is done, or open again when a task is reopened.
+func (r *EmployeeRepository) UpdateChecklistTask(task models.ChecklistTask) (models.ChecklistTask, error) {
+	ctx := context.Background()
+	var updated models.ChecklistTask
+
+	err := r.inTx(ctx, func(txRepo *EmployeeRepository) error {
+		err := txRepo.conn().QueryRowContext(ctx, `
+			UPDATE checklist_tasks AS t
+			SET assignee = $1, due_date = $2, completed_at = $3, completed_by = $4
+			WHERE t.id = $5
+			RETURNING `+checklistTaskColumns,
+			task.Assignee,
+			task.DueDate,
+			task.CompletedAt,
+			task.CompletedBy,
+			task.ID,
+		).Scan(checklistTaskFields(&updated)...)
+		if err != nil {
+			return err
+		}
+
+		_, err = txRepo.conn().ExecContext(ctx, `
+			UPDATE checklists c
+			SET completed_at = CASE
+				WHEN NOT EXISTS (SELECT 1 FROM checklist_tasks t WHERE t.checklist_id = c.id AND t.completed_at IS NULL)
+				THEN COALESCE(c.completed_at, CURRENT_TIMESTAMP)
+			END
+			WHERE c.id = $1
+		`, updated.ChecklistID)
+		return err
+	})
+	return updated, err
+}
+
+// GetOverdueTasks lists the tenant's open tasks due before today, most overdue
+// first. An empty assignee returns every assignee's tasks.
+func (r *EmployeeRepository) GetOverdueTasks(userID uint, assignee string, today string) ([]models.OverdueTask, error) {
+	query := `
+		SELECT ` + checklistTaskColumns + `, c.name, c.kind, c.identity_number, c.employee_name,
+			c.department_id::TEXT, $3::DATE - t.due_date
+		FROM checklist_tasks t
+		JOIN checklists c ON c.id = t.checklist_id
+		WHERE c.userId = $1
+			AND ($2 = '' OR t.assignee = $2)
+			AND t.completed_at IS NULL
+			AND t.due_date < $3::DATE
+		ORDER BY t.due_date, c.id, t.position
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, userID, assignee, today)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []models.OverdueTask{}
+	for rows.Next() {
+		var t models.OverdueTask
+		fields := append(checklistTaskFields(&t.ChecklistTask), &t.ChecklistName, &t.Kind, &t.IdentityNumber,
+			&t.EmployeeName, &t.DepartmentID, &t.DaysOverdue)
+		if err := rows.Scan(fields...); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+
+	return tasks, rows.Err()
+}
@@ -0,0 +1,342 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-go-manager/models"
+	"math"
+
+	"github.com/lib/pq"
+)
+
+// ErrCompensationExists is returned when the employee already has a record
+// taking effect on the same date.
+var ErrCompensationExists = errors.New("a compensation record already takes effect on this date")
+
+const compensationColumns = `
+	id, identity_number, effective_date::TEXT, base_salary, currency, pay_frequency, allowances, reason,
+	recorded_by, created_at::TEXT
+`
+
+func scanCompensation(scan func(dest ...interface{}) error) (models.Compensation, error) {
+	var c models.Compensation
+	var allowances []byte
+	err := scan(
+		&c.ID,
+		&c.IdentityNumber,
+		&c.EffectiveDate,
+		&c.BaseSalary,
+		&c.Currency,
+		&c.PayFrequency,
+		&allowances,
+		&c.Reason,
+		&c.RecordedBy,
+		&c.CreatedAt,
+	)
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(allowances, &c.Allowances)
+	return c, err
+}
+
+func compensationError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrCompensationExists
+	}
+	return err
+}
+
+// asActor records who makes the changes of the current transaction, for the
+// compensation history trigger.
+func (r *EmployeeRepository) asActor(ctx context.Context, actor string) error {
+	_, err := r.conn().ExecContext(ctx, "SELECT set_config('app.actor', $1, true)", actor)
+	return err
+}
+
+func (r *EmployeeRepository) AddCompensation(compensation models.Compensation, actor string) (models.Compensation, error) {
+	allowances, err := json.Marshal(compensation.Allowances)
+	if err != nil {
+		return compensation, err
+	}
+
+	var created models.Compensation
+	ctx := context.Background()
+	err = r.inTx(ctx, func(txRepo *EmployeeRepository) error {
+		if err := txRepo.asActor(ctx, actor); err != nil {
+			return err
+		}
+
+		query := `
+			INSERT INTO compensation_records (identity_number, effective_date, base_salary, currency, pay_frequency,
+				allowances, reason, recorded_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING ` + compensationColumns
+		created, err = scanCompensation(txRepo.conn().QueryRowContext(ctx, query,
+			compensation.IdentityNumber,
+			compensation.EffectiveDate,
+			compensation.BaseSalary,
+			compensation.Currency,
+			compensation.PayFrequency,
+			allowances,
+			compensation.Reason,
+			actor,
+		).Scan)
+		return err
+	})
+	return created, compensationError(err)
+}
+
+// GetCompensations returns the employee's records, the latest effective first.
+func (r *EmployeeRepository) GetCompensations(identityNumber string) ([]models.Compensation, error) {
+	query := "SELECT " + compensationColumns + " FROM compensation_records WHERE identity_number = $1 ORDER BY effective_date DESC"
+	rows, err := r.conn().QueryContext(context.Background(), query, identityNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []models.Compensation{}
+	for rows.Next() {
+		c, err := scanCompensation(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, c)
+	}
+
+	return records, rows.Err()
+}
+
+func (r *EmployeeRepository) GetCompensation(identityNumber string, id int) (*models.Compensation, error) {
+	query := "SELECT " + compensationColumns + " FROM compensation_records WHERE identity_number = $1 AND id = $2"
+	c, err := scanCompensation(r.conn().QueryRowContext(context.Background(), query, identityNumber, id).Scan)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// UpdateCompensation corrects a record in place; the previous values stay in
+// the compensation history.
+func (r *EmployeeRepository) UpdateCompensation(compensation models.Compensation, actor string) (models.Compensation, error) {
+	allowances, err := json.Marshal(compensation.Allowances)
+	if err != nil {
+		return compensation, err
+	}
+
+	var updated models.Compensation
+	ctx := context.Background()
+	err = r.inTx(ctx, func(txRepo *EmployeeRepository) error {
+		if err := txRepo.asActor(ctx, actor); err != nil {
+			return err
+		}
+
+		query := `
+			UPDATE compensation_records
+			SET effective_date = $1, base_salary = $2, currency = $3, pay_frequency = $4, allowances = $5,
+				reason = $6, recorded_by = $7, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $8
+			RETURNING ` + compensationColumns
+		updated, err = scanCompensation(txRepo.conn().QueryRowContext(ctx, query,
+			compensation.EffectiveDate,
+			compensation.BaseSalary,
+			compensation.Currency,
+			compensation.PayFrequency,
+			allowances,
+			compensation.Reason,
+			actor,
+			compensation.ID,
+		).Scan)
+		return err
+	})
+	return updated, compensationError(err)
+}
+
+func (r *EmployeeRepository) DeleteCompensation(id int, actor string) error {
+	ctx := context.Background()
+	return r.inTx(ctx, func(txRepo *EmployeeRepository) error {
+		if err := txRepo.asActor(ctx, actor); err != nil {
+			return err
+		}
+		_, err := txRepo.conn().ExecContext(ctx, "DELETE FROM compensation_records WHERE id = $1", id)
+		return err
+	})
+}
+
+// GetCompensationHistory returns every change to the employee's records, oldest first.
+func (r *EmployeeRepository) GetCompensationHistory(identityNumber string) ([]models.CompensationChange, error) {
+	query := `
+		SELECT id, compensation_id, operation, effective_date::TEXT, base_salary, currency, pay_frequency, allowances,
+			reason, changed_by, to_char(changed_at AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"')
+		FROM compensation_history
+		WHERE identity_number = $1
+		ORDER BY changed_at, id
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, identityNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []models.CompensationChange{}
+	for rows.Next() {
+		var c models.CompensationChange
+		var allowances []byte
+		err := rows.Scan(
+			&c.ID,
+			&c.CompensationID,
+			&c.Operation,
+			&c.EffectiveDate,
+			&c.BaseSalary,
+			&c.Currency,
+			&c.PayFrequency,
+			&allowances,
+			&c.Reason,
+			&c.ChangedBy,
+			&c.ChangedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(allowances, &c.Allowances); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+
+	return changes, rows.Err()
+}
+
+// GetPayrollCost totals the annual cost of the employees matching filters by
+// department and currency, using the record of each employee in force on asOf.
+// Costs in different currencies are never added up.
+func (r *EmployeeRepository) GetPayrollCost(filters map[string]string, asOf string) ([]models.DepartmentPayroll, error) {
+	f := newEmployeeFilter(filters)
+
+	query := `
+		SELECT e.department_id, COALESCE(d.name, ''), c.currency, c.base_salary, c.pay_frequency, c.allowances
+		FROM ` + f.from + `
+		LEFT JOIN department d ON d.id = e.department_id
+		LEFT JOIN LATERAL (
+			SELECT currency, base_salary, pay_frequency, allowances
+			FROM compensation_records
+			WHERE identity_number = e.identity_number AND effective_date <= ` + f.arg(asOf) + `
+			ORDER BY effective_date DESC
+			LIMIT 1
+		) c ON TRUE
+		WHERE ` + f.where + `
+		ORDER BY d.name, e.department_id, c.currency
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, f.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	report := []models.DepartmentPayroll{}
+	for rows.Next() {
+		var departmentID, name string
+		var currency, frequency *string
+		var baseSalary *float64
+		var allowances []byte
+		if err := rows.Scan(&departmentID, &name, &currency, &baseSalary, &frequency, &allowances); err != nil {
+			return nil, err
+		}
+
+		// Rows of one department are adjacent
+		if len(report) == 0 || report[len(report)-1].DepartmentID != departmentID {
+			report = append(report, models.DepartmentPayroll{DepartmentID: departmentID, Name: name, Costs: []models.PayrollCost{}})
+		}
+		department := &report[len(report)-1]
+		department.Headcount++
+
+		if currency == nil {
+			department.Uncompensated++
+			continue
+		}
+
+		compensation := models.Compensation{BaseSalary: *baseSalary, PayFrequency: models.PayFrequency(*frequency)}
+		if err := json.Unmarshal(allowances, &compensation.Allowances); err != nil {
+			return nil, err
+		}
+		base, extra := compensation.AnnualCost()
+
+		if n := len(department.Costs); n == 0 || department.Costs[n-1].Currency != *currency {
+			department.Costs = append(department.Costs, models.PayrollCost{Currency: *currency})
+		}
+		cost := &department.Costs[len(department.Costs)-1]
+		cost.Employees++
+		cost.AnnualBase += base
+		cost.AnnualAllowances += extra
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range report {
+		for j := range report[i].Costs {
+			cost := &report[i].Costs[j]
+			cost.AnnualBase = math.Round(cost.AnnualBase*100) / 100
+			cost.AnnualAllowances = math.Round(cost.AnnualAllowances*100) / 100
+			cost.AnnualTotal = math.Round((cost.AnnualBase+cost.AnnualAllowances)*100) / 100
+			cost.MonthlyTotal = math.Round(cost.AnnualTotal/12*100) / 100
+		}
+	}
+
+	return report, nil
+}
+
+// GetEmployeePay returns the annual pay of the filtered employees under the
+// compensation record in force on asOf. Employees without one are left out.
+func (r *EmployeeRepository) GetEmployeePay(filters map[string]string, asOf string) ([]models.EmployeePay, error) {
+	f := newEmployeeFilter(filters)
+
+	query := `
+		SELECT e.department_id, COALESCE(d.name, ''), e.gender, c.currency, c.base_salary, c.pay_frequency, c.allowances
+		FROM ` + f.from + `
+		LEFT JOIN department d ON d.id = e.department_id
+		JOIN LATERAL (
+			SELECT currency, base_salary, pay_frequency, allowances
+			FROM compensation_records
+			WHERE identity_number = e.identity_number AND effective_date <= ` + f.arg(asOf) + `
+			ORDER BY effective_date DESC
+			LIMIT 1
+		) c ON TRUE
+		WHERE ` + f.where
+	rows, err := r.conn().QueryContext(context.Background(), query, f.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pay := []models.EmployeePay{}
+	for rows.Next() {
+		var p models.EmployeePay
+		var compensation models.Compensation
+		var allowances []byte
+		err := rows.Scan(
+			&p.DepartmentID,
+			&p.DepartmentName,
+			&p.Gender,
+			&p.Currency,
+			&compensation.BaseSalary,
+			&compensation.PayFrequency,
+			&allowances,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(allowances, &compensation.Allowances); err != nil {
+			return nil, err
+		}
+
+		base, extra := compensation.AnnualCost()
+		p.AnnualPay = base + extra
+		pay = append(pay, p)
+	}
+
+	return pay, rows.Err()
+}
@@ -3,145 +3,332 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"go-go-manager/models"
-	"strconv"
+	"go-go-manager/utils"
+	"slices"
+
+	"github.com/lib/pq"
 )
 
 type EmployeeRepository struct {
 	DB *sql.DB
+	tx *sql.Tx
 }
 
 func NewEmployeeRepository(db *sql.DB) *EmployeeRepository {
 	return &EmployeeRepository{DB: db}
 }
 
+// encodeCustomFields returns the JSONB value stored for an employee's custom fields.
+func encodeCustomFields(customFields map[string]interface{}) ([]byte, error) {
+	if customFields == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(customFields)
+}
+
+func decodeCustomFields(data []byte) (map[string]interface{}, error) {
+	customFields := make(map[string]interface{})
+	if len(data) == 0 {
+		return customFields, nil
+	}
+	err := json.Unmarshal(data, &customFields)
+	return customFields, err
+}
+
 func (r *EmployeeRepository) AddEmployee(employee models.Employee) error {
+	// New hires start as active; the transition is written in the same statement
+	// so an employee never exists without a status.
 	query := `
-		INSERT INTO employees (identity_number, name, gender, department_id, employee_image_uri)
-		VALUES ($1, $2, $3, $4, $5)
+		WITH inserted AS (
+			INSERT INTO employees (identity_number, name, gender, department_id, employee_image_uri, custom_fields, phone)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING employment_id, identity_number
+		)
+		INSERT INTO employee_status_transitions (employment_id, identity_number, to_status, effective_date, reason)
+		SELECT employment_id, identity_number, 'active', CURRENT_DATE, 'hired' FROM inserted
 	`
-	_, err := r.DB.ExecContext(context.Background(), query,
+	customFields, err := encodeCustomFields(employee.CustomFields)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.conn().ExecContext(context.Background(), query,
 		employee.IdentityNumber,
 		employee.Name,
 		employee.Gender,
 		employee.DepartmentID,
 		employee.EmployeeImageURI,
+		customFields,
+		employee.Phone,
 	)
 	return err
 }
 
 func (r *EmployeeRepository) GetEmployeeByIdentityNumber(identityNumber string) (*models.Employee, error) {
 	query := `
-		SELECT identity_number, name, gender, department_id, employee_image_uri
+		SELECT identity_number, name, gender, department_id, employee_image_uri, custom_fields, phone
 		FROM employees
 		WHERE identity_number = $1
 	`
 	var employee models.Employee
-	err := r.DB.QueryRowContext(context.Background(), query, identityNumber).Scan(
+	var customFields []byte
+	err := r.conn().QueryRowContext(context.Background(), query, identityNumber).Scan(
 		&employee.IdentityNumber,
 		&employee.Name,
 		&employee.Gender,
 		&employee.DepartmentID,
 		&employee.EmployeeImageURI,
+		&customFields,
+		&employee.Phone,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if employee.CustomFields, err = decodeCustomFields(customFields); err != nil {
+		return nil, err
+	}
 	return &employee, nil
 }
 
 func (r *EmployeeRepository) UpdateEmployee(identityNumber string, updatedEmployee models.Employee) error {
+	// The identity number is immutable. A department change made through an
+	// update is still recorded as a transfer.
 	query := `
-		UPDATE employees
-		SET name = $1, gender = $2, department_id = $3, employee_image_uri = $4, identity_number = $5
-		WHERE identity_number = $6
+		WITH previous AS (
+			SELECT department_id FROM employees WHERE identity_number = $5
+		), updated AS (
+			UPDATE employees
+			SET name = $1, gender = $2, department_id = $3, employee_image_uri = $4, custom_fields = $6, phone = $7
+			WHERE identity_number = $5
+			RETURNING employment_id, identity_number, department_id
+		)
+		INSERT INTO employee_transfers (employment_id, identity_number, from_department_id, to_department_id, effective_date, reason)
+		SELECT updated.employment_id, updated.identity_number, previous.department_id, updated.department_id, CURRENT_DATE,
+			'Updated employee record'
+		FROM updated, previous
+		WHERE updated.department_id <> previous.department_id
 	`
-	_, err := r.DB.ExecContext(context.Background(), query,
+	customFields, err := encodeCustomFields(updatedEmployee.CustomFields)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.conn().ExecContext(context.Background(), query,
 		updatedEmployee.Name,
 		updatedEmployee.Gender,
 		updatedEmployee.DepartmentID,
 		updatedEmployee.EmployeeImageURI,
-		updatedEmployee.IdentityNumber,
 		identityNumber,
+		customFields,
+		updatedEmployee.Phone,
 	)
 	return err
 }
 
+// IsTenantDepartment reports whether the department belongs to the tenant.
+func (r *EmployeeRepository) IsTenantDepartment(userID uint, departmentID string) (bool, error) {
+	var exists bool
+	err := r.conn().QueryRowContext(context.Background(), `
+		SELECT EXISTS (SELECT 1 FROM department WHERE id::TEXT = $1 AND userId = $2)
+	`, departmentID, userID).Scan(&exists)
+	return exists, err
+}
+
+// DeleteEmployee removes the employee but keeps their status transitions, so
+// dates before the deletion still resolve. Unless they already left, the
+// deletion ends their employment today and drops anything scheduled later.
 func (r *EmployeeRepository) DeleteEmployee(identityNumber string) error {
-	query := `
-		DELETE FROM employees
-		WHERE identity_number = $1
-	`
-	_, err := r.DB.ExecContext(context.Background(), query, identityNumber)
-	return err
+	ctx := context.Background()
+	return r.inTx(ctx, func(txRepo *EmployeeRepository) error {
+		_, err := txRepo.conn().ExecContext(ctx, `
+			DELETE FROM employee_status_transitions
+			WHERE employment_id = `+currentEmploymentExpr("$1")+` AND effective_date > CURRENT_DATE
+		`, identityNumber)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			INSERT INTO employee_status_transitions (employment_id, identity_number, from_status, to_status, effective_date, reason)
+			SELECT e.employment_id, e.identity_number, status.current, 'terminated', CURRENT_DATE, 'Employee record deleted'
+			FROM employees e, LATERAL (SELECT ` + currentStatusExpr + ` AS current) status
+			WHERE e.identity_number = $1 AND status.current <> 'terminated'
+		`
+		if _, err := txRepo.conn().ExecContext(ctx, query, identityNumber); err != nil {
+			return err
+		}
+
+		_, err = txRepo.conn().ExecContext(ctx, "DELETE FROM employees WHERE identity_number = $1", identityNumber)
+		return err
+	})
 }
 
-func (r *EmployeeRepository) FilterEmployees(filters map[string]string) ([]models.Employee, error) {
-	query := `
-		SELECT identity_number, name, gender, department_id, employee_image_uri
-		FROM employees
-		WHERE 1=1
-	`
-	args := []interface{}{}
-	argCount := 1
+// employeeFilter holds the FROM and WHERE clauses shared by every query that
+// accepts the GET /employee filters.
+type employeeFilter struct {
+	from       string
+	where      string
+	statusExpr string
+	args       []interface{}
+}
+
+// arg adds a query argument and returns its placeholder.
+func (f *employeeFilter) arg(value interface{}) string {
+	f.args = append(f.args, value)
+	return fmt.Sprintf("$%d", len(f.args))
+}
+
+func newEmployeeFilter(filters map[string]string) *employeeFilter {
+	f := &employeeFilter{
+		from:       "employees e",
+		where:      "1=1",
+		statusExpr: currentStatusExpr,
+	}
+
+	// asOf rebuilds the list from the versions that were valid at that moment.
+	// Otherwise withDeleted reads every employment as last recorded, so those
+	// removed through DELETE /employee, whose transitions are kept, still count
+	// in the lifecycle figures. An employment stays within one tenant, so each
+	// earlier employment under a reused identity number is kept apart and
+	// matched only with its own transitions.
+	if asOf, ok := filters["asOf"]; ok {
+		at := f.arg(asOf)
+		f.statusExpr = statusOnExpr(f.arg(filters["asOfDate"]) + "::DATE")
+		f.from = "employees_history e"
+		f.where += fmt.Sprintf(" AND e.valid_from <= %s AND (e.valid_to IS NULL OR e.valid_to > %s)", at, at)
+	} else if filters["withDeleted"] == "true" {
+		f.from = `(
+			SELECT DISTINCT ON (employment_id) *
+			FROM employees_history
+			ORDER BY employment_id, valid_from DESC, id DESC
+		) e`
+	}
 
+	if userID, ok := filters["userId"]; ok {
+		f.where += " AND e.department_id IN (SELECT id FROM department WHERE userid = " + f.arg(userID) + ")"
+	}
 	if identityNumber, ok := filters["identityNumber"]; ok {
-		query += fmt.Sprintf(" AND identity_number LIKE $%d || '%%'", argCount)
-		args = append(args, identityNumber)
-		argCount++
+		f.where += " AND e.identity_number LIKE " + f.arg(identityNumber) + " || '%'"
 	}
 	if name, ok := filters["name"]; ok {
-		query += fmt.Sprintf(" AND name ILIKE $%d", argCount)
-		args = append(args, "%"+name+"%")
-		argCount++
+		f.where += " AND e.name ILIKE " + f.arg("%"+name+"%")
 	}
 	if gender, ok := filters["gender"]; ok {
-		query += fmt.Sprintf(" AND gender = $%d", argCount)
-		args = append(args, gender)
-		argCount++
+		f.where += " AND e.gender = " + f.arg(gender)
 	}
 	if departmentID, ok := filters["departmentId"]; ok {
-		query += fmt.Sprintf(" AND department_id = $%d", argCount)
-		args = append(args, departmentID)
-		argCount++
+		f.where += " AND e.department_id = " + f.arg(departmentID)
 	}
+	if status, ok := filters["status"]; ok {
+		f.where += " AND " + f.statusExpr + " = ANY(" + f.arg(pq.Array(statusFilterValues(status))) + ")"
+	}
+	// customFields is a JSON object the employee's custom fields must contain
+	if customFields, ok := filters["customFields"]; ok {
+		f.where += " AND e.custom_fields @> " + f.arg(customFields) + "::JSONB"
+	}
+
+	return f
+}
 
-	// Add LIMIT and OFFSET for pagination
-	limit, _ := strconv.Atoi(filters["limit"])
-	offset, _ := strconv.Atoi(filters["offset"])
-	if limit > 0 {
-		query += fmt.Sprintf(" LIMIT $%d", argCount)
-		args = append(args, limit)
-		argCount++
+// EmployeeKeyset lists the fields GET /employee can be sorted by.
+var EmployeeKeyset = utils.Keyset{
+	Columns: map[string]utils.SortColumn{
+		"name":           {Expr: "e.name", Type: "TEXT"},
+		"createdAt":      {Expr: "e.created_at", Type: "TIMESTAMP"},
+		"identityNumber": {Expr: "e.identity_number", Type: "TEXT"},
+	},
+	Key: utils.SortColumn{Expr: "e.identity_number", Type: "TEXT"},
+}
+
+func (r *EmployeeRepository) FilterEmployees(filters map[string]string, page utils.Page) ([]models.Employee, utils.PageInfo, error) {
+	f := newEmployeeFilter(filters)
+
+	var info utils.PageInfo
+	if page.WithTotal {
+		var total int
+		countQuery := "SELECT COUNT(*) FROM " + f.from + " WHERE " + f.where
+		if err := r.conn().QueryRowContext(context.Background(), countQuery, f.args...).Scan(&total); err != nil {
+			return nil, info, err
+		}
+		info.Total = &total
 	}
-	if offset > 0 {
-		query += fmt.Sprintf(" OFFSET $%d", argCount)
-		args = append(args, offset)
-		argCount++
+
+	condition, order, err := EmployeeKeyset.Clause(page, f.arg)
+	if err != nil {
+		return nil, info, err
+	}
+	sortExpr := EmployeeKeyset.Columns[page.Field()].Expr
+
+	query := `
+		SELECT e.identity_number, e.name, e.gender, e.department_id, e.employee_image_uri, ` + f.statusExpr + `,
+			e.created_at::TEXT, e.custom_fields, ` + sortExpr + `::TEXT
+		FROM ` + f.from + `
+		WHERE ` + f.where + ` AND ` + condition + `
+		ORDER BY ` + order
+
+	// One extra row tells whether there is a further page
+	query += " LIMIT " + f.arg(page.Limit+1)
+	if page.Cursor == nil && page.Offset > 0 {
+		query += " OFFSET " + f.arg(page.Offset)
 	}
 
-	rows, err := r.DB.QueryContext(context.Background(), query, args...)
+	rows, err := r.conn().QueryContext(context.Background(), query, f.args...)
 	if err != nil {
-		return nil, err
+		return nil, info, err
 	}
 	defer rows.Close()
 
-	var employees []models.Employee
+	employees := []models.Employee{}
+	sortValues := []string{}
 	for rows.Next() {
 		var emp models.Employee
+		var customFields []byte
+		var sortValue string
 		err := rows.Scan(
 			&emp.IdentityNumber,
 			&emp.Name,
 			&emp.Gender,
 			&emp.DepartmentID,
 			&emp.EmployeeImageURI,
+			&emp.Status,
+			&emp.CreatedAt,
+			&customFields,
+			&sortValue,
 		)
 		if err != nil {
-			return nil, err
+			return nil, info, err
+		}
+		if emp.CustomFields, err = decodeCustomFields(customFields); err != nil {
+			return nil, info, err
 		}
 		employees = append(employees, emp)
+		sortValues = append(sortValues, sortValue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, info, err
+	}
+
+	fetched := len(employees)
+	if fetched > page.Limit {
+		employees, sortValues = employees[:page.Limit], sortValues[:page.Limit]
+	}
+
+	// A backwards page was read in reverse order
+	if page.Cursor != nil && page.Cursor.Prev {
+		slices.Reverse(employees)
+		slices.Reverse(sortValues)
+	}
+
+	if len(employees) > 0 {
+		first := [2]string{sortValues[0], employees[0].IdentityNumber}
+		last := [2]string{sortValues[len(sortValues)-1], employees[len(employees)-1].IdentityNumber}
+		total := info.Total
+		info = page.Info(fetched, first, last)
+		info.Total = total
 	}
 
-	return employees, nil
+	return employees, info, nil
 }
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go-go-manager/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrAccountActive is returned when inviting an employee whose account is
+	// already in use.
+	ErrAccountActive = errors.New("employee already has an active account")
+	// ErrAccountEmailTaken is returned when another employee's account uses
+	// the email.
+	ErrAccountEmailTaken = errors.New("email is already used by another account")
+)
+
+const employeeAccountColumns = `
+	a.id, d.userid, a.identity_number, a.email,
+	CASE WHEN a.activated_at IS NULL THEN 'invited' ELSE 'active' END,
+	a.invited_by, a.invited_at::TEXT, a.invite_expires_at::TEXT, a.activated_at::TEXT
+`
+
+const employeeAccountFrom = `
+	FROM employee_accounts a
+	JOIN employees e ON e.identity_number = a.identity_number
+	JOIN department d ON d.id = e.department_id
+`
+
+func scanEmployeeAccount(scan func(dest ...interface{}) error) (models.EmployeeAccount, error) {
+	var a models.EmployeeAccount
+	err := scan(
+		&a.ID,
+		&a.UserID,
+		&a.IdentityNumber,
+		&a.Email,
+		&a.Status,
+		&a.InvitedBy,
+		&a.InvitedAt,
+		&a.InviteExpiresAt,
+		&a.ActivatedAt,
+	)
+	return a, err
+}
+
+// InviteEmployeeAccount creates the employee's account, or replaces the email
+// and invite token of an invitation that has not been accepted yet.
+func (r *EmployeeRepository) InviteEmployeeAccount(identityNumber string, email string, tokenHash string, expiresAt time.Time, invitedBy string) (models.EmployeeAccount, error) {
+	var id int
+	err := r.conn().QueryRowContext(context.Background(), `
+		INSERT INTO employee_accounts (identity_number, email, invite_token_hash, invite_expires_at, invited_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (identity_number) DO UPDATE
+		SET email = EXCLUDED.email, invite_token_hash = EXCLUDED.invite_token_hash,
+			invite_expires_at = EXCLUDED.invite_expires_at, invited_by = EXCLUDED.invited_by, invited_at = CURRENT_TIMESTAMP
+		WHERE employee_accounts.activated_at IS NULL
+		RETURNING id
+	`, identityNumber, email, tokenHash, expiresAt, invitedBy).Scan(&id)
+	if err == sql.ErrNoRows {
+		return models.EmployeeAccount{}, ErrAccountActive
+	}
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return models.EmployeeAccount{}, ErrAccountEmailTaken
+		}
+		return models.EmployeeAccount{}, err
+	}
+
+	return r.FindEmployeeAccountById(id)
+}
+
+func (r *EmployeeRepository) FindEmployeeAccountById(id int) (models.EmployeeAccount, error) {
+	query := "SELECT " + employeeAccountColumns + employeeAccountFrom + " WHERE a.id = $1"
+	return scanEmployeeAccount(r.conn().QueryRowContext(context.Background(), query, id).Scan)
+}
+
+func (r *EmployeeRepository) GetEmployeeAccount(identityNumber string) (models.EmployeeAccount, error) {
+	query := "SELECT " + employeeAccountColumns + employeeAccountFrom + " WHERE a.identity_number = $1"
+	return scanEmployeeAccount(r.conn().QueryRowContext(context.Background(), query, identityNumber).Scan)
+}
+
+// FindEmployeeAccountByEmail returns an active account together with its
+// password hash.
+func (r *EmployeeRepository) FindEmployeeAccountByEmail(email string) (models.EmployeeAccount, string, error) {
+	query := "SELECT " + employeeAccountColumns + ", a.password" + employeeAccountFrom +
+		" WHERE a.email = $1 AND a.activated_at IS NOT NULL"
+
+	var a models.EmployeeAccount
+	var password string
+	err := r.conn().QueryRowContext(context.Background(), query, email).Scan(
+		&a.ID,
+		&a.UserID,
+		&a.IdentityNumber,
+		&a.Email,
+		&a.Status,
+		&a.InvitedBy,
+		&a.InvitedAt,
+		&a.InviteExpiresAt,
+		&a.ActivatedAt,
+		&password,
+	)
+	return a, password, err
+}
+
+// ActivateEmployeeAccount sets the password of the account invited with the
+// token and makes the token unusable. It returns sql.ErrNoRows when the token
+// is unknown or has expired.
+func (r *EmployeeRepository) ActivateEmployeeAccount(tokenHash string, passwordHash string) (models.EmployeeAccount, error) {
+	var id int
+	err := r.conn().QueryRowContext(context.Background(), `
+		UPDATE employee_accounts
+		SET password = $1, activated_at = CURRENT_TIMESTAMP, invite_token_hash = NULL, invite_expires_at = NULL
+		WHERE invite_token_hash = $2 AND invite_expires_at > CURRENT_TIMESTAMP
+		RETURNING id
+	`, passwordHash, tokenHash).Scan(&id)
+	if err != nil {
+		return models.EmployeeAccount{}, err
+	}
+
+	return r.FindEmployeeAccountById(id)
+}
+
+// DeleteEmployeeAccount revokes the account; tokens issued to it stop working
+// since every request looks the account up.
+func (r *EmployeeRepository) DeleteEmployeeAccount(identityNumber string) (bool, error) {
+	result, err := r.conn().ExecContext(context.Background(),
+		"DELETE FROM employee_accounts WHERE identity_number = $1", identityNumber)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected > 0, err
+}
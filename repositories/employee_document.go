@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"context"
+	"go-go-manager/models"
+)
+
+const documentColumns = `
+	doc.id, doc.identity_number, doc.category, doc.title, doc.file_name, doc.content_type, doc.size_bytes,
+	doc.storage_key, doc.expires_on::TEXT, doc.uploaded_by, doc.created_at::TEXT
+`
+
+func documentFields(d *models.EmployeeDocument) []interface{} {
+	return []interface{}{
+		&d.ID,
+		&d.IdentityNumber,
+		&d.Category,
+		&d.Title,
+		&d.FileName,
+		&d.ContentType,
+		&d.SizeBytes,
+		&d.StorageKey,
+		&d.ExpiresOn,
+		&d.UploadedBy,
+		&d.CreatedAt,
+	}
+}
+
+func (r *EmployeeRepository) AddDocument(document models.EmployeeDocument) (models.EmployeeDocument, error) {
+	err := r.conn().QueryRowContext(context.Background(), `
+		INSERT INTO employee_documents (identity_number, category, title, file_name, content_type, size_bytes, storage_key, expires_on, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at::TEXT
+	`,
+		document.IdentityNumber,
+		document.Category,
+		document.Title,
+		document.FileName,
+		document.ContentType,
+		document.SizeBytes,
+		document.StorageKey,
+		document.ExpiresOn,
+		document.UploadedBy,
+	).Scan(&document.ID, &document.CreatedAt)
+	return document, err
+}
+
+// GetDocuments lists the employee's documents, newest first. An empty category returns all of them.
+func (r *EmployeeRepository) GetDocuments(identityNumber string, category string) ([]models.EmployeeDocument, error) {
+	query := `
+		SELECT ` + documentColumns + `
+		FROM employee_documents doc
+		WHERE doc.identity_number = $1 AND ($2 = '' OR doc.category = $2)
+		ORDER BY doc.created_at DESC, doc.id DESC
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, identityNumber, category)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	documents := []models.EmployeeDocument{}
+	for rows.Next() {
+		var d models.EmployeeDocument
+		if err := rows.Scan(documentFields(&d)...); err != nil {
+			return nil, err
+		}
+		documents = append(documents, d)
+	}
+
+	return documents, rows.Err()
+}
+
+func (r *EmployeeRepository) GetDocument(identityNumber string, id int) (*models.EmployeeDocument, error) {
+	query := `
+		SELECT ` + documentColumns + `
+		FROM employee_documents doc
+		WHERE doc.identity_number = $1 AND doc.id = $2
+	`
+	var d models.EmployeeDocument
+	if err := r.conn().QueryRowContext(context.Background(), query, identityNumber, id).Scan(documentFields(&d)...); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// GetDocumentStorageKeys returns the storage keys of the employee's documents,
+// whose files have to be removed along with the employee.
+func (r *EmployeeRepository) GetDocumentStorageKeys(identityNumber string) ([]string, error) {
+	rows, err := r.conn().QueryContext(context.Background(), "SELECT storage_key FROM employee_documents WHERE identity_number = $1", identityNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []string{}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *EmployeeRepository) DeleteDocument(id int) error {
+	_, err := r.conn().ExecContext(context.Background(), "DELETE FROM employee_documents WHERE id = $1", id)
+	return err
+}
+
+// GetExpiringDocuments lists the tenant's documents that expire within days,
+// soonest first. Documents that have already expired are included when
+// includeExpired is set.
+func (r *EmployeeRepository) GetExpiringDocuments(userID uint, days int, includeExpired bool) ([]models.ExpiringDocument, error) {
+	query := `
+		SELECT ` + documentColumns + `, e.name, e.department_id, doc.expires_on - CURRENT_DATE
+		FROM employee_documents doc
+		JOIN employees e ON e.identity_number = doc.identity_number
+		JOIN department d ON d.id = e.department_id
+		WHERE d.userid = $1
+			AND doc.expires_on <= CURRENT_DATE + $2::INT
+			AND ($3 OR doc.expires_on >= CURRENT_DATE)
+		ORDER BY doc.expires_on, doc.id
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, userID, days, includeExpired)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	documents := []models.ExpiringDocument{}
+	for rows.Next() {
+		var d models.ExpiringDocument
+		fields := append(documentFields(&d.EmployeeDocument), &d.Name, &d.DepartmentID, &d.DaysLeft)
+		if err := rows.Scan(fields...); err != nil {
+			return nil, err
+		}
+		documents = append(documents, d)
+	}
+
+	return documents, rows.Err()
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"go-go-manager/models"
+
+	"github.com/lib/pq"
+)
+
+// StreamEmployees calls fn for every employee matching the filters, one row at a
+// time, so callers can export any number of employees in constant memory.
+func (r *EmployeeRepository) StreamEmployees(ctx context.Context, filters map[string]string, fn func(models.EmployeeExportRow) error) error {
+	f := newEmployeeFilter(filters)
+	query := `
+		SELECT e.identity_number, e.name, e.gender, e.department_id, COALESCE(d.name, ''), e.employee_image_uri, ` + f.statusExpr + `
+		FROM ` + f.from + `
+		LEFT JOIN department d ON d.id = e.department_id
+		WHERE ` + f.where + `
+		ORDER BY e.identity_number
+	`
+	rows, err := r.conn().QueryContext(ctx, query, f.args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.EmployeeExportRow
+		err := rows.Scan(
+			&row.IdentityNumber,
+			&row.Name,
+			&row.Gender,
+			&row.DepartmentID,
+			&row.DepartmentName,
+			&row.EmployeeImageURI,
+			&row.Status,
+		)
+		if err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// StreamDepartments calls fn for every department of the user, one row at a time.
+func (r *EmployeeRepository) StreamDepartments(ctx context.Context, userID uint, name string, fn func(models.DepartmentExportRow) error) error {
+	// Headcount only includes staff who are currently employed
+	query := `
+		SELECT d.id, COALESCE(d.name, ''), COUNT(e.identity_number), d.created_at::TEXT
+		FROM department d
+		LEFT JOIN employees e ON e.department_id = d.id AND ` + currentStatusExpr + ` = ANY($2)
+		WHERE d.userid = $1
+	`
+	args := []interface{}{userID, pq.Array(statusFilterValues("current"))}
+
+	if name != "" {
+		query += " AND LOWER(d.name) LIKE LOWER($3)"
+		args = append(args, "%"+name+"%")
+	}
+
+	query += " GROUP BY d.id ORDER BY d.id"
+
+	rows, err := r.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.DepartmentExportRow
+		if err := rows.Scan(&row.DepartmentID, &row.Name, &row.Headcount, &row.CreatedAt); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
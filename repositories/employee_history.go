@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+	"go-go-manager/models"
+)
+
+// GetEmployeeHistory returns the versions recorded while the employee was in
+// one of the tenant's departments, including departments deleted since.
+func (r *EmployeeRepository) GetEmployeeHistory(userID uint, identityNumber string) ([]models.EmployeeVersion, error) {
+	query := `
+		SELECT identity_number, name, gender, department_id, employee_image_uri,
+			to_char(valid_from AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"'),
+			to_char(valid_to AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"')
+		FROM employees_history
+		WHERE identity_number = $1
+			AND department_id IN (SELECT department_id FROM department_history WHERE userId = $2)
+		ORDER BY valid_from, id
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, identityNumber, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []models.EmployeeVersion{}
+	for rows.Next() {
+		var v models.EmployeeVersion
+		err := rows.Scan(
+			&v.IdentityNumber,
+			&v.Name,
+			&v.Gender,
+			&v.DepartmentID,
+			&v.EmployeeImageURI,
+			&v.ValidFrom,
+			&v.ValidTo,
+		)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+
+	return versions, rows.Err()
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"go-go-manager/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ImportEmployees adds every employee and starts their onboarding in one
+// transaction, so either all of them are stored or none are.
+func (r *EmployeeRepository) ImportEmployees(userID uint, employees []models.Employee) error {
+	return r.inTx(context.Background(), func(txRepo *EmployeeRepository) error {
+		for i, employee := range employees {
+			if err := txRepo.AddEmployee(employee); err != nil {
+				return fmt.Errorf("employee %d (%s): %w", i+1, employee.IdentityNumber, err)
+			}
+			if _, err := txRepo.StartChecklists(userID, models.Onboarding, employee, time.Now()); err != nil {
+				return fmt.Errorf("employee %d (%s): %w", i+1, employee.IdentityNumber, err)
+			}
+		}
+		return nil
+	})
+}
+
+// ExistingIdentityNumbers reports which of the given identity numbers are already taken.
+func (r *EmployeeRepository) ExistingIdentityNumbers(identityNumbers []string) (map[string]bool, error) {
+	query := `
+		SELECT identity_number
+		FROM employees
+		WHERE identity_number = ANY($1)
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, pq.Array(identityNumbers))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var identityNumber string
+		if err := rows.Scan(&identityNumber); err != nil {
+			return nil, err
+		}
+		existing[identityNumber] = true
+	}
+
+	return existing, rows.Err()
+}
@@ -0,0 +1,178 @@
+package repositories
+
+import (
+	"context"
+	"go-go-manager/models"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// prefixTsQuery turns free text into a tsquery that matches every word as a
+// prefix, dropping characters that have a meaning in tsquery syntax.
+func prefixTsQuery(term string) string {
+	words := strings.FieldsFunc(term, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// restoreAccents carries the marks of a headline worked out on the unaccented
+// text over to the original. It only succeeds when unaccenting kept one
+// character per character, which holds for accented letters but not for
+// ligatures such as æ.
+func restoreAccents(text string, headline string) (string, bool) {
+	original := []rune(text)
+	var b strings.Builder
+	i := 0
+	for headline != "" {
+		switch {
+		case strings.HasPrefix(headline, "<mark>"):
+			b.WriteString("<mark>")
+			headline = headline[len("<mark>"):]
+		case strings.HasPrefix(headline, "</mark>"):
+			b.WriteString("</mark>")
+			headline = headline[len("</mark>"):]
+		default:
+			if i == len(original) {
+				return "", false
+			}
+			_, size := utf8.DecodeRuneInString(headline)
+			b.WriteRune(original[i])
+			headline = headline[size:]
+			i++
+		}
+	}
+	return b.String(), i == len(original)
+}
+
+// highlight picks the headline shown for text. Terms are matched without
+// accents, so the marks come from the unaccented headline where they can be
+// mapped back, and from the accent-sensitive one on the original otherwise.
+func highlight(text string, headline string, unaccentedHeadline string) string {
+	if restored, ok := restoreAccents(text, unaccentedHeadline); ok && strings.Contains(restored, "<mark>") {
+		return restored
+	}
+	if strings.Contains(headline, "<mark>") {
+		return headline
+	}
+	return ""
+}
+
+// SearchEmployees ranks employees by full-text and trigram similarity against
+// their name, identity number, department name and position title, so typos
+// and missing accents still match.
+func (r *EmployeeRepository) SearchEmployees(filters map[string]string, term string, limit int, offset int) ([]models.EmployeeSearchResult, error) {
+	f := newEmployeeFilter(filters)
+	raw := f.arg(term)
+	normTerm := "immutable_unaccent(" + f.arg(strings.ToLower(term)) + ")"
+	tsq := "to_tsquery('simple', immutable_unaccent(" + f.arg(prefixTsQuery(term)) + "))"
+	headline := func(text string) string {
+		return "ts_headline('simple', " + text + ", " + tsq + ", 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true')"
+	}
+
+	// Each expression matches one of the search indexes
+	nameTsv := "to_tsvector('simple', immutable_unaccent(e.name))"
+	nameNorm := "immutable_unaccent(lower(e.name))"
+	departmentTsv := "to_tsvector('simple', immutable_unaccent(COALESCE(d.name, '')))"
+	departmentNorm := "immutable_unaccent(lower(COALESCE(d.name, '')))"
+	titleTsv := "to_tsvector('simple', immutable_unaccent(COALESCE(p.job_title, '')))"
+	titleNorm := "immutable_unaccent(lower(COALESCE(p.job_title, '')))"
+
+	// An OR across the tables would rule out the indexes, so every kind of match
+	// is its own branch. The final WHERE rechecks the row itself, which keeps
+	// an asOf search to the version that was valid at the time.
+	query := `
+		WITH matches AS (
+			SELECT e.identity_number FROM ` + f.from + ` WHERE ` + nameTsv + ` @@ ` + tsq + `
+			UNION
+			SELECT e.identity_number FROM ` + f.from + ` WHERE ` + normTerm + ` <% ` + nameNorm + `
+			UNION
+			SELECT e.identity_number FROM ` + f.from + ` WHERE e.identity_number LIKE ` + raw + ` || '%'
+			UNION
+			SELECT e.identity_number
+			FROM department d
+			JOIN ` + f.from + ` ON e.department_id = d.id
+			WHERE ` + departmentTsv + ` @@ ` + tsq + ` OR ` + normTerm + ` <% ` + departmentNorm + `
+			UNION
+			SELECT p.identity_number
+			FROM positions p
+			WHERE p.identity_number IS NOT NULL AND (` + titleTsv + ` @@ ` + tsq + ` OR ` + normTerm + ` <% ` + titleNorm + `)
+		)
+		SELECT e.identity_number, e.name, e.gender, e.department_id, COALESCE(d.name, ''), e.employee_image_uri,
+			` + f.statusExpr + `, COALESCE(p.job_title, ''),
+			2 * ts_rank(` + nameTsv + `, ` + tsq + `)
+				+ word_similarity(` + normTerm + `, ` + nameNorm + `)
+				+ 0.5 * ts_rank(` + departmentTsv + `, ` + tsq + `)
+				+ 0.5 * word_similarity(` + normTerm + `, ` + departmentNorm + `)
+				+ 0.5 * ts_rank(` + titleTsv + `, ` + tsq + `)
+				+ 0.5 * word_similarity(` + normTerm + `, ` + titleNorm + `)
+				+ CASE WHEN e.identity_number LIKE ` + raw + ` || '%' THEN 1 ELSE 0 END AS score,
+			` + headline("e.name") + `, ` + headline("immutable_unaccent(e.name)") + `,
+			` + headline("COALESCE(d.name, '')") + `, ` + headline("immutable_unaccent(COALESCE(d.name, ''))") + `,
+			` + headline("COALESCE(p.job_title, '')") + `, ` + headline("immutable_unaccent(COALESCE(p.job_title, ''))") + `
+		FROM ` + f.from + `
+		LEFT JOIN department d ON d.id = e.department_id
+		LEFT JOIN positions p ON p.identity_number = e.identity_number
+		WHERE ` + f.where + `
+			AND e.identity_number IN (SELECT identity_number FROM matches)
+			AND (` + nameTsv + ` @@ ` + tsq + `
+				OR ` + normTerm + ` <% ` + nameNorm + `
+				OR e.identity_number LIKE ` + raw + ` || '%'
+				OR ` + departmentTsv + ` @@ ` + tsq + `
+				OR ` + normTerm + ` <% ` + departmentNorm + `
+				OR ` + titleTsv + ` @@ ` + tsq + `
+				OR ` + normTerm + ` <% ` + titleNorm + `)
+		ORDER BY score DESC, e.identity_number
+		LIMIT ` + f.arg(limit) + ` OFFSET ` + f.arg(offset)
+
+	rows, err := r.conn().QueryContext(context.Background(), query, f.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []models.EmployeeSearchResult{}
+	for rows.Next() {
+		var result models.EmployeeSearchResult
+		var nameHeadline, departmentHeadline, jobTitleHeadline string
+		var unaccentedNameHeadline, unaccentedDepartmentHeadline, unaccentedJobTitleHeadline string
+		err := rows.Scan(
+			&result.IdentityNumber,
+			&result.Name,
+			&result.Gender,
+			&result.DepartmentID,
+			&result.DepartmentName,
+			&result.EmployeeImageURI,
+			&result.Status,
+			&result.JobTitle,
+			&result.Score,
+			&nameHeadline,
+			&unaccentedNameHeadline,
+			&departmentHeadline,
+			&unaccentedDepartmentHeadline,
+			&jobTitleHeadline,
+			&unaccentedJobTitleHeadline,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Highlights = map[string]string{}
+		if h := highlight(result.Name, nameHeadline, unaccentedNameHeadline); h != "" {
+			result.Highlights["name"] = h
+		}
+		if h := highlight(result.DepartmentName, departmentHeadline, unaccentedDepartmentHeadline); h != "" {
+			result.Highlights["departmentName"] = h
+		}
+		if h := highlight(result.JobTitle, jobTitleHeadline, unaccentedJobTitleHeadline); h != "" {
+			result.Highlights["jobTitle"] = h
+		}
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
@@ -0,0 +1,275 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"go-go-manager/models"
+
+	"github.com/lib/pq"
+)
+
+func (r *EmployeeRepository) GetEmployeeSkills(identityNumber string) ([]models.EmployeeSkill, error) {
+	query := `
+		SELECT s.id, s.name, s.category, es.level, es.updated_at::TEXT
+		FROM employee_skills es
+		JOIN skills s ON s.id = es.skill_id
+		WHERE es.identity_number = $1
+		ORDER BY es.level DESC, s.name
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, identityNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	skills := []models.EmployeeSkill{}
+	for rows.Next() {
+		var s models.EmployeeSkill
+		if err := rows.Scan(&s.SkillID, &s.Name, &s.Category, &s.Level, &s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		skills = append(skills, s)
+	}
+
+	return skills, rows.Err()
+}
+
+// SetEmployeeSkill rates the employee in a skill, replacing any earlier rating.
+func (r *EmployeeRepository) SetEmployeeSkill(identityNumber string, skill models.Skill, level int) (models.EmployeeSkill, error) {
+	result := models.EmployeeSkill{SkillID: skill.ID, Name: skill.Name, Category: skill.Category, Level: level}
+	err := r.conn().QueryRowContext(context.Background(), `
+		INSERT INTO employee_skills (identity_number, skill_id, level)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (identity_number, skill_id) DO UPDATE
+		SET level = EXCLUDED.level, updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at::TEXT
+	`, identityNumber, skill.ID, level).Scan(&result.UpdatedAt)
+	return result, err
+}
+
+// RemoveEmployeeSkill reports whether the employee had a rating in the skill.
+func (r *EmployeeRepository) RemoveEmployeeSkill(identityNumber string, skillID int) (bool, error) {
+	result, err := r.conn().ExecContext(context.Background(),
+		"DELETE FROM employee_skills WHERE identity_number = $1 AND skill_id = $2", identityNumber, skillID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// SearchEmployeesBySkills returns the employees matching the list filters that
+// meet every criterion, strongest first by the sum of their matching levels.
+// Each match carries the ratings for the skills that were asked for.
+func (r *EmployeeRepository) SearchEmployeesBySkills(filters map[string]string, criteria []models.SkillCriterion, limit int, offset int) ([]models.SkillMatch, error) {
+	f := newEmployeeFilter(filters)
+
+	skillIDs := make([]int64, 0, len(criteria))
+	for _, criterion := range criteria {
+		f.where += " AND EXISTS (SELECT 1 FROM employee_skills es WHERE es.identity_number = e.identity_number" +
+			" AND es.skill_id = " + f.arg(criterion.SkillID) + " AND es.level >= " + f.arg(criterion.MinLevel) + ")"
+		skillIDs = append(skillIDs, int64(criterion.SkillID))
+	}
+	ids := f.arg(pq.Array(skillIDs))
+
+	query := `
+		WITH matches AS (
+			SELECT e.identity_number, e.name, e.department_id,
+				(SELECT SUM(es.level) FROM employee_skills es
+					WHERE es.identity_number = e.identity_number AND es.skill_id = ANY(` + ids + `)) AS score
+			FROM ` + f.from + `
+			WHERE ` + f.where + `
+			ORDER BY score DESC, e.name, e.identity_number
+			LIMIT ` + f.arg(limit) + ` OFFSET ` + f.arg(offset) + `
+		)
+		SELECT m.identity_number, m.name, m.department_id, s.id, s.name, s.category, es.level, es.updated_at::TEXT
+		FROM matches m
+		JOIN employee_skills es ON es.identity_number = m.identity_number AND es.skill_id = ANY(` + ids + `)
+		JOIN skills s ON s.id = es.skill_id
+		ORDER BY m.score DESC, m.name, m.identity_number, es.level DESC, s.name
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, f.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches := []models.SkillMatch{}
+	for rows.Next() {
+		var m models.SkillMatch
+		var s models.EmployeeSkill
+		err := rows.Scan(&m.IdentityNumber, &m.Name, &m.DepartmentID, &s.SkillID, &s.Name, &s.Category, &s.Level, &s.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		// Rows of one employee are adjacent
+		if len(matches) == 0 || matches[len(matches)-1].IdentityNumber != m.IdentityNumber {
+			m.Skills = []models.EmployeeSkill{}
+			matches = append(matches, m)
+		}
+		last := &matches[len(matches)-1]
+		last.Skills = append(last.Skills, s)
+	}
+
+	return matches, rows.Err()
+}
+
+const certificationColumns = `
+	c.id, c.identity_number, c.name, c.issuer, c.credential_id, c.issued_on::TEXT, c.expires_on::TEXT,
+	c.lapse_flagged_at::TEXT, c.created_at::TEXT
+`
+
+func certificationFields(c *models.Certification) []interface{} {
+	return []interface{}{
+		&c.ID,
+		&c.IdentityNumber,
+		&c.Name,
+		&c.Issuer,
+		&c.CredentialID,
+		&c.IssuedOn,
+		&c.ExpiresOn,
+		&c.LapseFlaggedAt,
+		&c.CreatedAt,
+	}
+}
+
+func (r *EmployeeRepository) AddCertification(certification models.Certification) (models.Certification, error) {
+	var created models.Certification
+	err := r.conn().QueryRowContext(context.Background(), `
+		INSERT INTO certifications AS c (identity_number, name, issuer, credential_id, issued_on, expires_on)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+certificationColumns,
+		certification.IdentityNumber,
+		certification.Name,
+		certification.Issuer,
+		certification.CredentialID,
+		certification.IssuedOn,
+		certification.ExpiresOn,
+	).Scan(certificationFields(&created)...)
+	return created, err
+}
+
+func (r *EmployeeRepository) GetCertifications(identityNumber string) ([]models.Certification, error) {
+	query := `
+		SELECT ` + certificationColumns + `
+		FROM certifications c
+		WHERE c.identity_number = $1
+		ORDER BY c.expires_on NULLS LAST, c.name
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, identityNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	certifications := []models.Certification{}
+	for rows.Next() {
+		var c models.Certification
+		if err := rows.Scan(certificationFields(&c)...); err != nil {
+			return nil, err
+		}
+		certifications = append(certifications, c)
+	}
+
+	return certifications, rows.Err()
+}
+
+func (r *EmployeeRepository) GetCertification(identityNumber string, id int) (*models.Certification, error) {
+	query := `
+		SELECT ` + certificationColumns + `
+		FROM certifications c
+		WHERE c.identity_number = $1 AND c.id = $2
+	`
+	var c models.Certification
+	if err := r.conn().QueryRowContext(context.Background(), query, identityNumber, id).Scan(certificationFields(&c)...); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// UpdateCertification saves the certification. A renewal, i.e. a new expiry
+// date, clears the lapse flag so the reminder job can raise it again.
+func (r *EmployeeRepository) UpdateCertification(certification models.Certification) (models.Certification, error) {
+	var updated models.Certification
+	err := r.conn().QueryRowContext(context.Background(), `
+		UPDATE certifications AS c
+		SET name = $1, issuer = $2, credential_id = $3, issued_on = $4, expires_on = $5,
+			lapse_flagged_at = CASE WHEN c.expires_on IS DISTINCT FROM $5::DATE THEN NULL ELSE c.lapse_flagged_at END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE c.id = $6
+		RETURNING `+certificationColumns,
+		certification.Name,
+		certification.Issuer,
+		certification.CredentialID,
+		certification.IssuedOn,
+		certification.ExpiresOn,
+		certification.ID,
+	).Scan(certificationFields(&updated)...)
+	return updated, err
+}
+
+func (r *EmployeeRepository) DeleteCertification(id int) error {
+	_, err := r.conn().ExecContext(context.Background(), "DELETE FROM certifications WHERE id = $1", id)
+	return err
+}
+
+func scanLapsingCertifications(rows *sql.Rows) ([]models.LapsingCertification, error) {
+	defer rows.Close()
+
+	certifications := []models.LapsingCertification{}
+	for rows.Next() {
+		var c models.LapsingCertification
+		fields := append(certificationFields(&c.Certification), &c.EmployeeName, &c.DepartmentID, &c.DaysLeft)
+		if err := rows.Scan(fields...); err != nil {
+			return nil, err
+		}
+		certifications = append(certifications, c)
+	}
+
+	return certifications, rows.Err()
+}
+
+// GetLapsingCertifications lists the tenant's certifications that expire
+// within days or have already expired, soonest first. flaggedOnly limits the
+// list to those the reminder job has flagged.
+func (r *EmployeeRepository) GetLapsingCertifications(userID uint, days int, flaggedOnly bool) ([]models.LapsingCertification, error) {
+	query := `
+		SELECT ` + certificationColumns + `, e.name, e.department_id, c.expires_on - CURRENT_DATE
+		FROM certifications c
+		JOIN employees e ON e.identity_number = c.identity_number
+		JOIN department d ON d.id = e.department_id
+		WHERE d.userid = $1
+			AND c.expires_on <= CURRENT_DATE + $2::INT
+			AND (NOT $3 OR c.lapse_flagged_at IS NOT NULL)
+		ORDER BY c.expires_on, c.id
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, userID, days, flaggedOnly)
+	if err != nil {
+		return nil, err
+	}
+	return scanLapsingCertifications(rows)
+}
+
+// FlagLapsingCertifications flags the certifications of every tenant that
+// enter the reminder window of days and returns the ones it flagged, so each
+// certification is reported once per expiry date.
+func (r *EmployeeRepository) FlagLapsingCertifications(ctx context.Context, days int) ([]models.LapsingCertification, error) {
+	query := `
+		WITH flagged AS (
+			UPDATE certifications
+			SET lapse_flagged_at = CURRENT_TIMESTAMP
+			WHERE lapse_flagged_at IS NULL AND expires_on <= CURRENT_DATE + $1::INT
+			RETURNING *
+		)
+		SELECT ` + certificationColumns + `, e.name, e.department_id, c.expires_on - CURRENT_DATE
+		FROM flagged c
+		JOIN employees e ON e.identity_number = c.identity_number
+		ORDER BY c.expires_on, c.id
+	`
+	rows, err := r.conn().QueryContext(ctx, query, days)
+	if err != nil {
+		return nil, err
+	}
+	return scanLapsingCertifications(rows)
+}
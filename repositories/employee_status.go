@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"go-go-manager/models"
+)
+
+// statusOnExpr resolves an employee's status on the given SQL date from the latest
+// transition of their employment that had taken effect by then. Employees whose
+// first transition is still in the future are treated as onboarding.
+func statusOnExpr(date string) string {
+	return `COALESCE((
+	SELECT t.to_status
+	FROM employee_status_transitions t
+	WHERE t.employment_id = e.employment_id AND t.effective_date <= ` + date + `
+	ORDER BY t.effective_date DESC, t.id DESC
+	LIMIT 1
+), 'onboarding')`
+}
+
+var currentStatusExpr = statusOnExpr("CURRENT_DATE")
+
+// currentEmploymentExpr picks the employment of the employee whose identity
+// number is the given placeholder, so records left by an earlier employment
+// under the same identity number are not read as theirs.
+func currentEmploymentExpr(identityNumber string) string {
+	return "(SELECT employment_id FROM employees WHERE identity_number = " + identityNumber + ")"
+}
+
+// statusFilterValues expands the status query filter into the statuses it covers.
+// "current" is the default and means staff who are still employed.
+func statusFilterValues(filter string) []string {
+	switch filter {
+	case "current":
+		return []string{string(models.StatusActive), string(models.StatusOnLeave)}
+	case "former":
+		return []string{string(models.StatusTerminated)}
+	case "all":
+		return []string{
+			string(models.StatusOnboarding),
+			string(models.StatusActive),
+			string(models.StatusOnLeave),
+			string(models.StatusTerminated),
+		}
+	default:
+		return []string{filter}
+	}
+}
+
+// GetLatestTransition returns the most recent transition including ones scheduled
+// for a future date, since new transitions must follow on from it.
+func (r *EmployeeRepository) GetLatestTransition(identityNumber string) (*models.StatusTransition, error) {
+	query := `
+		SELECT id, identity_number, COALESCE(from_status, ''), to_status, effective_date::TEXT, reason, termination_type,
+			regretted, created_at::TEXT
+		FROM employee_status_transitions
+		WHERE employment_id = ` + currentEmploymentExpr("$1") + `
+		ORDER BY effective_date DESC, id DESC
+		LIMIT 1
+	`
+	var t models.StatusTransition
+	err := r.conn().QueryRowContext(context.Background(), query, identityNumber).Scan(
+		&t.ID,
+		&t.IdentityNumber,
+		&t.FromStatus,
+		&t.ToStatus,
+		&t.EffectiveDate,
+		&t.Reason,
+		&t.TerminationType,
+		&t.Regretted,
+		&t.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *EmployeeRepository) AddStatusTransition(transition models.StatusTransition) (models.StatusTransition, error) {
+	query := `
+		INSERT INTO employee_status_transitions (employment_id, identity_number, from_status, to_status, effective_date,
+			reason, termination_type, regretted)
+		SELECT employment_id, identity_number, NULLIF($2, ''), $3, $4, $5, $6, $7
+		FROM employees
+		WHERE identity_number = $1
+		RETURNING id, created_at::TEXT
+	`
+	err := r.conn().QueryRowContext(context.Background(), query,
+		transition.IdentityNumber,
+		transition.FromStatus,
+		transition.ToStatus,
+		transition.EffectiveDate,
+		transition.Reason,
+		transition.TerminationType,
+		transition.Regretted,
+	).Scan(&transition.ID, &transition.CreatedAt)
+	return transition, err
+}
+
+func (r *EmployeeRepository) GetStatusTransitions(identityNumber string) ([]models.StatusTransition, error) {
+	query := `
+		SELECT id, identity_number, COALESCE(from_status, ''), to_status, effective_date::TEXT, reason, termination_type,
+			regretted, created_at::TEXT
+		FROM employee_status_transitions
+		WHERE employment_id = ` + currentEmploymentExpr("$1") + `
+		ORDER BY effective_date, id
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, identityNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transitions := []models.StatusTransition{}
+	for rows.Next() {
+		var t models.StatusTransition
+		err := rows.Scan(
+			&t.ID,
+			&t.IdentityNumber,
+			&t.FromStatus,
+			&t.ToStatus,
+			&t.EffectiveDate,
+			&t.Reason,
+			&t.TerminationType,
+			&t.Regretted,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		transitions = append(transitions, t)
+	}
+
+	return transitions, rows.Err()
+}
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"go-go-manager/models"
+)
+
+func (r *EmployeeRepository) GetLatestTransfer(identityNumber string) (*models.EmployeeTransfer, error) {
+	query := `
+		SELECT id, identity_number, COALESCE(from_department_id::TEXT, ''), COALESCE(to_department_id::TEXT, ''),
+			effective_date::TEXT, reason, approver, created_at::TEXT
+		FROM employee_transfers
+		WHERE employment_id = ` + currentEmploymentExpr("$1") + `
+		ORDER BY effective_date DESC, id DESC
+		LIMIT 1
+	`
+	var t models.EmployeeTransfer
+	err := r.conn().QueryRowContext(context.Background(), query, identityNumber).Scan(
+		&t.ID,
+		&t.IdentityNumber,
+		&t.FromDepartmentID,
+		&t.ToDepartmentID,
+		&t.EffectiveDate,
+		&t.Reason,
+		&t.Approver,
+		&t.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// TransferEmployee records the transfer and moves the employee in one transaction.
+func (r *EmployeeRepository) TransferEmployee(transfer models.EmployeeTransfer) (models.EmployeeTransfer, error) {
+	err := r.inTx(context.Background(), func(txRepo *EmployeeRepository) error {
+		err := txRepo.conn().QueryRowContext(context.Background(), `
+			INSERT INTO employee_transfers (employment_id, identity_number, from_department_id, to_department_id, effective_date,
+				reason, approver)
+			SELECT employment_id, identity_number, $2, $3, $4, $5, $6
+			FROM employees
+			WHERE identity_number = $1
+			RETURNING id, created_at::TEXT
+		`,
+			transfer.IdentityNumber,
+			transfer.FromDepartmentID,
+			transfer.ToDepartmentID,
+			transfer.EffectiveDate,
+			transfer.Reason,
+			transfer.Approver,
+		).Scan(&transfer.ID, &transfer.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		_, err = txRepo.conn().ExecContext(context.Background(), `
+			UPDATE employees
+			SET department_id = $1
+			WHERE identity_number = $2
+		`, transfer.ToDepartmentID, transfer.IdentityNumber)
+		return err
+	})
+	return transfer, err
+}
+
+// GetTimeline merges the status transitions and transfers of the employee's
+// current employment into one chronological list.
+func (r *EmployeeRepository) GetTimeline(identityNumber string) ([]models.TimelineEvent, error) {
+	query := `
+		WITH employment AS (
+			SELECT employment_id FROM employees WHERE identity_number = $1
+		)
+		SELECT 'status', effective_date::TEXT, reason, COALESCE(from_status, ''), to_status, '', '', '', created_at::TEXT
+		FROM employee_status_transitions
+		WHERE employment_id = (SELECT employment_id FROM employment)
+		UNION ALL
+		SELECT 'transfer', effective_date::TEXT, reason, '', '', COALESCE(from_department_id::TEXT, ''),
+			COALESCE(to_department_id::TEXT, ''), COALESCE(approver, ''), created_at::TEXT
+		FROM employee_transfers
+		WHERE employment_id = (SELECT employment_id FROM employment)
+		ORDER BY 2, 9
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, identityNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.TimelineEvent{}
+	for rows.Next() {
+		var event models.TimelineEvent
+		err := rows.Scan(
+			&event.Type,
+			&event.EffectiveDate,
+			&event.Reason,
+			&event.FromStatus,
+			&event.ToStatus,
+			&event.FromDepartmentID,
+			&event.ToDepartmentID,
+			&event.Approver,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
@@ -0,0 +1,336 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-go-manager/models"
+	"math"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ErrLeaveOverlap is returned when a leave request covers a day that another
+// pending or approved request of the same employee already covers.
+var ErrLeaveOverlap = errors.New("leave overlaps an existing request")
+
+const leaveRequestColumns = `
+	l.id, l.identity_number, l.leave_type_id, t.name, l.start_date::TEXT, l.end_date::TEXT, l.days, l.reason,
+	l.status, l.decided_by, l.decision_note, l.decided_at::TEXT, l.created_at::TEXT
+`
+
+func scanLeaveRequest(scan func(dest ...interface{}) error) (models.LeaveRequest, error) {
+	var l models.LeaveRequest
+	err := scan(
+		&l.ID,
+		&l.IdentityNumber,
+		&l.LeaveTypeID,
+		&l.LeaveType,
+		&l.StartDate,
+		&l.EndDate,
+		&l.Days,
+		&l.Reason,
+		&l.Status,
+		&l.DecidedBy,
+		&l.DecisionNote,
+		&l.DecidedAt,
+		&l.CreatedAt,
+	)
+	return l, err
+}
+
+func (r *EmployeeRepository) CreateLeaveRequest(leave models.LeaveRequest) (models.LeaveRequest, error) {
+	var id int
+	err := r.conn().QueryRowContext(context.Background(), `
+		INSERT INTO leave_requests (identity_number, leave_type_id, start_date, end_date, days, reason)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`,
+		leave.IdentityNumber,
+		leave.LeaveTypeID,
+		leave.StartDate,
+		leave.EndDate,
+		leave.Days,
+		leave.Reason,
+	).Scan(&id)
+	if err != nil {
+		// The exclusion constraint rejects overlapping open requests
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23P01" {
+			return leave, ErrLeaveOverlap
+		}
+		return leave, err
+	}
+
+	created, err := r.GetLeaveRequest(id)
+	if err != nil {
+		return leave, err
+	}
+	return *created, nil
+}
+
+func (r *EmployeeRepository) GetLeaveRequest(id int) (*models.LeaveRequest, error) {
+	query := `
+		SELECT ` + leaveRequestColumns + `
+		FROM leave_requests l
+		JOIN leave_types t ON t.id = l.leave_type_id
+		WHERE l.id = $1
+	`
+	l, err := scanLeaveRequest(r.conn().QueryRowContext(context.Background(), query, id).Scan)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// GetLeaveRequests lists leave requests, newest first. Supported filters are
+// userId, identityNumber, departmentId and status.
+func (r *EmployeeRepository) GetLeaveRequests(filters map[string]string) ([]models.LeaveRequest, error) {
+	where := "1=1"
+	args := []interface{}{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if userID, ok := filters["userId"]; ok {
+		where += " AND e.department_id IN (SELECT id FROM department WHERE userid = " + arg(userID) + ")"
+	}
+	if identityNumber, ok := filters["identityNumber"]; ok {
+		where += " AND l.identity_number = " + arg(identityNumber)
+	}
+	if departmentID, ok := filters["departmentId"]; ok {
+		where += " AND e.department_id = " + arg(departmentID)
+	}
+	if status, ok := filters["status"]; ok {
+		where += " AND l.status = " + arg(status)
+	}
+
+	query := `
+		SELECT ` + leaveRequestColumns + `
+		FROM leave_requests l
+		JOIN leave_types t ON t.id = l.leave_type_id
+		JOIN employees e ON e.identity_number = l.identity_number
+		WHERE ` + where + `
+		ORDER BY l.start_date DESC, l.id DESC
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := []models.LeaveRequest{}
+	for rows.Next() {
+		l, err := scanLeaveRequest(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, l)
+	}
+
+	return requests, rows.Err()
+}
+
+// DecideLeaveRequest moves a request to status, but only while it is still in
+// one of the from statuses. It returns sql.ErrNoRows when the request has
+// already moved on.
+func (r *EmployeeRepository) DecideLeaveRequest(id int, from []models.LeaveStatus, status models.LeaveStatus, decidedBy string, note *string) (models.LeaveRequest, error) {
+	allowed := make([]string, len(from))
+	for i, s := range from {
+		allowed[i] = string(s)
+	}
+
+	result, err := r.conn().ExecContext(context.Background(), `
+		UPDATE leave_requests
+		SET status = $1, decided_by = $2, decision_note = $3, decided_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND status = ANY($5)
+	`, status, decidedBy, note, id, pq.Array(allowed))
+	if err != nil {
+		return models.LeaveRequest{}, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return models.LeaveRequest{}, err
+	}
+	if rowsAffected == 0 {
+		return models.LeaveRequest{}, sql.ErrNoRows
+	}
+
+	decided, err := r.GetLeaveRequest(id)
+	if err != nil {
+		return models.LeaveRequest{}, err
+	}
+	return *decided, nil
+}
+
+// GetHireDate returns the date of the first status transition of the
+// employee's current employment, or the date the record was created for
+// employees without one.
+func (r *EmployeeRepository) GetHireDate(identityNumber string) (time.Time, error) {
+	query := `
+		SELECT COALESCE(
+			(SELECT MIN(effective_date) FROM employee_status_transitions WHERE employment_id = ` + currentEmploymentExpr("$1") + `),
+			(SELECT created_at::DATE FROM employees WHERE identity_number = $1),
+			CURRENT_DATE
+		)::TEXT
+	`
+	var hiredOn string
+	if err := r.conn().QueryRowContext(context.Background(), query, identityNumber).Scan(&hiredOn); err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.DateOnly, hiredOn)
+}
+
+// leaveUsage sums the approved and pending days per leave type that fall in
+// year. Leave running over New Year counts its working days towards each
+// year separately.
+func (r *EmployeeRepository) leaveUsage(identityNumber string, year int) (map[int]float64, map[int]float64, error) {
+	query := `
+		SELECT leave_type_id, status, SUM(
+			CASE
+				WHEN EXTRACT(YEAR FROM start_date) = $2::INT AND EXTRACT(YEAR FROM end_date) = $2::INT THEN days
+				ELSE (
+					SELECT COUNT(*)
+					FROM generate_series(
+						GREATEST(start_date, make_date($2::INT, 1, 1)),
+						LEAST(end_date, make_date($2::INT, 12, 31)),
+						INTERVAL '1 day'
+					) AS d
+					WHERE EXTRACT(ISODOW FROM d) < 6
+				)
+			END
+		)
+		FROM leave_requests
+		WHERE identity_number = $1
+			AND status IN ('approved', 'pending')
+			AND start_date <= make_date($2::INT, 12, 31)
+			AND end_date >= make_date($2::INT, 1, 1)
+		GROUP BY leave_type_id, status
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, identityNumber, year)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	taken := make(map[int]float64)
+	pending := make(map[int]float64)
+	for rows.Next() {
+		var leaveTypeID int
+		var status models.LeaveStatus
+		var days float64
+		if err := rows.Scan(&leaveTypeID, &status, &days); err != nil {
+			return nil, nil, err
+		}
+		if status == models.LeaveApproved {
+			taken[leaveTypeID] = days
+		} else {
+			pending[leaveTypeID] = days
+		}
+	}
+
+	return taken, pending, rows.Err()
+}
+
+// GetLeaveBalances works out the employee's balance for each leave type in
+// year as of asOf. Unused days of the previous year carry over up to the
+// leave type's limit.
+func (r *EmployeeRepository) GetLeaveBalances(identityNumber string, types []models.LeaveType, year int, asOf time.Time) ([]models.LeaveBalance, error) {
+	hiredOn, err := r.GetHireDate(identityNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	taken, pending, err := r.leaveUsage(identityNumber, year)
+	if err != nil {
+		return nil, err
+	}
+	previousTaken, _, err := r.leaveUsage(identityNumber, year-1)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]models.LeaveBalance, 0, len(types))
+	for _, t := range types {
+		balance := models.LeaveBalance{
+			LeaveTypeID: t.ID,
+			LeaveType:   t.Name,
+			Year:        year,
+			Taken:       taken[t.ID],
+			Pending:     pending[t.ID],
+		}
+
+		if t.Accrual != models.AccrualNone {
+			entitlement := t.Entitlement(year, hiredOn, asOf)
+			unused := t.Entitlement(year-1, hiredOn, asOf) - previousTaken[t.ID]
+			balance.CarriedOver = math.Max(0, math.Min(unused, t.MaxCarryOver))
+
+			available := entitlement + balance.CarriedOver - balance.Taken - balance.Pending
+			balance.Entitlement = &entitlement
+			balance.Available = &available
+		}
+
+		balances = append(balances, balance)
+	}
+
+	return balances, nil
+}
+
+// GetLeaveCalendar lists the leave of the tenant's employees that overlaps the
+// period from to to. Supported filters are departmentId and includePending.
+func (r *EmployeeRepository) GetLeaveCalendar(userID uint, from string, to string, filters map[string]string) ([]models.LeaveCalendarEntry, error) {
+	args := []interface{}{userID, from, to}
+	where := "d.userid = $1 AND l.start_date <= $3 AND l.end_date >= $2"
+
+	statuses := []string{string(models.LeaveApproved)}
+	if filters["includePending"] == "true" {
+		statuses = append(statuses, string(models.LeavePending))
+	}
+	args = append(args, pq.Array(statuses))
+	where += fmt.Sprintf(" AND l.status = ANY($%d)", len(args))
+
+	if departmentID, ok := filters["departmentId"]; ok {
+		args = append(args, departmentID)
+		where += fmt.Sprintf(" AND e.department_id = $%d", len(args))
+	}
+
+	query := `
+		SELECT l.id, e.identity_number, e.name, e.department_id, t.name, l.start_date::TEXT, l.end_date::TEXT, l.status
+		FROM leave_requests l
+		JOIN leave_types t ON t.id = l.leave_type_id
+		JOIN employees e ON e.identity_number = l.identity_number
+		JOIN department d ON d.id = e.department_id
+		WHERE ` + where + `
+		ORDER BY l.start_date, e.name
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.LeaveCalendarEntry{}
+	for rows.Next() {
+		var entry models.LeaveCalendarEntry
+		err := rows.Scan(
+			&entry.LeaveID,
+			&entry.IdentityNumber,
+			&entry.Name,
+			&entry.DepartmentID,
+			&entry.LeaveType,
+			&entry.StartDate,
+			&entry.EndDate,
+			&entry.Status,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go-go-manager/models"
+)
+
+// LogPersonalInfoAccess records that actor read or changed the employee's
+// personal information.
+func (r *EmployeeRepository) LogPersonalInfoAccess(userID uint, identityNumber string, actor string, action models.PersonalInfoAction) error {
+	_, err := r.conn().ExecContext(context.Background(), `
+		INSERT INTO personal_info_access_log (userId, identity_number, actor, action)
+		VALUES ($1, $2, $3, $4)
+	`, userID, identityNumber, actor, action)
+	return err
+}
+
+func (r *EmployeeRepository) GetPersonalInfo(identityNumber string) (models.PersonalInfo, error) {
+	ctx := context.Background()
+	info := models.PersonalInfo{IdentityNumber: identityNumber}
+
+	var address models.HomeAddress
+	err := r.conn().QueryRowContext(ctx, `
+		SELECT line1, line2, city, region, postal_code, country
+		FROM employee_addresses
+		WHERE identity_number = $1
+	`, identityNumber).Scan(
+		&address.Line1,
+		&address.Line2,
+		&address.City,
+		&address.Region,
+		&address.PostalCode,
+		&address.Country,
+	)
+	switch {
+	case err == nil:
+		info.HomeAddress = &address
+	case err != sql.ErrNoRows:
+		return info, err
+	}
+
+	rows, err := r.conn().QueryContext(ctx, `
+		SELECT id, name, relationship, phone
+		FROM emergency_contacts
+		WHERE identity_number = $1
+		ORDER BY position
+	`, identityNumber)
+	if err != nil {
+		return info, err
+	}
+	defer rows.Close()
+
+	info.EmergencyContacts = []models.EmergencyContact{}
+	for rows.Next() {
+		var contact models.EmergencyContact
+		if err := rows.Scan(&contact.ID, &contact.Name, &contact.Relationship, &contact.Phone); err != nil {
+			return info, err
+		}
+		info.EmergencyContacts = append(info.EmergencyContacts, contact)
+	}
+
+	return info, rows.Err()
+}
+
+// SavePersonalInfo replaces the employee's home address and emergency
+// contacts. A nil address removes the stored one.
+func (r *EmployeeRepository) SavePersonalInfo(info models.PersonalInfo) (models.PersonalInfo, error) {
+	var saved models.PersonalInfo
+	ctx := context.Background()
+	err := r.inTx(ctx, func(txRepo *EmployeeRepository) error {
+		if info.HomeAddress == nil {
+			if _, err := txRepo.conn().ExecContext(ctx, "DELETE FROM employee_addresses WHERE identity_number = $1", info.IdentityNumber); err != nil {
+				return err
+			}
+		} else {
+			address := info.HomeAddress
+			_, err := txRepo.conn().ExecContext(ctx, `
+				INSERT INTO employee_addresses (identity_number, line1, line2, city, region, postal_code, country)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				ON CONFLICT (identity_number) DO UPDATE
+				SET line1 = EXCLUDED.line1, line2 = EXCLUDED.line2, city = EXCLUDED.city, region = EXCLUDED.region,
+					postal_code = EXCLUDED.postal_code, country = EXCLUDED.country, updated_at = CURRENT_TIMESTAMP
+			`,
+				info.IdentityNumber,
+				address.Line1,
+				address.Line2,
+				address.City,
+				address.Region,
+				address.PostalCode,
+				address.Country,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		if _, err := txRepo.conn().ExecContext(ctx, "DELETE FROM emergency_contacts WHERE identity_number = $1", info.IdentityNumber); err != nil {
+			return err
+		}
+		for i, contact := range info.EmergencyContacts {
+			_, err := txRepo.conn().ExecContext(ctx, `
+				INSERT INTO emergency_contacts (identity_number, position, name, relationship, phone)
+				VALUES ($1, $2, $3, $4, $5)
+			`, info.IdentityNumber, i+1, contact.Name, contact.Relationship, contact.Phone)
+			if err != nil {
+				return err
+			}
+		}
+
+		var err error
+		saved, err = txRepo.GetPersonalInfo(info.IdentityNumber)
+		return err
+	})
+	return saved, err
+}
+
+// GetPersonalInfoAccessLog lists accesses to personal information, newest
+// first. Supported filters are userId, identityNumber, actor and action.
+func (r *EmployeeRepository) GetPersonalInfoAccessLog(filters map[string]string, limit int, offset int) ([]models.PersonalInfoAccess, error) {
+	where := "1=1"
+	args := []interface{}{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if userID, ok := filters["userId"]; ok {
+		where += " AND userId = " + arg(userID)
+	}
+	if identityNumber, ok := filters["identityNumber"]; ok {
+		where += " AND identity_number = " + arg(identityNumber)
+	}
+	if actor, ok := filters["actor"]; ok {
+		where += " AND actor = " + arg(actor)
+	}
+	if action, ok := filters["action"]; ok {
+		where += " AND action = " + arg(action)
+	}
+
+	query := `
+		SELECT id, identity_number, actor, action, accessed_at::TEXT
+		FROM personal_info_access_log
+		WHERE ` + where + `
+		ORDER BY accessed_at DESC, id DESC
+		LIMIT ` + arg(limit) + ` OFFSET ` + arg(offset)
+	rows, err := r.conn().QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.PersonalInfoAccess{}
+	for rows.Next() {
+		var entry models.PersonalInfoAccess
+		if err := rows.Scan(&entry.ID, &entry.IdentityNumber, &entry.Actor, &entry.Action, &entry.AccessedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
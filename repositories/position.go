@@ -0,0 +1,192 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-go-manager/models"
+
+	"github.com/lib/pq"
+)
+
+// ErrEmployeeHasPosition is returned when the employee already fills another position.
+var ErrEmployeeHasPosition = errors.New("the employee already fills another position")
+
+const positionColumns = `
+	p.id, p.department_id::TEXT, COALESCE(d.name, ''), p.job_title, p.level, p.identity_number, e.name,
+	p.vacant_since::TEXT, p.created_at::TEXT
+`
+
+const positionFrom = `
+	positions p
+	LEFT JOIN department d ON d.id = p.department_id
+	LEFT JOIN employees e ON e.identity_number = p.identity_number
+`
+
+func positionFields(p *models.Position) []interface{} {
+	return []interface{}{
+		&p.ID,
+		&p.DepartmentID,
+		&p.DepartmentName,
+		&p.JobTitle,
+		&p.Level,
+		&p.IdentityNumber,
+		&p.EmployeeName,
+		&p.VacantSince,
+		&p.CreatedAt,
+	}
+}
+
+func positionError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrEmployeeHasPosition
+	}
+	return err
+}
+
+func (r *EmployeeRepository) CreatePosition(userID uint, p models.Position) (models.Position, error) {
+	var id int
+	err := r.conn().QueryRowContext(context.Background(), `
+		INSERT INTO positions (userId, department_id, job_title, level, identity_number)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, userID, p.DepartmentID, p.JobTitle, p.Level, p.IdentityNumber).Scan(&id)
+	if err != nil {
+		return models.Position{}, positionError(err)
+	}
+	return r.GetPosition(userID, id)
+}
+
+func (r *EmployeeRepository) GetPosition(userID uint, id int) (models.Position, error) {
+	var p models.Position
+	query := "SELECT " + positionColumns + " FROM " + positionFrom + " WHERE p.id = $1 AND p.userId = $2"
+	err := r.conn().QueryRowContext(context.Background(), query, id, userID).Scan(positionFields(&p)...)
+	return p, err
+}
+
+// GetPositions lists positions by department and job title. Supported filters
+// are userId, departmentId and status (filled or vacant).
+func (r *EmployeeRepository) GetPositions(filters map[string]string) ([]models.Position, error) {
+	where := "1=1"
+	args := []interface{}{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if userID, ok := filters["userId"]; ok {
+		where += " AND p.userId = " + arg(userID)
+	}
+	if departmentID, ok := filters["departmentId"]; ok {
+		where += " AND p.department_id::TEXT = " + arg(departmentID)
+	}
+	switch filters["status"] {
+	case "filled":
+		where += " AND p.identity_number IS NOT NULL"
+	case "vacant":
+		where += " AND p.identity_number IS NULL"
+	}
+
+	query := "SELECT " + positionColumns + " FROM " + positionFrom + " WHERE " + where + " ORDER BY d.name, p.job_title, p.id"
+	rows, err := r.conn().QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	positions := []models.Position{}
+	for rows.Next() {
+		var p models.Position
+		if err := rows.Scan(positionFields(&p)...); err != nil {
+			return nil, err
+		}
+		positions = append(positions, p)
+	}
+
+	return positions, rows.Err()
+}
+
+func (r *EmployeeRepository) UpdatePosition(userID uint, p models.Position) (models.Position, error) {
+	_, err := r.conn().ExecContext(context.Background(), `
+		UPDATE positions
+		SET department_id = $1, job_title = $2, level = $3, identity_number = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $5 AND userId = $6
+	`, p.DepartmentID, p.JobTitle, p.Level, p.IdentityNumber, p.ID, userID)
+	if err != nil {
+		return models.Position{}, positionError(err)
+	}
+	return r.GetPosition(userID, p.ID)
+}
+
+func (r *EmployeeRepository) DeletePosition(userID uint, id int) error {
+	_, err := r.conn().ExecContext(context.Background(), "DELETE FROM positions WHERE id = $1 AND userId = $2", id, userID)
+	return err
+}
+
+// GetVacancies lists the tenant's vacant positions, longest open first. An
+// empty departmentID covers every department.
+func (r *EmployeeRepository) GetVacancies(userID uint, departmentID string) ([]models.Vacancy, error) {
+	query := `
+		SELECT ` + positionColumns + `, CURRENT_DATE - p.vacant_since::DATE
+		FROM ` + positionFrom + `
+		WHERE p.userId = $1 AND p.identity_number IS NULL AND ($2 = '' OR p.department_id::TEXT = $2)
+		ORDER BY p.vacant_since, p.id
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, userID, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	vacancies := []models.Vacancy{}
+	for rows.Next() {
+		var v models.Vacancy
+		if err := rows.Scan(append(positionFields(&v.Position), &v.DaysOpen)...); err != nil {
+			return nil, err
+		}
+		vacancies = append(vacancies, v)
+	}
+
+	return vacancies, rows.Err()
+}
+
+// GetStaffing compares the headcount plan of each of the tenant's departments
+// with its employees and positions. Employees still onboarding count, as they
+// already take a seat.
+func (r *EmployeeRepository) GetStaffing(userID uint) ([]models.DepartmentStaffing, error) {
+	query := `
+		SELECT
+			d.id::TEXT,
+			COALESCE(d.name, ''),
+			h.planned,
+			(SELECT COUNT(*) FROM employees e WHERE e.department_id = d.id AND ` + currentStatusExpr + ` <> 'terminated'),
+			(SELECT COUNT(*) FROM positions p WHERE p.department_id = d.id),
+			(SELECT COUNT(*) FROM positions p WHERE p.department_id = d.id AND p.identity_number IS NOT NULL)
+		FROM department d
+		LEFT JOIN headcount_plans h ON h.department_id = d.id
+		WHERE d.userId = $1
+		ORDER BY d.name, d.id
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	departments := []models.DepartmentStaffing{}
+	for rows.Next() {
+		var s models.DepartmentStaffing
+		if err := rows.Scan(&s.DepartmentID, &s.Name, &s.Planned, &s.Actual, &s.Positions, &s.Filled); err != nil {
+			return nil, err
+		}
+		s.Vacant = s.Positions - s.Filled
+		if s.Planned != nil {
+			gap := *s.Planned - s.Actual
+			s.Gap = &gap
+		}
+		departments = append(departments, s)
+	}
+
+	return departments, rows.Err()
+}
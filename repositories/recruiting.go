@@ -0,0 +1,340 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-go-manager/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrCandidateExists is returned when the email already applied to the opening.
+	ErrCandidateExists = errors.New("a candidate with this email already applied to the opening")
+	// ErrCandidateNotOffered is returned when hiring a candidate who is not at
+	// the offer stage, including one hired by a concurrent request.
+	ErrCandidateNotOffered = errors.New("only candidates with an offer can be hired")
+)
+
+const jobOpeningColumns = `
+	o.id, o.department_id::TEXT, COALESCE(d.name, ''), o.position_id, o.title, o.description, o.status,
+	(
+		SELECT COALESCE(jsonb_object_agg(s.stage, s.count), '{}')
+		FROM (SELECT stage, COUNT(*) AS count FROM candidates WHERE opening_id = o.id GROUP BY stage) s
+	),
+	o.created_at::TEXT
+`
+
+func scanJobOpening(scan func(dest ...interface{}) error) (models.JobOpening, error) {
+	var o models.JobOpening
+	var pipeline []byte
+	err := scan(&o.ID, &o.DepartmentID, &o.DepartmentName, &o.PositionID, &o.Title, &o.Description, &o.Status, &pipeline, &o.CreatedAt)
+	if err != nil {
+		return o, err
+	}
+	err = json.Unmarshal(pipeline, &o.Pipeline)
+	return o, err
+}
+
+func (r *EmployeeRepository) CreateJobOpening(userID uint, o models.JobOpening) (models.JobOpening, error) {
+	var id int
+	err := r.conn().QueryRowContext(context.Background(), `
+		INSERT INTO job_openings (userId, department_id, position_id, title, description)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, userID, o.DepartmentID, o.PositionID, o.Title, o.Description).Scan(&id)
+	if err != nil {
+		return models.JobOpening{}, err
+	}
+	return r.GetJobOpening(userID, id)
+}
+
+func (r *EmployeeRepository) GetJobOpening(userID uint, id int) (models.JobOpening, error) {
+	query := `
+		SELECT ` + jobOpeningColumns + `
+		FROM job_openings o
+		LEFT JOIN department d ON d.id = o.department_id
+		WHERE o.id = $1 AND o.userId = $2
+	`
+	return scanJobOpening(r.conn().QueryRowContext(context.Background(), query, id, userID).Scan)
+}
+
+// GetJobOpenings lists openings, newest first. Supported filters are userId,
+// departmentId and status.
+func (r *EmployeeRepository) GetJobOpenings(filters map[string]string) ([]models.JobOpening, error) {
+	where := "1=1"
+	args := []interface{}{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if userID, ok := filters["userId"]; ok {
+		where += " AND o.userId = " + arg(userID)
+	}
+	if departmentID, ok := filters["departmentId"]; ok {
+		where += " AND o.department_id::TEXT = " + arg(departmentID)
+	}
+	if status, ok := filters["status"]; ok {
+		where += " AND o.status = " + arg(status)
+	}
+
+	query := `
+		SELECT ` + jobOpeningColumns + `
+		FROM job_openings o
+		LEFT JOIN department d ON d.id = o.department_id
+		WHERE ` + where + `
+		ORDER BY o.created_at DESC, o.id DESC
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	openings := []models.JobOpening{}
+	for rows.Next() {
+		o, err := scanJobOpening(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		openings = append(openings, o)
+	}
+
+	return openings, rows.Err()
+}
+
+func (r *EmployeeRepository) UpdateJobOpening(userID uint, o models.JobOpening) (models.JobOpening, error) {
+	_, err := r.conn().ExecContext(context.Background(), `
+		UPDATE job_openings
+		SET department_id = $1, position_id = $2, title = $3, description = $4, status = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $6 AND userId = $7
+	`, o.DepartmentID, o.PositionID, o.Title, o.Description, o.Status, o.ID, userID)
+	if err != nil {
+		return models.JobOpening{}, err
+	}
+	return r.GetJobOpening(userID, o.ID)
+}
+
+const candidateColumns = `
+	c.id, c.opening_id, c.name, c.email, c.phone, c.stage, c.cv_key, c.cv_file_name, c.cv_content_type,
+	c.cv_size_bytes, c.identity_number, c.hired_at::TEXT, c.created_at::TEXT
+`
+
+func scanCandidate(scan func(dest ...interface{}) error) (models.Candidate, error) {
+	var c models.Candidate
+	var cvKey, cvFileName, cvContentType sql.NullString
+	var cvSize sql.NullInt64
+	err := scan(
+		&c.ID,
+		&c.OpeningID,
+		&c.Name,
+		&c.Email,
+		&c.Phone,
+		&c.Stage,
+		&cvKey,
+		&cvFileName,
+		&cvContentType,
+		&cvSize,
+		&c.IdentityNumber,
+		&c.HiredAt,
+		&c.CreatedAt,
+	)
+	if err != nil {
+		return c, err
+	}
+	if cvKey.Valid {
+		c.CV = &models.CandidateCV{
+			FileName:    cvFileName.String,
+			ContentType: cvContentType.String,
+			SizeBytes:   cvSize.Int64,
+			StorageKey:  cvKey.String,
+		}
+	}
+	return c, nil
+}
+
+func (r *EmployeeRepository) AddCandidate(c models.Candidate) (models.Candidate, error) {
+	err := r.conn().QueryRowContext(context.Background(), `
+		INSERT INTO candidates (opening_id, name, email, phone)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, stage, created_at::TEXT
+	`, c.OpeningID, c.Name, c.Email, c.Phone).Scan(&c.ID, &c.Stage, &c.CreatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return c, ErrCandidateExists
+		}
+	}
+	return c, err
+}
+
+// GetCandidate returns the candidate when their opening belongs to the tenant.
+func (r *EmployeeRepository) GetCandidate(userID uint, id int) (models.Candidate, error) {
+	query := `
+		SELECT ` + candidateColumns + `
+		FROM candidates c
+		JOIN job_openings o ON o.id = c.opening_id
+		WHERE c.id = $1 AND o.userId = $2
+	`
+	return scanCandidate(r.conn().QueryRowContext(context.Background(), query, id, userID).Scan)
+}
+
+// GetCandidates lists the opening's candidates in order of application. An
+// empty stage returns all of them.
+func (r *EmployeeRepository) GetCandidates(openingID int, stage string) ([]models.Candidate, error) {
+	query := `
+		SELECT ` + candidateColumns + `
+		FROM candidates c
+		WHERE c.opening_id = $1 AND ($2 = '' OR c.stage = $2)
+		ORDER BY c.created_at, c.id
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, openingID, stage)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	candidates := []models.Candidate{}
+	for rows.Next() {
+		c, err := scanCandidate(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, c)
+	}
+
+	return candidates, rows.Err()
+}
+
+func (r *EmployeeRepository) MoveCandidate(id int, stage models.CandidateStage) error {
+	_, err := r.conn().ExecContext(context.Background(), `
+		UPDATE candidates SET stage = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2
+	`, stage, id)
+	return err
+}
+
+// SetCandidateCV replaces the candidate's CV; the caller removes the previous file.
+func (r *EmployeeRepository) SetCandidateCV(id int, cv models.CandidateCV) error {
+	_, err := r.conn().ExecContext(context.Background(), `
+		UPDATE candidates
+		SET cv_key = $1, cv_file_name = $2, cv_content_type = $3, cv_size_bytes = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $5
+	`, cv.StorageKey, cv.FileName, cv.ContentType, cv.SizeBytes, id)
+	return err
+}
+
+func (r *EmployeeRepository) DeleteCandidate(id int) error {
+	_, err := r.conn().ExecContext(context.Background(), "DELETE FROM candidates WHERE id = $1", id)
+	return err
+}
+
+func (r *EmployeeRepository) AddCandidateNote(note models.CandidateNote) (models.CandidateNote, error) {
+	err := r.conn().QueryRowContext(context.Background(), `
+		INSERT INTO candidate_notes (candidate_id, stage, rating, note, author)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at::TEXT
+	`, note.CandidateID, note.Stage, note.Rating, note.Note, note.Author).Scan(&note.ID, &note.CreatedAt)
+	return note, err
+}
+
+// GetCandidateNotes lists the candidate's notes, oldest first.
+func (r *EmployeeRepository) GetCandidateNotes(candidateID int) ([]models.CandidateNote, error) {
+	rows, err := r.conn().QueryContext(context.Background(), `
+		SELECT id, candidate_id, stage, rating, note, author, created_at::TEXT
+		FROM candidate_notes
+		WHERE candidate_id = $1
+		ORDER BY created_at, id
+	`, candidateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notes := []models.CandidateNote{}
+	for rows.Next() {
+		var n models.CandidateNote
+		if err := rows.Scan(&n.ID, &n.CandidateID, &n.Stage, &n.Rating, &n.Note, &n.Author, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		notes = append(notes, n)
+	}
+
+	return notes, rows.Err()
+}
+
+// HireCandidate creates the employee from a candidate with an offer, starts
+// their onboarding checklists and marks the candidate hired. When the opening
+// is linked to a position that is still vacant in the employee's department,
+// the employee fills it and the opening closes.
+func (r *EmployeeRepository) HireCandidate(userID uint, candidateID int, employee models.Employee) (models.Candidate, error) {
+	ctx := context.Background()
+	var hired models.Candidate
+
+	err := r.inTx(ctx, func(txRepo *EmployeeRepository) error {
+		// The lock makes a concurrent hire of the same candidate wait and then fail
+		var stage models.CandidateStage
+		var openingID int
+		var positionID *int
+		err := txRepo.conn().QueryRowContext(ctx, `
+			SELECT c.stage, o.id, o.position_id
+			FROM candidates c
+			JOIN job_openings o ON o.id = c.opening_id
+			WHERE c.id = $1 AND o.userId = $2
+			FOR UPDATE OF c
+		`, candidateID, userID).Scan(&stage, &openingID, &positionID)
+		if err != nil {
+			return err
+		}
+		if stage != models.StageOffer {
+			return ErrCandidateNotOffered
+		}
+
+		if err := txRepo.AddEmployee(employee); err != nil {
+			return err
+		}
+		if _, err := txRepo.StartChecklists(userID, models.Onboarding, employee, time.Now()); err != nil {
+			return err
+		}
+
+		_, err = txRepo.conn().ExecContext(ctx, `
+			UPDATE candidates
+			SET stage = $1, identity_number = $2, hired_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $3
+		`, models.StageHired, employee.IdentityNumber, candidateID)
+		if err != nil {
+			return err
+		}
+
+		if positionID != nil {
+			result, err := txRepo.conn().ExecContext(ctx, `
+				UPDATE positions
+				SET identity_number = $1, updated_at = CURRENT_TIMESTAMP
+				WHERE id = $2 AND identity_number IS NULL AND department_id::TEXT = $3
+			`, employee.IdentityNumber, *positionID, employee.DepartmentID)
+			if err != nil {
+				return err
+			}
+			filled, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if filled > 0 {
+				_, err = txRepo.conn().ExecContext(ctx, `
+					UPDATE job_openings SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2
+				`, models.OpeningClosed, openingID)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		hired, err = txRepo.GetCandidate(userID, candidateID)
+		return err
+	})
+	return hired, err
+}
@@ -0,0 +1,357 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-go-manager/models"
+	"math"
+
+	"github.com/lib/pq"
+)
+
+// ErrReviewCycleExists is returned when the tenant already has a cycle with the same name.
+var ErrReviewCycleExists = errors.New("review cycle already exists")
+
+const reviewCycleColumns = `
+	rc.id, rc.template_id, rc.template, rc.name, rc.period, rc.period_start::TEXT, rc.period_end::TEXT,
+	rc.self_due::TEXT, rc.manager_due::TEXT, rc.status, (SELECT COUNT(*) FROM reviews r WHERE r.cycle_id = rc.id),
+	rc.created_by, rc.created_at::TEXT, rc.closed_at::TEXT
+`
+
+func scanReviewCycle(scan func(dest ...interface{}) error) (models.ReviewCycle, error) {
+	var c models.ReviewCycle
+	var template []byte
+	err := scan(
+		&c.ID,
+		&c.TemplateID,
+		&template,
+		&c.Name,
+		&c.Period,
+		&c.PeriodStart,
+		&c.PeriodEnd,
+		&c.SelfDue,
+		&c.ManagerDue,
+		&c.Status,
+		&c.Reviews,
+		&c.CreatedBy,
+		&c.CreatedAt,
+		&c.ClosedAt,
+	)
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(template, &c.Template)
+	return c, err
+}
+
+// CreateReviewCycle opens the cycle and creates a review for every current
+// employee of the tenant, or of departmentIDs when given. Each review is
+// assigned to the head of the employee's department at launch.
+func (r *EmployeeRepository) CreateReviewCycle(userID uint, cycle models.ReviewCycle, departmentIDs []string) (models.ReviewCycle, error) {
+	template, err := json.Marshal(cycle.Template)
+	if err != nil {
+		return cycle, err
+	}
+
+	var created models.ReviewCycle
+	ctx := context.Background()
+	err = r.inTx(ctx, func(txRepo *EmployeeRepository) error {
+		var id int
+		err := txRepo.conn().QueryRowContext(ctx, `
+			INSERT INTO review_cycles (userId, template_id, template, name, period, period_start, period_end, self_due,
+				manager_due, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING id
+		`,
+			userID,
+			cycle.TemplateID,
+			template,
+			cycle.Name,
+			cycle.Period,
+			cycle.PeriodStart,
+			cycle.PeriodEnd,
+			cycle.SelfDue,
+			cycle.ManagerDue,
+			cycle.CreatedBy,
+		).Scan(&id)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+				return ErrReviewCycleExists
+			}
+			return err
+		}
+
+		f := newEmployeeFilter(map[string]string{"userId": fmt.Sprint(userID), "status": "current"})
+		if len(departmentIDs) > 0 {
+			f.where += " AND e.department_id::TEXT = ANY(" + f.arg(pq.Array(departmentIDs)) + ")"
+		}
+		query := `
+			INSERT INTO reviews (cycle_id, identity_number, department_id, reviewer_identity_number)
+			SELECT ` + f.arg(id) + `, e.identity_number, e.department_id, NULLIF(d.head_identity_number, e.identity_number)
+			FROM ` + f.from + `
+			JOIN department d ON d.id = e.department_id
+			WHERE ` + f.where
+		if _, err := txRepo.conn().ExecContext(ctx, query, f.args...); err != nil {
+			return err
+		}
+
+		created, err = txRepo.GetReviewCycle(userID, id)
+		return err
+	})
+	return created, err
+}
+
+func (r *EmployeeRepository) GetReviewCycles(userID uint, status string) ([]models.ReviewCycle, error) {
+	query := `
+		SELECT ` + reviewCycleColumns + `
+		FROM review_cycles rc
+		WHERE rc.userId = $1 AND ($2 = '' OR rc.status = $2)
+		ORDER BY rc.period_start DESC, rc.id DESC
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, userID, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cycles := []models.ReviewCycle{}
+	for rows.Next() {
+		c, err := scanReviewCycle(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		cycles = append(cycles, c)
+	}
+
+	return cycles, rows.Err()
+}
+
+func (r *EmployeeRepository) GetReviewCycle(userID uint, id int) (models.ReviewCycle, error) {
+	query := "SELECT " + reviewCycleColumns + " FROM review_cycles rc WHERE rc.id = $1 AND rc.userId = $2"
+	return scanReviewCycle(r.conn().QueryRowContext(context.Background(), query, id, userID).Scan)
+}
+
+// updatedOne returns sql.ErrNoRows when a guarded update matched no row.
+func updatedOne(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// CloseReviewCycle returns sql.ErrNoRows when the cycle is not open.
+func (r *EmployeeRepository) CloseReviewCycle(userID uint, id int) (models.ReviewCycle, error) {
+	result, err := r.conn().ExecContext(context.Background(), `
+		UPDATE review_cycles SET status = 'closed', closed_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND userId = $2 AND status = 'open'
+	`, id, userID)
+	if err := updatedOne(result, err); err != nil {
+		return models.ReviewCycle{}, err
+	}
+	return r.GetReviewCycle(userID, id)
+}
+
+const reviewColumns = `
+	r.id, r.cycle_id, rc.name, rc.period_start::TEXT, rc.period_end::TEXT, r.identity_number, e.name,
+	r.department_id::TEXT, r.reviewer_identity_number, r.status, r.self_answers, r.self_submitted_at::TEXT,
+	r.manager_answers, r.manager_submitted_at::TEXT, r.manager_submitted_by, r.overall_rating,
+	r.finalized_at::TEXT, r.finalized_by
+`
+
+const reviewFrom = `
+	reviews r
+	JOIN review_cycles rc ON rc.id = r.cycle_id
+	JOIN employees e ON e.identity_number = r.identity_number
+`
+
+func scanReview(scan func(dest ...interface{}) error) (models.Review, error) {
+	var rv models.Review
+	var selfAnswers, managerAnswers []byte
+	err := scan(
+		&rv.ID,
+		&rv.CycleID,
+		&rv.CycleName,
+		&rv.PeriodStart,
+		&rv.PeriodEnd,
+		&rv.IdentityNumber,
+		&rv.Name,
+		&rv.DepartmentID,
+		&rv.ReviewerIdentityNumber,
+		&rv.Status,
+		&selfAnswers,
+		&rv.SelfSubmittedAt,
+		&managerAnswers,
+		&rv.ManagerSubmittedAt,
+		&rv.ManagerSubmittedBy,
+		&rv.OverallRating,
+		&rv.FinalizedAt,
+		&rv.FinalizedBy,
+	)
+	if err != nil {
+		return rv, err
+	}
+	if selfAnswers != nil {
+		if err := json.Unmarshal(selfAnswers, &rv.SelfAnswers); err != nil {
+			return rv, err
+		}
+	}
+	if managerAnswers != nil {
+		if err := json.Unmarshal(managerAnswers, &rv.ManagerAnswers); err != nil {
+			return rv, err
+		}
+	}
+	return rv, nil
+}
+
+// GetReview returns the review when it belongs to one of the tenant's cycles.
+func (r *EmployeeRepository) GetReview(userID uint, id int) (models.Review, error) {
+	query := "SELECT " + reviewColumns + " FROM " + reviewFrom + " WHERE r.id = $1 AND rc.userId = $2"
+	return scanReview(r.conn().QueryRowContext(context.Background(), query, id, userID).Scan)
+}
+
+// GetReviews lists reviews, latest cycle first. Supported filters are userId,
+// cycleId, departmentId, status and identityNumber.
+func (r *EmployeeRepository) GetReviews(filters map[string]string) ([]models.Review, error) {
+	where := "1=1"
+	args := []interface{}{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if userID, ok := filters["userId"]; ok {
+		where += " AND rc.userId = " + arg(userID)
+	}
+	if cycleID, ok := filters["cycleId"]; ok {
+		where += " AND r.cycle_id = " + arg(cycleID)
+	}
+	if departmentID, ok := filters["departmentId"]; ok {
+		where += " AND r.department_id = " + arg(departmentID)
+	}
+	if status, ok := filters["status"]; ok {
+		where += " AND r.status = " + arg(status)
+	}
+	if identityNumber, ok := filters["identityNumber"]; ok {
+		where += " AND r.identity_number = " + arg(identityNumber)
+	}
+
+	query := "SELECT " + reviewColumns + " FROM " + reviewFrom + " WHERE " + where + " ORDER BY rc.period_start DESC, e.name, r.id"
+	rows, err := r.conn().QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []models.Review{}
+	for rows.Next() {
+		rv, err := scanReview(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, rv)
+	}
+
+	return reviews, rows.Err()
+}
+
+// SubmitSelfAssessment saves the employee's answers while the manager has not
+// assessed the review yet. It returns sql.ErrNoRows when that is too late.
+func (r *EmployeeRepository) SubmitSelfAssessment(id int, answers map[string]interface{}) error {
+	encoded, err := json.Marshal(answers)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.conn().ExecContext(context.Background(), `
+		UPDATE reviews SET self_answers = $1, self_submitted_at = CURRENT_TIMESTAMP, status = 'self_submitted'
+		WHERE id = $2 AND status IN ('pending', 'self_submitted')
+	`, encoded, id)
+	return updatedOne(result, err)
+}
+
+// SubmitManagerAssessment saves the manager's answers and overall rating until
+// the review is finalized. It returns sql.ErrNoRows when that is too late.
+func (r *EmployeeRepository) SubmitManagerAssessment(id int, answers map[string]interface{}, rating float64, submittedBy string) error {
+	encoded, err := json.Marshal(answers)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.conn().ExecContext(context.Background(), `
+		UPDATE reviews
+		SET manager_answers = $1, overall_rating = $2, manager_submitted_at = CURRENT_TIMESTAMP,
+			manager_submitted_by = $3, status = 'manager_submitted'
+		WHERE id = $4 AND status <> 'finalized'
+	`, encoded, math.Round(rating*100)/100, submittedBy, id)
+	return updatedOne(result, err)
+}
+
+// FinalizeReview locks a review whose manager assessment is in. It returns
+// sql.ErrNoRows when the review is in any other state.
+func (r *EmployeeRepository) FinalizeReview(id int, finalizedBy string) error {
+	result, err := r.conn().ExecContext(context.Background(), `
+		UPDATE reviews SET status = 'finalized', finalized_at = CURRENT_TIMESTAMP, finalized_by = $1
+		WHERE id = $2 AND status = 'manager_submitted'
+	`, finalizedBy, id)
+	return updatedOne(result, err)
+}
+
+// GetReviewProgress counts the cycle's reviews per department as of today.
+func (r *EmployeeRepository) GetReviewProgress(cycleID int, today string) ([]models.ReviewProgress, error) {
+	query := `
+		SELECT r.department_id::TEXT, COALESCE(d.name, ''), COUNT(*),
+			COUNT(*) FILTER (WHERE r.status = 'pending'),
+			COUNT(*) FILTER (WHERE r.status = 'self_submitted'),
+			COUNT(*) FILTER (WHERE r.status = 'manager_submitted'),
+			COUNT(*) FILTER (WHERE r.status = 'finalized'),
+			COUNT(*) FILTER (WHERE r.status = 'pending' AND rc.self_due < $2::DATE),
+			COUNT(*) FILTER (WHERE r.status IN ('pending', 'self_submitted') AND rc.manager_due < $2::DATE)
+		FROM reviews r
+		JOIN review_cycles rc ON rc.id = r.cycle_id
+		LEFT JOIN department d ON d.id = r.department_id
+		WHERE r.cycle_id = $1
+		GROUP BY r.department_id, d.name
+		ORDER BY d.name, r.department_id
+	`
+	rows, err := r.conn().QueryContext(context.Background(), query, cycleID, today)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	progress := []models.ReviewProgress{}
+	for rows.Next() {
+		var p models.ReviewProgress
+		err := rows.Scan(
+			&p.DepartmentID,
+			&p.Name,
+			&p.Total,
+			&p.Pending,
+			&p.SelfSubmitted,
+			&p.ManagerSubmitted,
+			&p.Finalized,
+			&p.OverdueSelf,
+			&p.OverdueManager,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if p.Total > 0 {
+			p.CompletionRate = math.Round(float64(p.Finalized)/float64(p.Total)*10000) / 100
+		}
+		progress = append(progress, p)
+	}
+
+	return progress, rows.Err()
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+)
+
+// DBTX is implemented by both *sql.DB and *sql.Tx, so repository queries can
+// run either directly or as part of a caller's transaction.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// WithTx returns a copy of the repository whose queries run inside tx.
+func (r *EmployeeRepository) WithTx(tx *sql.Tx) *EmployeeRepository {
+	return &EmployeeRepository{DB: r.DB, tx: tx}
+}
+
+func (r *EmployeeRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// inTx runs fn inside the repository's transaction when it has one, otherwise
+// inside a new transaction that is committed when fn succeeds.
+func (r *EmployeeRepository) inTx(ctx context.Context, fn func(txRepo *EmployeeRepository) error) error {
+	if r.tx != nil {
+		return fn(r)
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(r.WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Transaction runs fn with a repository bound to a single transaction, which is
+// committed if fn returns nil and rolled back otherwise.
+func (r *EmployeeRepository) Transaction(fn func(txRepo *EmployeeRepository) error) error {
+	return r.inTx(context.Background(), fn)
+}
+
+// Savepoint runs fn inside a savepoint of the repository's transaction and rolls
+// back to it if fn fails, so the rest of the transaction can still commit.
+func (r *EmployeeRepository) Savepoint(name string, fn func() error) error {
+	if r.tx == nil {
+		return fn()
+	}
+
+	ctx := context.Background()
+	if _, err := r.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+
+	if err := fn(); err != nil {
+		if _, rbErr := r.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+			return rbErr
+		}
+		return err
+	}
+
+	_, err := r.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	return err
+}
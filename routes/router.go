@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"go-go-manager/config"
 	v1 "go-go-manager/controllers/v1"
+	"go-go-manager/models"
 	"go-go-manager/utils"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -15,28 +16,170 @@ import (
 func SetupRouter(cfg *config.Config, db *sql.DB, s3Client *s3.Client, bucketName string) *gin.Engine {
 	router := gin.Default()
 
-	employeeHandler := v1.NewEmployeeHandler(db)
 	v1FileHandler := v1.NewFileHandler(cfg)
+	employeeHandler := v1.NewEmployeeHandler(db, v1FileHandler)
+	documentHandler := v1.NewDocumentHandler(db, v1FileHandler)
+	recruitingHandler := v1.NewRecruitingHandler(db, v1FileHandler)
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		v.RegisterValidation("isImage", utils.IsImageURI)
 	}
 
+	// National identity number schemes tenants can pick from
+	utils.RegisterIdentityScheme("nik", utils.CheckNIK)
+
 	v1Group := router.Group("/api/v1")
 	{
 		v1Group.POST("/auth", v1.AuthHandler)
 		v1Group.GET("/user", v1.GetUsers)
 		v1Group.PATCH("/user", v1.UpdateUser)
+		v1Group.GET("/member", v1.GetMembers)
+		v1Group.POST("/member", v1.CreateMember)
+		v1Group.PATCH("/member/:memberId", v1.UpdateMember)
+		v1Group.DELETE("/member/:memberId", v1.DeleteMember)
 		v1Group.POST("/department", v1.CreateDepartment)
 		v1Group.GET("/department", v1.GetDepartments)
+		v1Group.GET("/department/export", employeeHandler.ExportDepartments())
 		v1Group.PATCH("/department/:departmentId", v1.UpdateDepartment)
 		v1Group.DELETE("/department/:departmentId", v1.DeleteDepartment)
+		v1Group.GET("/department/:departmentId/flows", v1.GetDepartmentFlows)
+		v1Group.PUT("/department/:departmentId/head", v1.SetDepartmentHead)
+		v1Group.PUT("/department/:departmentId/headcount-plan", v1.SaveHeadcountPlan)
+		v1Group.DELETE("/department/:departmentId/headcount-plan", v1.DeleteHeadcountPlan)
+		v1Group.POST("/custom-field", v1.CreateCustomField)
+		v1Group.GET("/custom-field", v1.GetCustomFields)
+		v1Group.PATCH("/custom-field/:key", v1.UpdateCustomField)
+		v1Group.DELETE("/custom-field/:key", v1.DeleteCustomField)
+		v1Group.GET("/identity-scheme", v1.GetIdentityScheme)
+		v1Group.PUT("/identity-scheme", v1.UpdateIdentityScheme)
 
 		// Employee routes
 		v1Group.POST("/employee", employeeHandler.CreateEmployee())
 		v1Group.GET("/employee", employeeHandler.GetEmployees())
+		v1Group.GET("/employee/export", employeeHandler.ExportEmployees())
+		v1Group.POST("/employee/batch", employeeHandler.BatchEmployees())
+		v1Group.POST("/employee/import", employeeHandler.ImportEmployees())
+		v1Group.GET("/employee/search", employeeHandler.SearchEmployees())
 		v1Group.PATCH("/employee/:identityNumber", employeeHandler.UpdateEmployee())
 		v1Group.DELETE("/employee/:identityNumber", employeeHandler.DeleteEmployee())
+		v1Group.GET("/employee/:identityNumber/history", employeeHandler.GetEmployeeHistory())
+		v1Group.GET("/employee/:identityNumber/status", employeeHandler.GetEmployeeStatusHistory())
+		v1Group.POST("/employee/:identityNumber/status", employeeHandler.TransitionEmployeeStatus())
+		v1Group.GET("/employee/:identityNumber/timeline", employeeHandler.GetEmployeeTimeline())
+		v1Group.POST("/employee/:identityNumber/transfer", employeeHandler.TransferEmployee())
+		v1Group.GET("/employee/:identityNumber/leave", employeeHandler.GetEmployeeLeave())
+		v1Group.POST("/employee/:identityNumber/leave", employeeHandler.RequestLeave())
+		v1Group.GET("/employee/:identityNumber/leave/balance", employeeHandler.GetLeaveBalances())
+		v1Group.POST("/employee/:identityNumber/clock-in", employeeHandler.Clock(models.ClockIn))
+		v1Group.POST("/employee/:identityNumber/clock-out", employeeHandler.Clock(models.ClockOut))
+		v1Group.DELETE("/employee/:identityNumber/attendance/:eventId", employeeHandler.DeleteAttendanceEvent())
+		v1Group.GET("/employee/:identityNumber/timesheet", employeeHandler.GetTimesheet())
+		v1Group.POST("/employee/:identityNumber/timesheet/approve", employeeHandler.ApproveTimesheet())
+		v1Group.GET("/employee/:identityNumber/document", documentHandler.GetDocuments())
+		v1Group.POST("/employee/:identityNumber/document", documentHandler.UploadDocument())
+		v1Group.GET("/employee/:identityNumber/document/:documentId/download", documentHandler.DownloadDocument())
+		v1Group.DELETE("/employee/:identityNumber/document/:documentId", documentHandler.DeleteDocument())
+		v1Group.GET("/document/expiring", documentHandler.GetExpiringDocuments())
+		v1Group.GET("/employee/:identityNumber/skill", employeeHandler.GetEmployeeSkills())
+		v1Group.PUT("/employee/:identityNumber/skill/:skillId", employeeHandler.SetEmployeeSkill())
+		v1Group.DELETE("/employee/:identityNumber/skill/:skillId", employeeHandler.RemoveEmployeeSkill())
+		v1Group.GET("/employee/:identityNumber/certification", employeeHandler.GetCertifications())
+		v1Group.POST("/employee/:identityNumber/certification", employeeHandler.AddCertification())
+		v1Group.PATCH("/employee/:identityNumber/certification/:certificationId", employeeHandler.UpdateCertification())
+		v1Group.DELETE("/employee/:identityNumber/certification/:certificationId", employeeHandler.DeleteCertification())
+		v1Group.GET("/employee/:identityNumber/compensation", employeeHandler.GetCompensations())
+		v1Group.POST("/employee/:identityNumber/compensation", employeeHandler.AddCompensation())
+		v1Group.GET("/employee/:identityNumber/compensation/history", employeeHandler.GetCompensationHistory())
+		v1Group.PATCH("/employee/:identityNumber/compensation/:compensationId", employeeHandler.UpdateCompensation())
+		v1Group.DELETE("/employee/:identityNumber/compensation/:compensationId", employeeHandler.DeleteCompensation())
+		v1Group.GET("/payroll/cost", employeeHandler.GetPayrollCost())
+		v1Group.GET("/payroll/pay-gap", employeeHandler.GetPayGapReport())
+		v1Group.GET("/payroll/pay-gap/export", employeeHandler.ExportPayGapReport())
+		v1Group.GET("/analytics/headcount", employeeHandler.GetHeadcountAnalytics())
+		v1Group.GET("/analytics/turnover", employeeHandler.GetTurnoverAnalytics())
+		v1Group.GET("/analytics/staffing", employeeHandler.GetStaffingAnalytics())
+		v1Group.GET("/employee/:identityNumber/review", employeeHandler.GetEmployeeReviews())
+		v1Group.GET("/employee/:identityNumber/personal-info", employeeHandler.GetPersonalInfo())
+		v1Group.PUT("/employee/:identityNumber/personal-info", employeeHandler.UpdatePersonalInfo())
+		v1Group.GET("/personal-info/access-log", employeeHandler.GetPersonalInfoAccessLog())
+		v1Group.GET("/employee/:identityNumber/account", employeeHandler.GetEmployeeAccount())
+		v1Group.POST("/employee/:identityNumber/account", employeeHandler.InviteEmployeeAccount())
+		v1Group.DELETE("/employee/:identityNumber/account", employeeHandler.RevokeEmployeeAccount())
+
+		// Self-service routes, authenticated with employee tokens
+		v1Group.POST("/self/activate", employeeHandler.ActivateAccount())
+		v1Group.POST("/self/auth", employeeHandler.SelfAuth())
+		v1Group.GET("/self", employeeHandler.GetSelf())
+		v1Group.PATCH("/self", employeeHandler.UpdateSelf())
+		v1Group.GET("/self/leave/balance", employeeHandler.GetSelfLeaveBalances())
+		v1Group.POST("/self/leave/:leaveId/approve", employeeHandler.DecideLeaveAsHead(models.LeaveApproved))
+		v1Group.POST("/self/leave/:leaveId/reject", employeeHandler.DecideLeaveAsHead(models.LeaveRejected))
+		v1Group.POST("/self/review/:reviewId/manager", employeeHandler.SubmitReviewerAssessment())
+
+		// Skill routes
+		v1Group.POST("/skill", v1.CreateSkill)
+		v1Group.GET("/skill", v1.GetSkills)
+		v1Group.GET("/skill/search", employeeHandler.SearchEmployeesBySkills())
+		v1Group.PATCH("/skill/:skillId", v1.UpdateSkill)
+		v1Group.DELETE("/skill/:skillId", v1.DeleteSkill)
+		v1Group.GET("/certification/lapsing", employeeHandler.GetLapsingCertifications())
+
+		// Leave routes
+		v1Group.POST("/leave-type", v1.CreateLeaveType)
+		v1Group.GET("/leave-type", v1.GetLeaveTypes)
+		v1Group.PATCH("/leave-type/:leaveTypeId", v1.UpdateLeaveType)
+		v1Group.GET("/leave", employeeHandler.GetLeaveRequests())
+		v1Group.GET("/leave/calendar", employeeHandler.GetLeaveCalendar())
+		v1Group.POST("/leave/:leaveId/approve", employeeHandler.DecideLeave(models.LeaveApproved))
+		v1Group.POST("/leave/:leaveId/reject", employeeHandler.DecideLeave(models.LeaveRejected))
+		v1Group.POST("/leave/:leaveId/cancel", employeeHandler.DecideLeave(models.LeaveCancelled))
+
+		// Checklist routes
+		v1Group.POST("/checklist-template", v1.CreateChecklistTemplate)
+		v1Group.GET("/checklist-template", v1.GetChecklistTemplates)
+		v1Group.PATCH("/checklist-template/:templateId", v1.UpdateChecklistTemplate)
+		v1Group.GET("/checklist", employeeHandler.GetChecklists())
+		v1Group.GET("/checklist/overdue", employeeHandler.GetOverdueTasks())
+		v1Group.GET("/checklist/:checklistId", employeeHandler.GetChecklist())
+		v1Group.PATCH("/checklist/task/:taskId", employeeHandler.UpdateChecklistTask())
+		v1Group.POST("/position", employeeHandler.CreatePosition())
+		v1Group.GET("/position", employeeHandler.GetPositions())
+		v1Group.GET("/position/vacancy", employeeHandler.GetVacancies())
+		v1Group.GET("/position/:positionId", employeeHandler.GetPosition())
+		v1Group.PATCH("/position/:positionId", employeeHandler.UpdatePosition())
+		v1Group.DELETE("/position/:positionId", employeeHandler.DeletePosition())
+		v1Group.POST("/job-opening", recruitingHandler.CreateJobOpening())
+		v1Group.GET("/job-opening", recruitingHandler.GetJobOpenings())
+		v1Group.GET("/job-opening/:openingId", recruitingHandler.GetJobOpening())
+		v1Group.PATCH("/job-opening/:openingId", recruitingHandler.UpdateJobOpening())
+		v1Group.GET("/job-opening/:openingId/candidate", recruitingHandler.GetCandidates())
+		v1Group.POST("/job-opening/:openingId/candidate", recruitingHandler.AddCandidate())
+		v1Group.GET("/candidate/:candidateId", recruitingHandler.GetCandidate())
+		v1Group.DELETE("/candidate/:candidateId", recruitingHandler.DeleteCandidate())
+		v1Group.POST("/candidate/:candidateId/stage", recruitingHandler.MoveCandidate())
+		v1Group.POST("/candidate/:candidateId/note", recruitingHandler.AddCandidateNote())
+		v1Group.PUT("/candidate/:candidateId/cv", recruitingHandler.UploadCandidateCV())
+		v1Group.GET("/candidate/:candidateId/cv", recruitingHandler.DownloadCandidateCV())
+		v1Group.POST("/candidate/:candidateId/hire", recruitingHandler.HireCandidate())
+
+		// Review routes
+		v1Group.POST("/review-template", v1.CreateReviewTemplate)
+		v1Group.GET("/review-template", v1.GetReviewTemplates)
+		v1Group.POST("/review-cycle", employeeHandler.CreateReviewCycle())
+		v1Group.GET("/review-cycle", employeeHandler.GetReviewCycles())
+		v1Group.GET("/review-cycle/:cycleId/dashboard", employeeHandler.GetReviewDashboard())
+		v1Group.GET("/review-cycle/:cycleId/review", employeeHandler.GetCycleReviews())
+		v1Group.POST("/review-cycle/:cycleId/close", employeeHandler.CloseReviewCycle())
+		v1Group.GET("/review/:reviewId", employeeHandler.GetReview())
+		v1Group.POST("/review/:reviewId/self", employeeHandler.SubmitSelfAssessment())
+		v1Group.POST("/review/:reviewId/manager", employeeHandler.SubmitManagerAssessment())
+		v1Group.POST("/review/:reviewId/finalize", employeeHandler.FinalizeReview())
+
+		// Attendance routes
+		v1Group.GET("/work-schedule", v1.GetWorkSchedule)
+		v1Group.PUT("/work-schedule", v1.UpdateWorkSchedule)
+		v1Group.GET("/attendance/missing-punches", employeeHandler.GetMissingPunches())
+		v1Group.GET("/attendance/export", employeeHandler.ExportTimesheets())
 
 		v1Group.POST("/file", v1FileHandler.UploadFile)
 		// v1Group.POST("/file", func(c *gin.Context) {
@@ -1,10 +1,15 @@
 package main
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gavv/httpexpect/v2"
+	"github.com/xuri/excelize/v2"
 )
 
 const PORT = "http://10.0.7.99"
@@ -40,6 +45,41 @@ func TestLoginAPI(t *testing.T) {
 		ContainsKey("token")
 }
 
+// otherTenantToken signs up a second account, if needed, and returns its token
+// for checking that one tenant cannot reach another's data.
+func otherTenantToken(e *httpexpect.Expect) string {
+	credentials := map[string]string{
+		"email":    "other-tenant@test.com",
+		"password": "password",
+		"action":   "create",
+	}
+	e.POST("/api/v1/auth").WithJSON(credentials).Expect()
+
+	credentials["action"] = "login"
+	return e.POST("/api/v1/auth").
+		WithJSON(credentials).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("token").String().Raw()
+}
+
+// selfToken invites the employee to self-service, accepts the invitation and
+// returns the employee's token.
+func selfToken(e *httpexpect.Expect, identityNumber string) string {
+	inviteToken := e.POST("/api/v1/employee/{identityNumber}/account", identityNumber).
+		WithHeader("Authorization", "Bearer "+TOKEN).
+		WithJSON(map[string]interface{}{"email": strings.ToLower(identityNumber) + "@test.com"}).
+		Expect().
+		Status(201).
+		JSON().Object().Value("inviteToken").String().Raw()
+	return e.POST("/api/v1/self/activate").
+		WithJSON(map[string]interface{}{"inviteToken": inviteToken, "password": "password123"}).
+		Expect().
+		Status(200).
+		JSON().Object().Value("token").String().Raw()
+}
+
 func TestUserAPI(t *testing.T) {
 	const USERID = 1
 
@@ -63,6 +103,28 @@ func TestUserAPI(t *testing.T) {
 			JSON().Object().ContainsMap(updatedUser)
 	})
 
+	// Test PATCH /api/v1/user with a merge patch
+	t.Run("Partially update a user", func(t *testing.T) {
+		e.PATCH("/api/v1/user").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithHeader("Content-Type", "application/merge-patch+json").
+			WithBytes([]byte(`{"companyName": "Google LLC"}`)).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ContainsMap(map[string]interface{}{"name": "Test User", "companyName": "Google LLC"})
+
+		// Every field is still required, so a null cannot clear one
+		for _, field := range []string{"userImageUri", "companyImageUri", "name"} {
+			e.PATCH("/api/v1/user").
+				WithHeader("Authorization", "Bearer "+TOKEN).
+				WithHeader("Content-Type", "application/merge-patch+json").
+				WithBytes([]byte(`{"` + field + `": null}`)).
+				Expect().
+				Status(400)
+		}
+	})
+
 	// Test GET /api/v1/user
 	t.Run("Get all user", func(t *testing.T) {
 		e.GET("/api/v1/user").
@@ -73,6 +135,103 @@ func TestUserAPI(t *testing.T) {
 	})
 }
 
+func TestMemberAPI(t *testing.T) {
+	e := httpexpect.New(t, PORT)
+
+	login := func(email string) *httpexpect.Response {
+		return e.POST("/api/v1/auth").
+			WithJSON(map[string]interface{}{"email": email, "password": "password123", "action": "login"}).
+			Expect()
+	}
+
+	// Test the role gates with an account added to the tenant
+	t.Run("Add a member with a role", func(t *testing.T) {
+		const EMAIL = "manager-member@test.com"
+
+		owner := e.GET("/api/v1/member").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array().Value(0).Object()
+		owner.Value("owner").IsEqual(true)
+		owner.Value("role").IsEqual("admin")
+
+		member := e.POST("/api/v1/member").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"email": EMAIL, "password": "password123", "role": "manager"}).
+			Expect().
+			Status(201).
+			JSON().Object()
+		member.ContainsMap(map[string]interface{}{"email": EMAIL, "role": "manager", "owner": false})
+		memberID := member.Value("id").Raw()
+
+		e.POST("/api/v1/member").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"email": EMAIL, "password": "password123", "role": "hr"}).
+			Expect().
+			Status(409)
+
+		managerToken := login(EMAIL).Status(200).JSON().Object().Value("token").String().Raw()
+
+		// The member works in the owner's tenant
+		departments := e.GET("/api/v1/department").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array().Length().Raw()
+		e.GET("/api/v1/department").
+			WithHeader("Authorization", "Bearer "+managerToken).
+			Expect().
+			Status(200).
+			JSON().Array().Length().IsEqual(departments)
+
+		// but only admins manage members and the identity scheme
+		e.GET("/api/v1/member").
+			WithHeader("Authorization", "Bearer "+managerToken).
+			Expect().
+			Status(403)
+		e.PUT("/api/v1/identity-scheme").
+			WithHeader("Authorization", "Bearer "+managerToken).
+			WithJSON(map[string]interface{}{"scheme": "none"}).
+			Expect().
+			Status(403)
+
+		e.PATCH("/api/v1/member/{memberId}", memberID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"role": "admin"}).
+			Expect().
+			Status(200).
+			JSON().Object().Value("role").IsEqual("admin")
+		e.GET("/api/v1/member").
+			WithHeader("Authorization", "Bearer "+managerToken).
+			Expect().
+			Status(200)
+
+		// The owner always stays an admin
+		e.PATCH("/api/v1/member/{memberId}", owner.Value("id").Raw()).
+			WithHeader("Authorization", "Bearer "+managerToken).
+			WithJSON(map[string]interface{}{"role": "hr"}).
+			Expect().
+			Status(404)
+		e.DELETE("/api/v1/member/{memberId}", owner.Value("id").Raw()).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(404)
+
+		// Other tenants cannot reach the member
+		e.DELETE("/api/v1/member/{memberId}", memberID).
+			WithHeader("Authorization", "Bearer "+otherTenantToken(e)).
+			Expect().
+			Status(404)
+
+		e.DELETE("/api/v1/member/{memberId}", memberID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200)
+		login(EMAIL).Status(404)
+	})
+}
+
 func TestDepartmentAPI(t *testing.T) {
 	const DEPARTMENT_ID = 1
 	e := httpexpect.New(t, PORT)
@@ -128,6 +287,74 @@ func TestDepartmentAPI(t *testing.T) {
 			JSON().Object().ContainsMap(updatedDepartment)
 	})
 
+	// Test GET /api/v1/department?asOf=
+	t.Run("Page departments as of a past moment", func(t *testing.T) {
+		prefix := fmt.Sprintf("Snapshot %d", time.Now().Unix())
+		ids := []interface{}{}
+		for _, suffix := range []string{"A", "B", "C"} {
+			ids = append(ids, e.POST("/api/v1/department").
+				WithHeader("Authorization", "Bearer "+TOKEN).
+				WithJSON(map[string]interface{}{"name": prefix + " " + suffix}).
+				Expect().
+				Status(201).
+				JSON().Object().Value("departmentId").Raw())
+		}
+
+		// Department C is deleted after the moment asked about
+		time.Sleep(2 * time.Second)
+		asOf := time.Now().Add(-500 * time.Millisecond).Format(time.RFC3339)
+		e.DELETE("/api/v1/department/{id}", ids[2]).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200)
+
+		// The snapshot is sorted, counted and paged like the live list
+		first := e.GET("/api/v1/department").
+			WithQuery("asOf", asOf).
+			WithQuery("name", prefix).
+			WithQuery("sort", "-name").
+			WithQuery("limit", 2).
+			WithQuery("withTotal", "true").
+			WithQuery("envelope", "true").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200)
+		first.Header("X-Total-Count").IsEqual("3")
+		first.Header("Link").Contains(`rel="next"`)
+		envelope := first.JSON().Object()
+		envelope.Value("data").Array().Value(0).Object().Value("name").IsEqual(prefix + " C")
+		envelope.Value("data").Array().Value(1).Object().Value("name").IsEqual(prefix + " B")
+
+		second := e.GET("/api/v1/department").
+			WithQuery("asOf", asOf).
+			WithQuery("name", prefix).
+			WithQuery("cursor", envelope.Value("next").String().Raw()).
+			WithQuery("limit", 2).
+			WithQuery("envelope", "true").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Object()
+		second.Value("data").Array().Length().IsEqual(1)
+		second.Value("data").Array().Value(0).Object().Value("name").IsEqual(prefix + " A")
+		second.Value("next").IsNull()
+
+		// Today C is gone
+		e.GET("/api/v1/department").
+			WithQuery("name", prefix).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array().Length().IsEqual(2)
+
+		for _, id := range ids[:2] {
+			e.DELETE("/api/v1/department/{id}", id).
+				WithHeader("Authorization", "Bearer "+TOKEN).
+				Expect().
+				Status(200)
+		}
+	})
+
 	// Test DELETE /api/v1/department/{id}
 	t.Run("Delete a department", func(t *testing.T) {
 		departmentID := DEPARTMENT_ID
@@ -139,114 +366,2262 @@ func TestDepartmentAPI(t *testing.T) {
 	})
 }
 
-func TestEmployeeAPI(t *testing.T) {
-	const EMPLOYEE_ID = "XX12345"
-
+func TestIdentitySchemeAPI(t *testing.T) {
 	e := httpexpect.New(t, PORT)
 
-	getOrCreateDepartmentID := func() string {
-		// Step 1: Try to get the list of departments
-		departments := e.GET("/api/v1/department").
+	// Test GET /api/v1/identity-scheme
+	t.Run("Get the identity scheme", func(t *testing.T) {
+		obj := e.GET("/api/v1/identity-scheme").
 			WithHeader("Authorization", "Bearer "+TOKEN).
 			Expect().
 			Status(200).
-			JSON().Array()
+			JSON().Object()
+		obj.Value("available").Array().ContainsAll("none", "nik")
+	})
 
-		// Step 2: If departments exist, return the first department's ID
-		if departments.Length().Raw() > 0 {
-			firstDepartment := departments.First().Object()
-			departmentID := firstDepartment.Value("departmentId").Raw()
+	// Test PUT /api/v1/identity-scheme
+	t.Run("Reject an unknown identity scheme", func(t *testing.T) {
+		e.PUT("/api/v1/identity-scheme").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"scheme": "unknown"}).
+			Expect().
+			Status(400)
+	})
 
-			// Handle the case where departmentId is a number or a string
-			switch v := departmentID.(type) {
-			case float64: // JSON numbers are unmarshalled as float64 by default
-				return fmt.Sprintf("%d", int(v)) // Convert to string
-			case int:
-				return fmt.Sprintf("%d", v) // Convert to string
-			case string:
-				return v // Already a string
-			default:
-				panic(fmt.Sprintf("unexpected type for departmentId: %T", v))
+	// The NIK scheme is tried on a second tenant so other tests keep free-form
+	// identity numbers
+	t.Run("Decode NIKs", func(t *testing.T) {
+		otherToken := otherTenantToken(e)
+
+		e.PUT("/api/v1/identity-scheme").
+			WithHeader("Authorization", "Bearer "+otherToken).
+			WithJSON(map[string]interface{}{"scheme": "nik"}).
+			Expect().
+			Status(200)
+
+		departmentID := fmt.Sprint(e.POST("/api/v1/department").
+			WithHeader("Authorization", "Bearer "+otherToken).
+			WithJSON(map[string]interface{}{"name": "NIK Department"}).
+			Expect().
+			Status(201).
+			JSON().Object().Value("departmentId").Raw())
+
+		cases := []struct {
+			nik     string
+			gender  string
+			details string // Empty when the NIK is valid
+		}{
+			{"3171011505900001", "male", ""},
+			{"3171015505900002", "female", ""}, // 40 is added to the day for women
+			{"3171012902000003", "male", ""},   // 29 February in a two-digit leap year
+			{"3171015505900004", "male", "encodes a female birth date"},
+			{"3171011505900005", "female", "encodes a male birth date"},
+			{"317101150590001", "male", "must be 16 digits"},
+			{"31710115059000A1", "male", "must be 16 digits"},
+			{"9971011505900001", "male", "province code 99"},
+			{"3100011505900001", "male", "cannot be 00"},
+			{"3171003105900001", "male", "cannot be 00"},
+			{"3171013102900001", "male", "valid birth date"},
+			{"3171012902010001", "male", "valid birth date"},
+			{"3171017113900001", "female", "valid birth date"},
+			{"3171011505900000", "male", "serial number cannot be 0000"},
+		}
+
+		for _, tc := range cases {
+			response := e.POST("/api/v1/employee").
+				WithHeader("Authorization", "Bearer "+otherToken).
+				WithJSON(map[string]interface{}{
+					"identityNumber":   tc.nik,
+					"name":             "NIK Tester",
+					"employeeImageUri": "http://example.com/image.png",
+					"gender":           tc.gender,
+					"departmentId":     departmentID,
+				}).
+				Expect()
+
+			if tc.details == "" {
+				response.Status(201)
+				e.DELETE("/api/v1/employee/{identityNumber}", tc.nik).
+					WithHeader("Authorization", "Bearer "+otherToken).
+					Expect().
+					Status(200)
+				continue
 			}
+			response.Status(400).JSON().Object().Value("details").String().Contains(tc.details)
 		}
 
-		// Step 3: If no departments exist, create a new department
-		newDepartment := map[string]interface{}{
-			"name": "Default Department",
+		e.DELETE("/api/v1/department/{departmentId}", departmentID).
+			WithHeader("Authorization", "Bearer "+otherToken).
+			Expect().
+			Status(200)
+		e.PUT("/api/v1/identity-scheme").
+			WithHeader("Authorization", "Bearer "+otherToken).
+			WithJSON(map[string]interface{}{"scheme": "none"}).
+			Expect().
+			Status(200)
+	})
+}
+
+func TestCustomFieldAPI(t *testing.T) {
+	e := httpexpect.New(t, PORT)
+
+	// Test POST /api/v1/custom-field
+	t.Run("Create a custom field", func(t *testing.T) {
+		field := map[string]interface{}{
+			"key":     "shirtSize",
+			"label":   "Shirt size",
+			"type":    "enum",
+			"options": []string{"S", "M", "L", "XL"},
 		}
 
-		// Create the new department and get the response
-		createdDepartment := e.POST("/api/v1/department").
+		e.POST("/api/v1/custom-field").
 			WithHeader("Authorization", "Bearer "+TOKEN).
-			WithJSON(newDepartment).
+			WithJSON(field).
 			Expect().
 			Status(201).
-			JSON().Object()
-
-		// Step 4: Handle the case where departmentId is a number or a string
-		departmentID := createdDepartment.Value("departmentId").Raw()
-		switch v := departmentID.(type) {
-		case float64: // JSON numbers are unmarshalled as float64 by default
-			return fmt.Sprintf("%d", int(v)) // Convert to string
-		case int:
-			return fmt.Sprintf("%d", v) // Convert to string
-		case string:
-			return v // Already a string
-		default:
-			panic(fmt.Sprintf("unexpected type for departmentId: %T", v))
-		}
-	}
+			JSON().Object().ContainsMap(field)
+	})
 
-	// Get or create a department ID
-	DEPARTMENT_ID := getOrCreateDepartmentID()
+	t.Run("Reject an enum without options", func(t *testing.T) {
+		e.POST("/api/v1/custom-field").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{
+				"key":   "unionMember",
+				"label": "Union member",
+				"type":  "enum",
+			}).
+			Expect().
+			Status(400)
+	})
 
-	// Test POST /api/v1/emplyee
-	t.Run("Create a new employee", func(t *testing.T) {
-		employee := map[string]interface{}{
-			"identityNumber":   EMPLOYEE_ID,
-			"name":             "Bob Smith",
-			"employeeImageUri": "1234",
-			"gender":           "male",
-			"departmentId":     DEPARTMENT_ID,
-		}
+	// A field made required later is only enforced on patches that change
+	// the employee's custom fields
+	t.Run("Patch an employee after a field becomes required", func(t *testing.T) {
+		const EMPLOYEE_ID = "XX35001"
+		key := fmt.Sprintf("badge%d", time.Now().Unix())
 
-		e.POST("/api/v1/employee").
+		departmentID := fmt.Sprint(e.POST("/api/v1/department").
 			WithHeader("Authorization", "Bearer "+TOKEN).
-			WithJSON(employee).
+			WithJSON(map[string]interface{}{"name": "Custom Field Department"}).
 			Expect().
 			Status(201).
+			JSON().Object().Value("departmentId").Raw())
+		e.POST("/api/v1/employee").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{
+				"identityNumber":   EMPLOYEE_ID,
+				"name":             "Custom Field Tester",
+				"employeeImageUri": "http://example.com/image.png",
+				"gender":           "female",
+				"departmentId":     departmentID,
+				"customFields":     map[string]interface{}{"shirtSize": "M"},
+			}).
+			Expect().
+			Status(201)
+
+		e.POST("/api/v1/custom-field").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"key": key, "label": "Badge", "type": "string"}).
+			Expect().
+			Status(201)
+		e.PATCH("/api/v1/custom-field/{key}", key).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"required": true}).
+			Expect().
+			Status(200)
+
+		e.PATCH("/api/v1/employee/{id}", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"name": "Renamed Custom Field Tester"}).
+			Expect().
+			Status(200).
 			JSON().Object().
-			ContainsMap(employee)
+			ContainsMap(map[string]interface{}{"name": "Renamed Custom Field Tester", "customFields": map[string]interface{}{"shirtSize": "M"}})
+
+		e.PATCH("/api/v1/employee/{id}", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"customFields": map[string]interface{}{"shirtSize": "L"}}).
+			Expect().
+			Status(400)
+
+		e.PATCH("/api/v1/employee/{id}", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"customFields": map[string]interface{}{"shirtSize": "L", key: "Blue"}}).
+			Expect().
+			Status(200)
+
+		e.DELETE("/api/v1/custom-field/{key}", key).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200)
+		e.DELETE("/api/v1/employee/{id}", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200)
+		e.DELETE("/api/v1/department/{departmentId}", departmentID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200)
 	})
 
-	t.Run("Get all employees", func(t *testing.T) {
-		e.GET("/api/v1/employee").
+	// Test DELETE /api/v1/custom-field/{key}
+	t.Run("Delete a custom field", func(t *testing.T) {
+		e.DELETE("/api/v1/custom-field/{key}", "shirtSize").
 			WithHeader("Authorization", "Bearer "+TOKEN).
 			Expect().
-			Status(200).
-			JSON().Array().NotEmpty()
+			Status(200)
 	})
+}
 
-	// Test PATCH /api/v1/employee/{id}
-	t.Run("Update a employee", func(t *testing.T) {
-		updatedEmployee := map[string]interface{}{
-			"identityNumber":   EMPLOYEE_ID,
-			"name":             "Updated Bob Smith",
-			"employeeImageUri": "1234",
-			"gender":           "male",
-			"departmentId":     DEPARTMENT_ID,
+func TestLeaveAPI(t *testing.T) {
+	e := httpexpect.New(t, PORT)
+
+	// Test POST /api/v1/leave-type
+	t.Run("Create a leave type", func(t *testing.T) {
+		leaveType := map[string]interface{}{
+			"name":         "Annual",
+			"paid":         true,
+			"accrual":      "monthly",
+			"daysPerYear":  12,
+			"maxCarryOver": 5,
 		}
 
-		e.PATCH("/api/v1/employee/{id}", EMPLOYEE_ID).
+		e.POST("/api/v1/leave-type").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(leaveType).
+			Expect().
+			Status(201).
+			JSON().Object().ContainsMap(leaveType)
+	})
+
+	// Test GET /api/v1/leave/calendar
+	t.Run("Get the leave calendar", func(t *testing.T) {
+		e.GET("/api/v1/leave/calendar").
+			WithQuery("from", "2026-01-01").
+			WithQuery("to", "2026-01-31").
 			WithHeader("Authorization", "Bearer "+TOKEN).
-			WithJSON(updatedEmployee).
 			Expect().
 			Status(200).
-			JSON().Object().ContainsMap(updatedEmployee)
+			JSON().Object().
+			ContainsMap(map[string]interface{}{"from": "2026-01-01", "to": "2026-01-31"}).
+			ContainsKey("entries")
 	})
 
-	// Test DELETE /api/v1/employee/{id}
-	t.Run("Delete a employee", func(t *testing.T) {
+	// Test the balance, overlap and approval rules end to end
+	t.Run("Request and decide leave", func(t *testing.T) {
+		const HEAD_ID = "XX36001"
+		const MEMBER_ID = "XX36002"
+
+		departmentID := fmt.Sprint(e.POST("/api/v1/department").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"name": "Leave Department"}).
+			Expect().
+			Status(201).
+			JSON().Object().Value("departmentId").Raw())
+
+		for _, identityNumber := range []string{HEAD_ID, MEMBER_ID} {
+			e.POST("/api/v1/employee").
+				WithHeader("Authorization", "Bearer "+TOKEN).
+				WithJSON(map[string]interface{}{
+					"identityNumber":   identityNumber,
+					"name":             "Leave Tester",
+					"employeeImageUri": "http://example.com/image.png",
+					"gender":           "female",
+					"departmentId":     departmentID,
+				}).
+				Expect().
+				Status(201)
+		}
+		e.PUT("/api/v1/department/{departmentId}/head", departmentID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"identityNumber": HEAD_ID}).
+			Expect().
+			Status(200)
+
+		leaveTypeID := e.POST("/api/v1/leave-type").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"name": "Leave Test", "paid": true, "accrual": "yearly", "daysPerYear": 10}).
+			Expect().
+			Status(201).
+			JSON().Object().Value("id").Raw()
+
+		balance := func(identityNumber string, year string) *httpexpect.Object {
+			balances := e.GET("/api/v1/employee/{identityNumber}/leave/balance", identityNumber).
+				WithQuery("year", year).
+				WithHeader("Authorization", "Bearer "+TOKEN).
+				Expect().
+				Status(200).
+				JSON().Array()
+			for _, b := range balances.Iter() {
+				if b.Object().Value("leaveTypeId").Raw() == leaveTypeID {
+					return b.Object()
+				}
+			}
+			t.Fatalf("no balance for leave type %v", leaveTypeID)
+			return nil
+		}
+		requestLeave := func(identityNumber string, startDate string, endDate string) *httpexpect.Response {
+			return e.POST("/api/v1/employee/{identityNumber}/leave", identityNumber).
+				WithHeader("Authorization", "Bearer "+TOKEN).
+				WithJSON(map[string]interface{}{"leaveTypeId": leaveTypeID, "startDate": startDate, "endDate": endDate}).
+				Expect()
+		}
+
+		// Monday 30 December 2030 to Thursday 2 January 2031 is two working
+		// days in each year
+		leaveID := requestLeave(MEMBER_ID, "2030-12-30", "2031-01-02").
+			Status(201).
+			JSON().Object().
+			ContainsMap(map[string]interface{}{"days": 4, "status": "pending"}).
+			Value("id").Raw()
+		balance(MEMBER_ID, "2030").ContainsMap(map[string]interface{}{"pending": 2, "available": 8})
+		balance(MEMBER_ID, "2031").ContainsMap(map[string]interface{}{"pending": 2, "available": 8})
+
+		requestLeave(MEMBER_ID, "2031-01-02", "2031-01-03").Status(409)
+		requestLeave(MEMBER_ID, "2031-02-03", "2031-02-14").Status(422)
+		requestLeave(MEMBER_ID, "2031-01-04", "2031-01-05").Status(400)
+
+		// Only the head of the employee's department decides, and never on
+		// their own leave
+		headToken := selfToken(e, HEAD_ID)
+		memberToken := selfToken(e, MEMBER_ID)
+		headLeaveID := requestLeave(HEAD_ID, "2031-03-03", "2031-03-03").
+			Status(201).
+			JSON().Object().Value("id").Raw()
+
+		e.POST("/api/v1/self/leave/{leaveId}/approve", leaveID).
+			WithHeader("Authorization", "Bearer "+memberToken).
+			Expect().
+			Status(403)
+		e.POST("/api/v1/self/leave/{leaveId}/approve", headLeaveID).
+			WithHeader("Authorization", "Bearer "+headToken).
+			Expect().
+			Status(403)
+		e.POST("/api/v1/self/leave/{leaveId}/approve", leaveID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(401)
+
+		// Other members of the tenant cannot decide through the admin routes
+		hrEmail := fmt.Sprintf("leave-hr-%d@test.com", time.Now().UnixNano())
+		hrID := e.POST("/api/v1/member").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"email": hrEmail, "password": "password123", "role": "hr"}).
+			Expect().
+			Status(201).
+			JSON().Object().Value("id").Raw()
+		hrToken := e.POST("/api/v1/auth").
+			WithJSON(map[string]interface{}{"email": hrEmail, "password": "password123", "action": "login"}).
+			Expect().
+			Status(200).
+			JSON().Object().Value("token").String().Raw()
+		for _, decision := range []string{"approve", "reject", "cancel"} {
+			e.POST("/api/v1/leave/{leaveId}/"+decision, leaveID).
+				WithHeader("Authorization", "Bearer "+hrToken).
+				Expect().
+				Status(403)
+		}
+		e.DELETE("/api/v1/member/{memberId}", hrID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200)
+		e.POST("/api/v1/self/leave/{leaveId}/approve", leaveID).
+			WithHeader("Authorization", "Bearer "+headToken).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ContainsMap(map[string]interface{}{"status": "approved", "decidedBy": HEAD_ID})
+		balance(MEMBER_ID, "2030").ContainsMap(map[string]interface{}{"taken": 2, "pending": 0})
+		balance(MEMBER_ID, "2031").ContainsMap(map[string]interface{}{"taken": 2, "pending": 0})
+
+		e.POST("/api/v1/leave/{leaveId}/reject", leaveID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(409)
+		e.POST("/api/v1/leave/{leaveId}/cancel", leaveID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Object().Value("status").IsEqual("cancelled")
+		balance(MEMBER_ID, "2031").ContainsMap(map[string]interface{}{"taken": 0, "available": 10})
+
+		// A cancelled request no longer blocks the days it covered
+		requestLeave(MEMBER_ID, "2031-01-02", "2031-01-03").Status(201)
+
+		for _, identityNumber := range []string{HEAD_ID, MEMBER_ID} {
+			e.DELETE("/api/v1/employee/{identityNumber}", identityNumber).
+				WithHeader("Authorization", "Bearer "+TOKEN).
+				Expect().
+				Status(200)
+		}
+		e.DELETE("/api/v1/department/{departmentId}", departmentID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200)
+	})
+}
+
+func TestAttendanceAPI(t *testing.T) {
+	e := httpexpect.New(t, PORT)
+
+	// Test PUT /api/v1/work-schedule
+	t.Run("Update the work schedule", func(t *testing.T) {
+		schedule := map[string]interface{}{
+			"dailyHours": 8,
+			"workDays":   []int{1, 2, 3, 4, 5},
+			"timezone":   "Asia/Jakarta",
+		}
+
+		e.PUT("/api/v1/work-schedule").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(schedule).
+			Expect().
+			Status(200).
+			JSON().Object().ContainsMap(schedule)
+	})
+
+	// Test GET /api/v1/attendance/missing-punches
+	t.Run("Get missing punches", func(t *testing.T) {
+		e.GET("/api/v1/attendance/missing-punches").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array()
+	})
+
+	// Punches, corrections and approval over the week of Monday 5 January 2026,
+	// in the Asia/Jakarta schedule set above
+	t.Run("Pair punches into a timesheet", func(t *testing.T) {
+		const EMPLOYEE_ID = "XX37001"
+
+		departmentID := fmt.Sprint(e.POST("/api/v1/department").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"name": "Attendance Department"}).
+			Expect().
+			Status(201).
+			JSON().Object().Value("departmentId").Raw())
+		e.POST("/api/v1/employee").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{
+				"identityNumber":   EMPLOYEE_ID,
+				"name":             "Attendance Tester",
+				"employeeImageUri": "http://example.com/image.png",
+				"gender":           "male",
+				"departmentId":     departmentID,
+			}).
+			Expect().
+			Status(201)
+
+		punch := func(eventType string, at string) *httpexpect.Response {
+			return e.POST("/api/v1/employee/{identityNumber}/clock-"+eventType, EMPLOYEE_ID).
+				WithHeader("Authorization", "Bearer "+TOKEN).
+				WithJSON(map[string]interface{}{"at": at}).
+				Expect()
+		}
+
+		punch("in", "2026-01-05T09:00:00+07:00").Status(201)
+		punch("in", "2026-01-06T09:00:00+07:00").Status(409)
+		// The forgotten clock-out is added afterwards
+		punch("out", "2026-01-05T18:30:00+07:00").Status(201)
+		punch("in", "2026-01-06T09:00:00+07:00").Status(201)
+		punch("out", "2026-01-06T17:00:00+07:00").Status(201)
+		// A night shift counts towards the day it started on
+		punch("in", "2026-01-07T22:00:00+07:00").Status(201)
+		punch("out", "2026-01-08T06:00:00+07:00").Status(201)
+
+		// Corrections must alternate with the punches around them
+		punch("in", "2026-01-07T08:00:00+07:00").Status(409)
+		punch("out", "2026-01-04T10:00:00+07:00").Status(409)
+
+		strayID := punch("in", "2026-01-09T09:00:00+07:00").
+			Status(201).
+			JSON().Object().Value("id").Raw()
+
+		approval := map[string]interface{}{"from": "2026-01-05", "to": "2026-01-11"}
+		e.POST("/api/v1/employee/{identityNumber}/timesheet/approve", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(approval).
+			Expect().
+			Status(409).
+			JSON().Object().Value("timesheet").Object().Value("missingPunches").IsEqual(1)
+
+		e.DELETE("/api/v1/employee/{identityNumber}/attendance/{eventId}", EMPLOYEE_ID, strayID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200)
+
+		sheet := e.GET("/api/v1/employee/{identityNumber}/timesheet", EMPLOYEE_ID).
+			WithQuery("from", "2026-01-05").
+			WithQuery("to", "2026-01-11").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Object()
+		sheet.ContainsMap(map[string]interface{}{"workedHours": 25.5, "overtimeHours": 1.5, "missingPunches": 0})
+		days := sheet.Value("days").Array()
+		days.Length().IsEqual(7)
+		days.Value(0).Object().ContainsMap(map[string]interface{}{"date": "2026-01-05", "workedHours": 9.5, "overtimeHours": 1.5})
+		days.Value(1).Object().ContainsMap(map[string]interface{}{"date": "2026-01-06", "workedHours": 8})
+		days.Value(2).Object().ContainsMap(map[string]interface{}{"date": "2026-01-07", "workedHours": 8})
+		days.Value(3).Object().ContainsMap(map[string]interface{}{"date": "2026-01-08", "workedHours": 0})
+		sheet.Value("weeks").Array().Value(0).Object().
+			ContainsMap(map[string]interface{}{"weekStart": "2026-01-05", "expectedHours": 40})
+
+		e.POST("/api/v1/employee/{identityNumber}/timesheet/approve", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(approval).
+			Expect().
+			Status(201).
+			JSON().Object().ContainsMap(map[string]interface{}{"workedHours": 25.5, "overtimeHours": 1.5})
+
+		// The approved week is frozen
+		punch("in", "2026-01-08T09:00:00+07:00").Status(409)
+		e.POST("/api/v1/employee/{identityNumber}/timesheet/approve", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"from": "2098-12-28", "to": "2099-01-01"}).
+			Expect().
+			Status(400)
+
+		e.DELETE("/api/v1/employee/{identityNumber}", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200)
+		e.DELETE("/api/v1/department/{departmentId}", departmentID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200)
+	})
+}
+
+func TestDocumentAPI(t *testing.T) {
+	e := httpexpect.New(t, PORT)
+
+	// Test GET /api/v1/document/expiring
+	t.Run("Get expiring documents", func(t *testing.T) {
+		e.GET("/api/v1/document/expiring").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithQuery("days", 60).
+			Expect().
+			Status(200).
+			JSON().Object().ContainsKey("documents")
+	})
+
+	t.Run("Reject an invalid window", func(t *testing.T) {
+		e.GET("/api/v1/document/expiring").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithQuery("days", -1).
+			Expect().
+			Status(400)
+	})
+
+	// Members of the tenant without the admin role are kept out
+	t.Run("Limit documents to admins", func(t *testing.T) {
+		email := fmt.Sprintf("document-hr-%d@test.com", time.Now().UnixNano())
+		memberID := e.POST("/api/v1/member").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"email": email, "password": "password123", "role": "hr"}).
+			Expect().
+			Status(201).
+			JSON().Object().Value("id").Raw()
+		hrToken := e.POST("/api/v1/auth").
+			WithJSON(map[string]interface{}{"email": email, "password": "password123", "action": "login"}).
+			Expect().
+			Status(200).
+			JSON().Object().Value("token").String().Raw()
+
+		e.GET("/api/v1/document/expiring").
+			WithHeader("Authorization", "Bearer "+hrToken).
+			WithQuery("days", 60).
+			Expect().
+			Status(403)
+
+		e.DELETE("/api/v1/member/{memberId}", memberID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200)
+	})
+}
+
+func TestSkillAPI(t *testing.T) {
+	e := httpexpect.New(t, PORT)
+
+	var skillID int
+
+	// Test POST /api/v1/skill
+	t.Run("Create a skill", func(t *testing.T) {
+		skill := map[string]interface{}{
+			"name":     fmt.Sprintf("Go %d", time.Now().UnixNano()),
+			"category": "Engineering",
+		}
+
+		obj := e.POST("/api/v1/skill").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(skill).
+			Expect().
+			Status(201).
+			JSON().Object()
+		obj.ContainsMap(skill)
+		skillID = int(obj.Value("id").Number().Raw())
+	})
+
+	// Test GET /api/v1/skill/search
+	t.Run("Search employees by skill", func(t *testing.T) {
+		e.GET("/api/v1/skill/search").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithQuery("skill", fmt.Sprintf("%d:3", skillID)).
+			Expect().
+			Status(200).
+			JSON().Array()
+	})
+
+	t.Run("Reject an unknown skill", func(t *testing.T) {
+		e.GET("/api/v1/skill/search").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithQuery("skill", "0").
+			Expect().
+			Status(400)
+	})
+
+	// Test GET /api/v1/certification/lapsing
+	t.Run("Get lapsing certifications", func(t *testing.T) {
+		e.GET("/api/v1/certification/lapsing").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array()
+	})
+}
+
+func TestPayrollAPI(t *testing.T) {
+	e := httpexpect.New(t, PORT)
+
+	// Test GET /api/v1/payroll/cost
+	t.Run("Get payroll cost by department", func(t *testing.T) {
+		e.GET("/api/v1/payroll/cost").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Object().ContainsKey("departments")
+	})
+
+	t.Run("Reject an invalid status", func(t *testing.T) {
+		e.GET("/api/v1/payroll/cost").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithQuery("status", "unknown").
+			Expect().
+			Status(400)
+	})
+
+	// Test GET /api/v1/payroll/pay-gap
+	t.Run("Get gender pay gap", func(t *testing.T) {
+		obj := e.GET("/api/v1/payroll/pay-gap").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Object()
+
+		obj.Value("minGroupSize").Number().IsEqual(5)
+		obj.Value("groups").Array()
+	})
+
+	// A department of five men and five women next to one of a man and a
+	// woman: the small one is suppressed, and since the overall figures minus
+	// the large department would give its pay away, the large one is as well
+	t.Run("Suppress small pay gap groups", func(t *testing.T) {
+		suffix := fmt.Sprint(time.Now().Unix())
+		name := "Pay Gap " + suffix
+
+		newDepartment := func(department string) string {
+			return fmt.Sprint(e.POST("/api/v1/department").
+				WithHeader("Authorization", "Bearer "+TOKEN).
+				WithJSON(map[string]interface{}{"name": department + " " + suffix}).
+				Expect().
+				Status(201).
+				JSON().Object().Value("departmentId").Raw())
+		}
+		largeID := newDepartment("Pay Gap Large")
+		smallID := newDepartment("Pay Gap Small")
+
+		type payee struct {
+			departmentID string
+			gender       string
+			pay          int
+		}
+		staff := []payee{{smallID, "male", 70000}, {smallID, "female", 40000}}
+		for i := 0; i < 5; i++ {
+			staff = append(staff, payee{largeID, "male", 60000}, payee{largeID, "female", 50000})
+		}
+
+		identityNumbers := []string{}
+		for i, s := range staff {
+			identityNumber := fmt.Sprintf("PG%s%02d", suffix, i)
+			identityNumbers = append(identityNumbers, identityNumber)
+			e.POST("/api/v1/employee").
+				WithHeader("Authorization", "Bearer "+TOKEN).
+				WithJSON(map[string]interface{}{
+					"identityNumber":   identityNumber,
+					"name":             name,
+					"employeeImageUri": "http://example.com/image.png",
+					"gender":           s.gender,
+					"departmentId":     s.departmentID,
+				}).
+				Expect().
+				Status(201)
+			e.POST("/api/v1/employee/{identityNumber}/compensation", identityNumber).
+				WithHeader("Authorization", "Bearer "+TOKEN).
+				WithJSON(map[string]interface{}{
+					"effectiveDate": "2025-01-01",
+					"baseSalary":    s.pay,
+					"currency":      "ISK",
+					"payFrequency":  "annual",
+				}).
+				Expect().
+				Status(201)
+		}
+
+		groups := e.GET("/api/v1/payroll/pay-gap").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithQuery("name", name).
+			Expect().
+			Status(200).
+			JSON().Object().Value("groups").Array()
+
+		// Overall, the two departments and the four quartiles
+		groups.Length().IsEqual(7)
+		groups.Value(0).Object().ContainsMap(map[string]interface{}{
+			"currency":    "ISK",
+			"scope":       "overall",
+			"maleCount":   6,
+			"femaleCount": 6,
+			"suppressed":  false,
+			"maleMean":    61666.67,
+			"femaleMean":  48333.33,
+			"meanGap":     21.62,
+		})
+
+		large := groups.Value(1).Object()
+		large.ContainsMap(map[string]interface{}{
+			"scope": "department", "departmentId": largeID, "maleCount": 5, "femaleCount": 5, "suppressed": true,
+		})
+		large.Value("maleMean").IsNull()
+		large.Value("meanGap").IsNull()
+		large.Value("medianGap").IsNull()
+
+		small := groups.Value(2).Object()
+		small.ContainsMap(map[string]interface{}{
+			"scope": "department", "departmentId": smallID, "maleCount": 1, "femaleCount": 1, "suppressed": true,
+		})
+		small.Value("maleMean").IsNull()
+
+		// Three employees a quartile are too few to report
+		for q := 3; q < 7; q++ {
+			groups.Value(q).Object().ContainsMap(map[string]interface{}{"scope": "quartile", "suppressed": true})
+			groups.Value(q).Object().NotContainsKey("femaleShare")
+		}
+
+		for _, identityNumber := range identityNumbers {
+			e.DELETE("/api/v1/employee/{identityNumber}", identityNumber).
+				WithHeader("Authorization", "Bearer "+TOKEN).
+				Expect().
+				Status(200)
+		}
+		for _, departmentID := range []string{largeID, smallID} {
+			e.DELETE("/api/v1/department/{departmentId}", departmentID).
+				WithHeader("Authorization", "Bearer "+TOKEN).
+				Expect().
+				Status(200)
+		}
+	})
+
+	// Test GET /api/v1/payroll/pay-gap/export
+	t.Run("Export gender pay gap as CSV", func(t *testing.T) {
+		e.GET("/api/v1/payroll/pay-gap/export").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithQuery("format", "csv").
+			Expect().
+			Status(200).
+			Header("Content-Type").HasPrefix("text/csv")
+	})
+
+	t.Run("Reject an unknown export format", func(t *testing.T) {
+		e.GET("/api/v1/payroll/pay-gap/export").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithQuery("format", "pdf").
+			Expect().
+			Status(400)
+	})
+}
+
+func TestPersonalInfoAPI(t *testing.T) {
+	e := httpexpect.New(t, PORT)
+
+	// Test PUT /api/v1/employee/:identityNumber/personal-info
+	t.Run("Reject an invalid emergency contact phone", func(t *testing.T) {
+		info := map[string]interface{}{
+			"emergencyContacts": []map[string]interface{}{
+				{"name": "Jane Doe", "relationship": "spouse", "phone": "not a phone"},
+			},
+		}
+
+		e.PUT("/api/v1/employee/1234567890/personal-info").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(info).
+			Expect().
+			Status(400)
+	})
+
+	// Test GET /api/v1/personal-info/access-log
+	t.Run("Get the personal information access log", func(t *testing.T) {
+		e.GET("/api/v1/personal-info/access-log").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithQuery("action", "read").
+			Expect().
+			Status(200).
+			JSON().Array()
+	})
+}
+
+func TestSelfServiceAPI(t *testing.T) {
+	e := httpexpect.New(t, PORT)
+
+	// Test POST /api/v1/self/activate
+	t.Run("Reject an unknown invite token", func(t *testing.T) {
+		e.POST("/api/v1/self/activate").
+			WithJSON(map[string]interface{}{"inviteToken": "not-a-token", "password": "password123"}).
+			Expect().
+			Status(400)
+	})
+
+	// Test GET /api/v1/self
+	t.Run("Reject a manager token on self-service routes", func(t *testing.T) {
+		e.GET("/api/v1/self").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(401)
+	})
+}
+
+func TestAnalyticsAPI(t *testing.T) {
+	e := httpexpect.New(t, PORT)
+
+	// Test GET /api/v1/analytics/headcount
+	t.Run("Get headcount analytics", func(t *testing.T) {
+		obj := e.GET("/api/v1/analytics/headcount").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Object()
+		obj.Value("monthlyFlows").Array().Length().IsEqual(12)
+		obj.Value("tenure").Array().Length().IsEqual(5)
+	})
+
+	t.Run("Reject a range ending before it starts", func(t *testing.T) {
+		e.GET("/api/v1/analytics/headcount").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithQuery("from", "2026-06-01").
+			WithQuery("to", "2026-01-01").
+			Expect().
+			Status(400)
+	})
+
+	// Test GET /api/v1/analytics/turnover
+	t.Run("Get turnover analytics", func(t *testing.T) {
+		obj := e.GET("/api/v1/analytics/turnover").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithQuery("from", "2026-01-01").
+			WithQuery("to", "2026-03-31").
+			Expect().
+			Status(200).
+			JSON().Object()
+		obj.Value("overall").Array().Length().IsEqual(3)
+		obj.Value("genders").Array().Length().IsEqual(2)
+	})
+
+	// Four employees hired in January 2025; in March two men leave and one of
+	// them is deleted afterwards, which must not take the departure with it
+	t.Run("Work out turnover rates", func(t *testing.T) {
+		// Transitions outlive deleted employees, so every run uses new identity numbers
+		prefix := fmt.Sprintf("TO%d", time.Now().Unix())
+		staff := []struct {
+			identityNumber string
+			gender         string
+		}{
+			{prefix + "1", "male"},
+			{prefix + "2", "male"},
+			{prefix + "3", "female"},
+			{prefix + "4", "female"},
+		}
+
+		departmentID := fmt.Sprint(e.POST("/api/v1/department").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"name": "Turnover Department"}).
+			Expect().
+			Status(201).
+			JSON().Object().Value("departmentId").Raw())
+
+		transition := func(identityNumber string, body map[string]interface{}) {
+			e.POST("/api/v1/employee/{identityNumber}/status", identityNumber).
+				WithHeader("Authorization", "Bearer "+TOKEN).
+				WithJSON(body).
+				Expect().
+				Status(201)
+		}
+		for _, s := range staff {
+			e.POST("/api/v1/employee").
+				WithHeader("Authorization", "Bearer "+TOKEN).
+				WithJSON(map[string]interface{}{
+					"identityNumber":   s.identityNumber,
+					"name":             "Turnover Tester",
+					"employeeImageUri": "http://example.com/image.png",
+					"gender":           s.gender,
+					"departmentId":     departmentID,
+				}).
+				Expect().
+				Status(201)
+			transition(s.identityNumber, map[string]interface{}{"status": "active", "effectiveDate": "2025-01-01", "reason": "Hired"})
+		}
+		transition(staff[0].identityNumber, map[string]interface{}{
+			"status": "terminated", "effectiveDate": "2025-03-15", "reason": "Resigned", "terminationType": "voluntary", "regretted": true,
+		})
+		transition(staff[1].identityNumber, map[string]interface{}{
+			"status": "terminated", "effectiveDate": "2025-03-20", "reason": "Dismissed", "terminationType": "involuntary",
+		})
+		e.DELETE("/api/v1/employee/{identityNumber}", staff[1].identityNumber).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200)
+
+		// Another tenant hiring someone under the deleted leaver's identity
+		// number starts an employment of its own
+		otherToken := otherTenantToken(e)
+		otherDepartmentID := fmt.Sprint(e.POST("/api/v1/department").
+			WithHeader("Authorization", "Bearer "+otherToken).
+			WithJSON(map[string]interface{}{"name": "Turnover Department"}).
+			Expect().
+			Status(201).
+			JSON().Object().Value("departmentId").Raw())
+		e.POST("/api/v1/employee").
+			WithHeader("Authorization", "Bearer "+otherToken).
+			WithJSON(map[string]interface{}{
+				"identityNumber":   staff[1].identityNumber,
+				"name":             "Turnover Tester",
+				"employeeImageUri": "http://example.com/image.png",
+				"gender":           "male",
+				"departmentId":     otherDepartmentID,
+			}).
+			Expect().
+			Status(201)
+
+		report := e.GET("/api/v1/analytics/turnover").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithQuery("from", "2025-02-01").
+			WithQuery("to", "2025-03-31").
+			WithQuery("departmentId", departmentID).
+			Expect().
+			Status(200).
+			JSON().Object()
+
+		overall := report.Value("overall").Array()
+		overall.Value(0).Object().ContainsMap(map[string]interface{}{
+			"month": "2025-02", "startHeadcount": 4, "endHeadcount": 4, "leavers": 0, "turnoverRate": 0, "retentionRate": 100,
+		})
+		// 2 leavers against an average headcount of 3
+		overall.Value(1).Object().ContainsMap(map[string]interface{}{
+			"month":              "2025-03",
+			"startHeadcount":     4,
+			"endHeadcount":       2,
+			"hires":              0,
+			"leavers":            2,
+			"voluntaryLeavers":   1,
+			"involuntaryLeavers": 1,
+			"regrettedLeavers":   1,
+			"turnoverRate":       66.67,
+			"voluntaryRate":      33.33,
+			"involuntaryRate":    33.33,
+			"retentionRate":      50,
+			"averageTenureYears": 0.24, // 89 days each for the two who stayed
+		})
+
+		genders := report.Value("genders").Array()
+		genders.Value(0).Object().Value("gender").IsEqual("male")
+		genders.Value(0).Object().Value("months").Array().Value(1).Object().
+			ContainsMap(map[string]interface{}{"startHeadcount": 2, "endHeadcount": 0, "leavers": 2, "retentionRate": 0})
+		genders.Value(0).Object().Value("months").Array().Value(1).Object().Value("averageTenureYears").IsNull()
+		genders.Value(1).Object().Value("months").Array().Value(1).Object().
+			ContainsMap(map[string]interface{}{"startHeadcount": 2, "endHeadcount": 2, "leavers": 0, "retentionRate": 100})
+
+		report.Value("departments").Array().Length().IsEqual(1)
+
+		// The headcount report's flows keep the deleted leaver as well
+		e.GET("/api/v1/analytics/headcount").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithQuery("from", "2025-03-01").
+			WithQuery("to", "2025-03-31").
+			WithQuery("departmentId", departmentID).
+			Expect().
+			Status(200).
+			JSON().Object().Value("monthlyFlows").Array().Value(0).Object().
+			ContainsMap(map[string]interface{}{"month": "2025-03", "hires": 0, "leavers": 2})
+
+		for _, s := range []string{staff[0].identityNumber, staff[2].identityNumber, staff[3].identityNumber} {
+			e.DELETE("/api/v1/employee/{identityNumber}", s).
+				WithHeader("Authorization", "Bearer "+TOKEN).
+				Expect().
+				Status(200)
+		}
+		e.DELETE("/api/v1/employee/{identityNumber}", staff[1].identityNumber).
+			WithHeader("Authorization", "Bearer "+otherToken).
+			Expect().
+			Status(200)
+		e.DELETE("/api/v1/department/{departmentId}", otherDepartmentID).
+			WithHeader("Authorization", "Bearer "+otherToken).
+			Expect().
+			Status(200)
+		e.DELETE("/api/v1/department/{departmentId}", departmentID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200)
+	})
+}
+
+func TestReviewAPI(t *testing.T) {
+	e := httpexpect.New(t, PORT)
+
+	// Test POST /api/v1/review-template
+	t.Run("Create a review template", func(t *testing.T) {
+		template := map[string]interface{}{
+			"name":     fmt.Sprintf("Quarterly %d", time.Now().UnixNano()),
+			"scaleMin": 1,
+			"scaleMax": 5,
+			"questions": []map[string]interface{}{
+				{"key": "delivery", "text": "How well were goals delivered?", "type": "rating", "required": true},
+				{"key": "highlights", "text": "What went well?", "type": "text"},
+			},
+		}
+
+		e.POST("/api/v1/review-template").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(template).
+			Expect().
+			Status(201).
+			JSON().Object().ContainsKey("id")
+	})
+
+	t.Run("Reject duplicate question keys", func(t *testing.T) {
+		template := map[string]interface{}{
+			"name":     fmt.Sprintf("Broken %d", time.Now().UnixNano()),
+			"scaleMax": 5,
+			"questions": []map[string]interface{}{
+				{"key": "delivery", "text": "Delivery", "type": "rating"},
+				{"key": "delivery", "text": "Delivery again", "type": "rating"},
+			},
+		}
+
+		e.POST("/api/v1/review-template").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(template).
+			Expect().
+			Status(400)
+	})
+
+	// Test GET /api/v1/review-cycle
+	t.Run("Get review cycles", func(t *testing.T) {
+		e.GET("/api/v1/review-cycle").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array()
+	})
+
+	// The manager assessment is taken by the reviewer assigned at launch
+	t.Run("Assess a review as the assigned reviewer", func(t *testing.T) {
+		const HEAD_ID = "XX41001"
+		const MEMBER_ID = "XX41002"
+
+		departmentID := fmt.Sprint(e.POST("/api/v1/department").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"name": "Review Department"}).
+			Expect().
+			Status(201).
+			JSON().Object().Value("departmentId").Raw())
+		for _, identityNumber := range []string{HEAD_ID, MEMBER_ID} {
+			e.POST("/api/v1/employee").
+				WithHeader("Authorization", "Bearer "+TOKEN).
+				WithJSON(map[string]interface{}{
+					"identityNumber":   identityNumber,
+					"name":             "Review Tester",
+					"employeeImageUri": "http://example.com/image.png",
+					"gender":           "female",
+					"departmentId":     departmentID,
+				}).
+				Expect().
+				Status(201)
+		}
+		e.PUT("/api/v1/department/{departmentId}/head", departmentID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"identityNumber": HEAD_ID}).
+			Expect().
+			Status(200)
+
+		templateID := e.POST("/api/v1/review-template").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{
+				"name":      fmt.Sprintf("Reviewer %d", time.Now().UnixNano()),
+				"scaleMin":  1,
+				"scaleMax":  5,
+				"questions": []map[string]interface{}{{"key": "delivery", "text": "Delivery", "type": "rating", "required": true}},
+			}).
+			Expect().
+			Status(201).
+			JSON().Object().Value("id").Raw()
+		cycleID := e.POST("/api/v1/review-cycle").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{
+				"name":          fmt.Sprintf("Reviewer cycle %d", time.Now().UnixNano()),
+				"templateId":    templateID,
+				"period":        "quarterly",
+				"periodStart":   "2026-01-01",
+				"periodEnd":     "2026-03-31",
+				"selfDue":       "2026-04-15",
+				"managerDue":    "2026-04-30",
+				"departmentIds": []string{departmentID},
+			}).
+			Expect().
+			Status(201).
+			JSON().Object().Value("id").Raw()
+
+		var reviewID interface{}
+		reviews := e.GET("/api/v1/review-cycle/{cycleId}/review", cycleID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array()
+		for _, review := range reviews.Iter() {
+			if review.Object().Value("identityNumber").Raw() == MEMBER_ID {
+				review.Object().Value("reviewerIdentityNumber").IsEqual(HEAD_ID)
+				reviewID = review.Object().Value("id").Raw()
+			}
+		}
+		if reviewID == nil {
+			t.Fatalf("no review for %s", MEMBER_ID)
+		}
+
+		assessment := map[string]interface{}{"answers": map[string]interface{}{"delivery": 4}, "overallRating": 4}
+		e.POST("/api/v1/self/review/{reviewId}/manager", reviewID).
+			WithHeader("Authorization", "Bearer "+selfToken(e, MEMBER_ID)).
+			WithJSON(assessment).
+			Expect().
+			Status(403)
+		// The reviewer can no longer be named in the body
+		e.POST("/api/v1/review/{reviewId}/manager", reviewID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"answers": map[string]interface{}{"delivery": 3}, "overallRating": 3, "reviewerIdentityNumber": HEAD_ID}).
+			Expect().
+			Status(200).
+			JSON().Object().Value("managerSubmittedBy").NotEqual(HEAD_ID)
+		e.POST("/api/v1/self/review/{reviewId}/manager", reviewID).
+			WithHeader("Authorization", "Bearer "+selfToken(e, HEAD_ID)).
+			WithJSON(assessment).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ContainsMap(map[string]interface{}{"managerSubmittedBy": HEAD_ID, "overallRating": 4})
+
+		e.POST("/api/v1/review-cycle/{cycleId}/close", cycleID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200)
+		for _, identityNumber := range []string{HEAD_ID, MEMBER_ID} {
+			e.DELETE("/api/v1/employee/{identityNumber}", identityNumber).
+				WithHeader("Authorization", "Bearer "+TOKEN).
+				Expect().
+				Status(200)
+		}
+		e.DELETE("/api/v1/department/{departmentId}", departmentID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200)
+	})
+}
+
+func TestPositionAPI(t *testing.T) {
+	e := httpexpect.New(t, PORT)
+
+	// Test POST /api/v1/position
+	t.Run("Reject a position in an unknown department", func(t *testing.T) {
+		position := map[string]interface{}{
+			"departmentId": "999999999",
+			"jobTitle":     "Software Engineer",
+			"level":        "Senior",
+		}
+
+		e.POST("/api/v1/position").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(position).
+			Expect().
+			Status(400)
+	})
+
+	// Test GET /api/v1/position
+	t.Run("Get vacant positions", func(t *testing.T) {
+		e.GET("/api/v1/position").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithQuery("status", "vacant").
+			Expect().
+			Status(200).
+			JSON().Array()
+	})
+
+	t.Run("Reject an unknown position status", func(t *testing.T) {
+		e.GET("/api/v1/position").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithQuery("status", "frozen").
+			Expect().
+			Status(400)
+	})
+
+	// Test GET /api/v1/position/vacancy
+	t.Run("Get open vacancies", func(t *testing.T) {
+		e.GET("/api/v1/position/vacancy").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array()
+	})
+
+	// Test GET /api/v1/analytics/staffing
+	t.Run("Get planned and actual headcount", func(t *testing.T) {
+		e.GET("/api/v1/analytics/staffing").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array()
+	})
+
+	// Test PUT /api/v1/department/:departmentId/headcount-plan
+	t.Run("Reject a plan for an unknown department", func(t *testing.T) {
+		e.PUT("/api/v1/department/999999999/headcount-plan").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"planned": 10}).
+			Expect().
+			Status(404)
+	})
+}
+
+func TestRecruitingAPI(t *testing.T) {
+	e := httpexpect.New(t, PORT)
+
+	// Test POST /api/v1/job-opening
+	t.Run("Reject an opening in an unknown department", func(t *testing.T) {
+		opening := map[string]interface{}{
+			"departmentId": "999999999",
+			"title":        "Backend Engineer",
+		}
+
+		e.POST("/api/v1/job-opening").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(opening).
+			Expect().
+			Status(400)
+	})
+
+	// Test GET /api/v1/job-opening
+	t.Run("Get open job openings", func(t *testing.T) {
+		e.GET("/api/v1/job-opening").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithQuery("status", "open").
+			Expect().
+			Status(200).
+			JSON().Array()
+	})
+
+	t.Run("Reject an unknown opening status", func(t *testing.T) {
+		e.GET("/api/v1/job-opening").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithQuery("status", "paused").
+			Expect().
+			Status(400)
+	})
+
+	// Test POST /api/v1/candidate/:candidateId/hire
+	t.Run("Reject hiring an unknown candidate", func(t *testing.T) {
+		hire := map[string]interface{}{
+			"identityNumber":   fmt.Sprintf("%d", time.Now().UnixNano()),
+			"gender":           "female",
+			"employeeImageUri": "https://www.google.com/images/branding/googlelogo/2x/googlelogo_color_272x92dp.png",
+		}
+
+		e.POST("/api/v1/candidate/999999999/hire").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(hire).
+			Expect().
+			Status(404)
+	})
+}
+
+func TestChecklistAPI(t *testing.T) {
+	e := httpexpect.New(t, PORT)
+
+	// Test POST /api/v1/checklist-template
+	t.Run("Create a checklist template", func(t *testing.T) {
+		template := map[string]interface{}{
+			"name": fmt.Sprintf("Onboarding %d", time.Now().UnixNano()),
+			"kind": "onboarding",
+			"tasks": []map[string]interface{}{
+				{"title": "Prepare laptop", "assignee": "it", "dueOffsetDays": -3},
+				{"title": "Sign contract", "assignee": "hr", "dueOffsetDays": 0},
+			},
+		}
+
+		obj := e.POST("/api/v1/checklist-template").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(template).
+			Expect().
+			Status(201).
+			JSON().Object()
+		obj.Value("active").Boolean().IsTrue()
+	})
+
+	t.Run("Reject a template without tasks", func(t *testing.T) {
+		template := map[string]interface{}{
+			"name":  fmt.Sprintf("Empty %d", time.Now().UnixNano()),
+			"kind":  "offboarding",
+			"tasks": []map[string]interface{}{},
+		}
+
+		e.POST("/api/v1/checklist-template").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(template).
+			Expect().
+			Status(400)
+	})
+
+	// Test GET /api/v1/checklist
+	t.Run("Get open checklists", func(t *testing.T) {
+		e.GET("/api/v1/checklist").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithQuery("status", "open").
+			Expect().
+			Status(200).
+			JSON().Array()
+	})
+
+	t.Run("Reject an unknown checklist status", func(t *testing.T) {
+		e.GET("/api/v1/checklist").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithQuery("status", "archived").
+			Expect().
+			Status(400)
+	})
+
+	// Test GET /api/v1/checklist/overdue
+	t.Run("Get overdue tasks", func(t *testing.T) {
+		e.GET("/api/v1/checklist/overdue").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array()
+	})
+}
+
+func TestEmployeeAPI(t *testing.T) {
+	const EMPLOYEE_ID = "XX12345"
+
+	e := httpexpect.New(t, PORT)
+
+	getOrCreateDepartmentID := func() string {
+		// Step 1: Try to get the list of departments
+		departments := e.GET("/api/v1/department").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array()
+
+		// Step 2: If departments exist, return the first department's ID
+		if departments.Length().Raw() > 0 {
+			firstDepartment := departments.First().Object()
+			departmentID := firstDepartment.Value("departmentId").Raw()
+
+			// Handle the case where departmentId is a number or a string
+			switch v := departmentID.(type) {
+			case float64: // JSON numbers are unmarshalled as float64 by default
+				return fmt.Sprintf("%d", int(v)) // Convert to string
+			case int:
+				return fmt.Sprintf("%d", v) // Convert to string
+			case string:
+				return v // Already a string
+			default:
+				panic(fmt.Sprintf("unexpected type for departmentId: %T", v))
+			}
+		}
+
+		// Step 3: If no departments exist, create a new department
+		newDepartment := map[string]interface{}{
+			"name": "Default Department",
+		}
+
+		// Create the new department and get the response
+		createdDepartment := e.POST("/api/v1/department").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(newDepartment).
+			Expect().
+			Status(201).
+			JSON().Object()
+
+		// Step 4: Handle the case where departmentId is a number or a string
+		departmentID := createdDepartment.Value("departmentId").Raw()
+		switch v := departmentID.(type) {
+		case float64: // JSON numbers are unmarshalled as float64 by default
+			return fmt.Sprintf("%d", int(v)) // Convert to string
+		case int:
+			return fmt.Sprintf("%d", v) // Convert to string
+		case string:
+			return v // Already a string
+		default:
+			panic(fmt.Sprintf("unexpected type for departmentId: %T", v))
+		}
+	}
+
+	// Get or create a department ID
+	DEPARTMENT_ID := getOrCreateDepartmentID()
+
+	// Test POST /api/v1/emplyee
+	t.Run("Create a new employee", func(t *testing.T) {
+		employee := map[string]interface{}{
+			"identityNumber":   EMPLOYEE_ID,
+			"name":             "Bob Smith",
+			"employeeImageUri": "1234",
+			"gender":           "male",
+			"departmentId":     DEPARTMENT_ID,
+		}
+
+		e.POST("/api/v1/employee").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(employee).
+			Expect().
+			Status(201).
+			JSON().Object().
+			ContainsMap(employee)
+	})
+
+	t.Run("Get all employees", func(t *testing.T) {
+		e.GET("/api/v1/employee").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array().NotEmpty()
+	})
+
+	// Test GET /api/v1/employee/search
+	t.Run("Search employees", func(t *testing.T) {
+		e.GET("/api/v1/employee/search").
+			WithQuery("q", "b").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(400)
+
+		// A prefix of the last name
+		e.GET("/api/v1/employee/search").
+			WithQuery("q", "smit").
+			WithQuery("identityNumber", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array().
+			Value(0).Object().
+			ContainsMap(map[string]interface{}{"identityNumber": EMPLOYEE_ID}).
+			Value("highlights").Object().ContainsMap(map[string]interface{}{"name": "Bob <mark>Smith</mark>"})
+
+		// A prefix of the identity number
+		e.GET("/api/v1/employee/search").
+			WithQuery("q", EMPLOYEE_ID[:5]).
+			WithQuery("identityNumber", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array().Length().IsEqual(1)
+
+		e.GET("/api/v1/employee/search").
+			WithQuery("q", "qqzzxxvv").
+			WithQuery("identityNumber", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array().IsEmpty()
+
+		// The title of the position they hold, with a typo
+		positionID := e.POST("/api/v1/position").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{
+				"departmentId":   DEPARTMENT_ID,
+				"jobTitle":       "Zookeeper",
+				"identityNumber": EMPLOYEE_ID,
+			}).
+			Expect().
+			Status(201).
+			JSON().Object().
+			Value("id").Number().Raw()
+
+		e.GET("/api/v1/employee/search").
+			WithQuery("q", "zookeper").
+			WithQuery("identityNumber", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array().
+			Value(0).Object().
+			ContainsMap(map[string]interface{}{"identityNumber": EMPLOYEE_ID, "jobTitle": "Zookeeper"})
+
+		e.DELETE("/api/v1/position/{id}", int(positionID)).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200)
+	})
+
+	// Terms typed without accents are highlighted in the accented name
+	t.Run("Highlight accented names", func(t *testing.T) {
+		const ACCENTED_ID = "XX33001"
+
+		e.POST("/api/v1/employee").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{
+				"identityNumber":   ACCENTED_ID,
+				"name":             "José Álvarez",
+				"employeeImageUri": "http://example.com/image.png",
+				"gender":           "male",
+				"departmentId":     DEPARTMENT_ID,
+			}).
+			Expect().
+			Status(201)
+
+		for term, name := range map[string]string{
+			"jose":    "<mark>José</mark> Álvarez",
+			"alvarez": "José <mark>Álvarez</mark>",
+			"Álvarez": "José <mark>Álvarez</mark>",
+		} {
+			e.GET("/api/v1/employee/search").
+				WithQuery("q", term).
+				WithQuery("identityNumber", ACCENTED_ID).
+				WithHeader("Authorization", "Bearer "+TOKEN).
+				Expect().
+				Status(200).
+				JSON().Array().
+				Value(0).Object().
+				Value("highlights").Object().ContainsMap(map[string]interface{}{"name": name})
+		}
+
+		e.DELETE("/api/v1/employee/{id}", ACCENTED_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200)
+	})
+
+	// Test POST /api/v1/employee/batch
+	t.Run("Batch create, update and delete employees", func(t *testing.T) {
+		employee := map[string]interface{}{
+			"identityNumber":   "XX30001",
+			"name":             "Batch Employee",
+			"employeeImageUri": "1234",
+			"gender":           "female",
+			"departmentId":     DEPARTMENT_ID,
+		}
+		updated := map[string]interface{}{}
+		for key, value := range employee {
+			updated[key] = value
+		}
+		updated["name"] = "Updated Batch Employee"
+
+		obj := e.POST("/api/v1/employee/batch").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{
+				"operations": []map[string]interface{}{
+					{"op": "create", "employee": employee},
+					{"op": "update", "identityNumber": "XX30001", "employee": updated},
+					{"op": "delete", "identityNumber": "XX30001"},
+				},
+			}).
+			Expect().
+			Status(200).
+			JSON().Object()
+		obj.ContainsMap(map[string]interface{}{"mode": "atomic", "succeeded": 3, "failed": 0})
+	})
+
+	t.Run("Roll back an atomic batch", func(t *testing.T) {
+		employee := map[string]interface{}{
+			"identityNumber":   "XX30002",
+			"name":             "Batch Employee",
+			"employeeImageUri": "1234",
+			"gender":           "female",
+			"departmentId":     DEPARTMENT_ID,
+		}
+
+		results := e.POST("/api/v1/employee/batch").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{
+				"operations": []map[string]interface{}{
+					{"op": "create", "employee": employee},
+					{"op": "delete", "identityNumber": "UNKNOWN-EMPLOYEE"},
+				},
+			}).
+			Expect().
+			Status(422).
+			JSON().Object().
+			Value("results").Array()
+		results.Value(0).Object().ContainsMap(map[string]interface{}{"status": "rolled_back"})
+		results.Value(1).Object().ContainsMap(map[string]interface{}{"status": "failed", "code": 404})
+
+		e.GET("/api/v1/employee").
+			WithQuery("identityNumber", "XX30002").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array().IsEmpty()
+	})
+
+	t.Run("Reject batch operations across tenants", func(t *testing.T) {
+		otherToken := otherTenantToken(e)
+
+		otherDepartmentID := e.POST("/api/v1/department").
+			WithHeader("Authorization", "Bearer "+otherToken).
+			WithJSON(map[string]interface{}{"name": "Other Tenant Department"}).
+			Expect().
+			Status(201).
+			JSON().Object().
+			Value("departmentId").String().Raw()
+
+		employee := map[string]interface{}{
+			"identityNumber":   EMPLOYEE_ID,
+			"name":             "Hijacked Bob Smith",
+			"employeeImageUri": "1234",
+			"gender":           "male",
+			"departmentId":     otherDepartmentID,
+		}
+
+		// Another tenant can neither change nor delete the employee
+		results := e.POST("/api/v1/employee/batch").
+			WithHeader("Authorization", "Bearer "+otherToken).
+			WithJSON(map[string]interface{}{
+				"mode": "best-effort",
+				"operations": []map[string]interface{}{
+					{"op": "update", "identityNumber": EMPLOYEE_ID, "employee": employee},
+					{"op": "delete", "identityNumber": EMPLOYEE_ID},
+				},
+			}).
+			Expect().
+			Status(200).
+			JSON().Object().
+			Value("results").Array()
+		results.Value(0).Object().ContainsMap(map[string]interface{}{"status": "failed", "code": 404})
+		results.Value(1).Object().ContainsMap(map[string]interface{}{"status": "failed", "code": 404})
+
+		// Nor can the owner move the employee into the other tenant's department
+		e.POST("/api/v1/employee/batch").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{
+				"operations": []map[string]interface{}{
+					{"op": "update", "identityNumber": EMPLOYEE_ID, "employee": employee},
+				},
+			}).
+			Expect().
+			Status(422).
+			JSON().Object().
+			Value("results").Array().
+			Value(0).Object().ContainsMap(map[string]interface{}{"status": "failed", "code": 400})
+
+		e.PATCH("/api/v1/employee/{id}", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+otherToken).
+			WithJSON(employee).
+			Expect().
+			Status(404)
+
+		e.GET("/api/v1/employee").
+			WithQuery("identityNumber", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array().
+			Value(0).Object().ContainsMap(map[string]interface{}{"name": "Bob Smith", "departmentId": DEPARTMENT_ID})
+
+		e.DELETE("/api/v1/department/{id}", otherDepartmentID).
+			WithHeader("Authorization", "Bearer "+otherToken).
+			Expect().
+			Status(200)
+	})
+
+	// Test GET /api/v1/employee with sorting and cursors
+	t.Run("Paginate employees", func(t *testing.T) {
+		operations := []map[string]interface{}{}
+		for i, name := range []string{"Page Alpha", "Page Bravo", "Page Charlie"} {
+			operations = append(operations, map[string]interface{}{
+				"op": "create",
+				"employee": map[string]interface{}{
+					"identityNumber":   fmt.Sprintf("XX4000%d", i+1),
+					"name":             name,
+					"employeeImageUri": "1234",
+					"gender":           "female",
+					"departmentId":     DEPARTMENT_ID,
+				},
+			})
+		}
+		e.POST("/api/v1/employee/batch").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"operations": operations}).
+			Expect().
+			Status(200)
+
+		first := e.GET("/api/v1/employee").
+			WithQuery("identityNumber", "XX4000").
+			WithQuery("sort", "-name").
+			WithQuery("limit", 2).
+			WithQuery("withTotal", "true").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200)
+		first.Header("X-Total-Count").IsEqual("3")
+		first.Header("Link").Contains(`rel="next"`)
+		names := first.JSON().Array()
+		names.Length().IsEqual(2)
+		names.Value(0).Object().Value("name").IsEqual("Page Charlie")
+		names.Value(1).Object().Value("name").IsEqual("Page Bravo")
+
+		envelope := e.GET("/api/v1/employee").
+			WithQuery("identityNumber", "XX4000").
+			WithQuery("sort", "-name").
+			WithQuery("limit", 2).
+			WithQuery("envelope", "true").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Object()
+		envelope.Value("prev").IsNull()
+		next := envelope.Value("next").String().Raw()
+
+		// The cursor carries the sort, so the next page only needs the cursor
+		second := e.GET("/api/v1/employee").
+			WithQuery("identityNumber", "XX4000").
+			WithQuery("cursor", next).
+			WithQuery("limit", 2).
+			WithQuery("envelope", "true").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Object()
+		second.Value("data").Array().Length().IsEqual(1)
+		second.Value("data").Array().Value(0).Object().Value("name").IsEqual("Page Alpha")
+		second.Value("next").IsNull()
+		second.Value("prev").String().NotEmpty()
+
+		e.GET("/api/v1/employee").
+			WithQuery("identityNumber", "XX4000").
+			WithQuery("sort", "name").
+			WithQuery("limit", 1).
+			WithQuery("offset", 1).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array().
+			Value(0).Object().Value("name").IsEqual("Page Bravo")
+
+		// Limits above the maximum are clamped rather than rejected
+		e.GET("/api/v1/employee").
+			WithQuery("identityNumber", "XX4000").
+			WithQuery("limit", 100000).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array().Length().IsEqual(3)
+
+		e.GET("/api/v1/employee").
+			WithQuery("sort", "gender").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(400)
+
+		e.GET("/api/v1/employee").
+			WithQuery("cursor", "not-a-cursor").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(400)
+
+		e.GET("/api/v1/employee").
+			WithQuery("cursor", next).
+			WithQuery("sort", "name").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(400)
+
+		for i := range operations {
+			operations[i] = map[string]interface{}{"op": "delete", "identityNumber": fmt.Sprintf("XX4000%d", i+1)}
+		}
+		e.POST("/api/v1/employee/batch").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"operations": operations}).
+			Expect().
+			Status(200)
+	})
+
+	// Test GET /api/v1/employee/export
+	t.Run("Export employees", func(t *testing.T) {
+		csvExport := e.GET("/api/v1/employee/export").
+			WithQuery("format", "csv").
+			WithQuery("identityNumber", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200)
+		csvExport.Header("Content-Type").IsEqual("text/csv")
+		csvExport.Header("Content-Disposition").Contains(`filename="employees-`)
+
+		records, err := csv.NewReader(strings.NewReader(csvExport.Body().Raw())).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 2 || records[0][0] != "identityNumber" || records[1][0] != EMPLOYEE_ID {
+			t.Fatalf("unexpected CSV export: %v", records)
+		}
+
+		body := e.GET("/api/v1/employee/export").
+			WithQuery("format", "xlsx").
+			WithQuery("identityNumber", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			Body().Raw()
+
+		workbook, err := excelize.OpenReader(strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer workbook.Close()
+		rows, err := workbook.GetRows(workbook.GetSheetName(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != 2 || rows[0][0] != "identityNumber" || rows[1][0] != EMPLOYEE_ID {
+			t.Fatalf("unexpected XLSX export: %v", rows)
+		}
+
+		ndjson := e.GET("/api/v1/employee/export").
+			WithQuery("format", "ndjson").
+			WithQuery("identityNumber", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			Body().Raw()
+
+		var row map[string]interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(ndjson)), &row); err != nil {
+			t.Fatal(err)
+		}
+		if row["identityNumber"] != EMPLOYEE_ID {
+			t.Fatalf("unexpected NDJSON export: %s", ndjson)
+		}
+
+		e.GET("/api/v1/employee/export").
+			WithQuery("format", "pdf").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(400)
+	})
+
+	// Test GET /api/v1/department/export
+	t.Run("Export departments", func(t *testing.T) {
+		body := e.GET("/api/v1/department/export").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			Body().Raw()
+
+		records, err := csv.NewReader(strings.NewReader(body)).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		headcount := ""
+		for _, record := range records[1:] {
+			if record[0] == DEPARTMENT_ID {
+				headcount = record[2]
+			}
+		}
+		if headcount == "" || headcount == "0" {
+			t.Fatalf("expected the employee in the headcount of department %s: %v", DEPARTMENT_ID, records)
+		}
+	})
+
+	// Test POST /api/v1/employee/import
+	t.Run("Import employees", func(t *testing.T) {
+		valid := "identity_number,name,gender,department_id,employee_image_uri\n" +
+			"XX20001,Alice Import,female," + DEPARTMENT_ID + ",1234\n" +
+			"XX20002,Carol Import,female," + DEPARTMENT_ID + ",1234\n"
+		invalid := valid + "XX20003,Dave Import,unknown," + DEPARTMENT_ID + ",1234\n"
+
+		report := e.POST("/api/v1/employee/import").
+			WithQuery("mode", "dry-run").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithMultipart().
+			WithFileBytes("file", "employees.csv", []byte(invalid)).
+			Expect().
+			Status(200).
+			JSON().Object()
+		report.ContainsMap(map[string]interface{}{"totalRows": 3, "validRows": 2, "invalidRows": 1, "imported": 0})
+		report.Value("errors").Array().Value(0).Object().ContainsMap(map[string]interface{}{"row": 4, "identityNumber": "XX20003"})
+
+		// Commit mode is all or nothing, so the valid rows are not imported either
+		e.POST("/api/v1/employee/import").
+			WithQuery("mode", "commit").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithMultipart().
+			WithFileBytes("file", "employees.csv", []byte(invalid)).
+			Expect().
+			Status(422)
+
+		e.GET("/api/v1/employee").
+			WithQuery("identityNumber", "XX2000").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array().IsEmpty()
+
+		// Imported hires start their onboarding like any other hire
+		onboarding := fmt.Sprintf("Import onboarding %d", time.Now().UnixNano())
+		e.POST("/api/v1/checklist-template").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{
+				"name":  onboarding,
+				"kind":  "onboarding",
+				"tasks": []map[string]interface{}{{"title": "Prepare laptop", "assignee": "it", "dueOffsetDays": 0}},
+			}).
+			Expect().
+			Status(201)
+
+		e.POST("/api/v1/employee/import").
+			WithQuery("mode", "commit").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithMultipart().
+			WithFileBytes("file", "employees.csv", []byte(valid)).
+			Expect().
+			Status(201).
+			JSON().Object().
+			ContainsMap(map[string]interface{}{"validRows": 2, "imported": 2})
+
+		e.GET("/api/v1/employee").
+			WithQuery("identityNumber", "XX2000").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array().Length().IsEqual(2)
+
+		for _, identityNumber := range []string{"XX20001", "XX20002"} {
+			started := false
+			checklists := e.GET("/api/v1/checklist").
+				WithQuery("identityNumber", identityNumber).
+				WithQuery("kind", "onboarding").
+				WithHeader("Authorization", "Bearer "+TOKEN).
+				Expect().
+				Status(200).
+				JSON().Array()
+			for _, checklist := range checklists.Iter() {
+				if checklist.Object().Value("name").String().Raw() == onboarding {
+					started = true
+				}
+			}
+			if !started {
+				t.Errorf("expected the %q checklist for %s", onboarding, identityNumber)
+			}
+		}
+
+		// Importing the same file again conflicts with the employees it created
+		e.POST("/api/v1/employee/import").
+			WithQuery("mode", "commit").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithMultipart().
+			WithFileBytes("file", "employees.csv", []byte(valid)).
+			Expect().
+			Status(422).
+			JSON().Object().
+			ContainsMap(map[string]interface{}{"validRows": 0, "invalidRows": 2})
+
+		for _, identityNumber := range []string{"XX20001", "XX20002"} {
+			e.DELETE("/api/v1/employee/{id}", identityNumber).
+				WithHeader("Authorization", "Bearer "+TOKEN).
+				Expect().
+				Status(200)
+		}
+	})
+
+	t.Run("Reject an import with an unknown column alias", func(t *testing.T) {
+		e.POST("/api/v1/employee/import").
+			WithQuery("mode", "dry-run").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithMultipart().
+			WithFileBytes("file", "employees.csv", []byte("identity_number,name,gender,department_code,employee_image_uri\n"+
+				"XX20004,Erin Import,female,IT,1234\n")).
+			Expect().
+			Status(400)
+	})
+
+	// Test PATCH /api/v1/employee/{id}
+	t.Run("Update a employee", func(t *testing.T) {
+		updatedEmployee := map[string]interface{}{
+			"identityNumber":   EMPLOYEE_ID,
+			"name":             "Updated Bob Smith",
+			"employeeImageUri": "1234",
+			"gender":           "male",
+			"departmentId":     DEPARTMENT_ID,
+		}
+
+		e.PATCH("/api/v1/employee/{id}", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(updatedEmployee).
+			Expect().
+			Status(200).
+			JSON().Object().ContainsMap(updatedEmployee)
+	})
+
+	// Test PATCH /api/v1/employee/{id} with a merge patch
+	t.Run("Partially update a employee", func(t *testing.T) {
+		e.PATCH("/api/v1/employee/{id}", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithHeader("Content-Type", "application/merge-patch+json").
+			WithBytes([]byte(`{"name": "Patched Bob Smith"}`)).
+			Expect().
+			Status(200).
+			JSON().Object().
+			ContainsMap(map[string]interface{}{
+				"identityNumber": EMPLOYEE_ID,
+				"name":           "Patched Bob Smith",
+				"departmentId":   DEPARTMENT_ID,
+			})
+	})
+
+	// Test POST /api/v1/employee/{id}/transfer
+	t.Run("Transfer a employee", func(t *testing.T) {
+		today := time.Now().Format(time.DateOnly)
+
+		transferDepartmentID := e.POST("/api/v1/department").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"name": "Transfer Department"}).
+			Expect().
+			Status(201).
+			JSON().Object().
+			Value("departmentId").String().Raw()
+
+		e.POST("/api/v1/employee/{id}/transfer", "UNKNOWN-EMPLOYEE").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"departmentId": transferDepartmentID, "effectiveDate": today, "reason": "Reorganisation"}).
+			Expect().
+			Status(404)
+
+		e.POST("/api/v1/employee/{id}/transfer", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"departmentId": DEPARTMENT_ID, "effectiveDate": today, "reason": "Reorganisation"}).
+			Expect().
+			Status(409)
+
+		e.POST("/api/v1/employee/{id}/transfer", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"departmentId": transferDepartmentID, "effectiveDate": today, "reason": "Reorganisation"}).
+			Expect().
+			Status(201).
+			JSON().Object().
+			ContainsMap(map[string]interface{}{
+				"identityNumber":   EMPLOYEE_ID,
+				"fromDepartmentId": DEPARTMENT_ID,
+				"toDepartmentId":   transferDepartmentID,
+			})
+
+		flow := e.GET("/api/v1/department/{id}/flows", transferDepartmentID).
+			WithQuery("from", today).
+			WithQuery("to", today).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Object()
+		flow.Value("inflow").Number().IsEqual(1)
+		flow.Value("outflow").Number().IsEqual(0)
+		flow.Value("net").Number().IsEqual(1)
+
+		// Move them back so the department can be deleted
+		e.POST("/api/v1/employee/{id}/transfer", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{"departmentId": DEPARTMENT_ID, "effectiveDate": today, "reason": "Reorganisation reverted"}).
+			Expect().
+			Status(201)
+
+		e.DELETE("/api/v1/department/{id}", transferDepartmentID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200)
+
+		// The transfers stay on the timeline after the department is deleted
+		timeline := e.GET("/api/v1/employee/{id}/timeline", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array()
+		timeline.Length().IsEqual(3)
+		timeline.Value(0).Object().ContainsMap(map[string]interface{}{"type": "status", "toStatus": "active"})
+		timeline.Value(1).Object().
+			ContainsMap(map[string]interface{}{"type": "transfer", "fromDepartmentId": DEPARTMENT_ID}).
+			NotContainsKey("toDepartmentId")
+		timeline.Value(2).Object().
+			ContainsMap(map[string]interface{}{"type": "transfer", "toDepartmentId": DEPARTMENT_ID}).
+			NotContainsKey("fromDepartmentId")
+
+		e.GET("/api/v1/employee/{id}/timeline", "UNKNOWN-EMPLOYEE").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(404)
+	})
+
+	// Test POST /api/v1/employee/{id}/status
+	t.Run("Terminate a employee", func(t *testing.T) {
+		transition := map[string]interface{}{
+			"status":        "terminated",
+			"effectiveDate": time.Now().Format(time.DateOnly),
+			"reason":        "Resigned",
+		}
+
+		e.POST("/api/v1/employee/{id}/status", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(transition).
+			Expect().
+			Status(201).
+			JSON().Object().
+			ContainsMap(map[string]interface{}{
+				"fromStatus": "active",
+				"toStatus":   "terminated",
+			})
+
+		e.GET("/api/v1/employee").
+			WithQuery("status", "former").
+			WithQuery("identityNumber", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array().NotEmpty()
+	})
+
+	// Test GET /api/v1/employee/{id}/status
+	t.Run("Get the status history", func(t *testing.T) {
+		history := e.GET("/api/v1/employee/{id}/status", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array()
+		history.Last().Object().ContainsMap(map[string]interface{}{"fromStatus": "active", "toStatus": "terminated"})
+
+		// Another tenant cannot read it
+		e.GET("/api/v1/employee/{id}/status", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+otherTenantToken(e)).
+			Expect().
+			Status(404)
+	})
+
+	// Test PATCH /api/v1/employee/{id} with a new identity number
+	t.Run("Change an identity number", func(t *testing.T) {
+		e.PATCH("/api/v1/employee/{id}", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithHeader("Content-Type", "application/merge-patch+json").
+			WithBytes([]byte(`{"identityNumber": "XX99999"}`)).
+			Expect().
+			Status(400)
+	})
+
+	// Test DELETE /api/v1/employee/{id}
+	t.Run("Delete a employee", func(t *testing.T) {
+		// Leave a full second between the last version and the deletion for the asOf test
+		time.Sleep(2 * time.Second)
+
+		e.DELETE("/api/v1/employee/{id}", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200)
+	})
+
+	// Test GET /api/v1/employee?asOf= for a deleted employee
+	t.Run("Get a deleted employee as of before the deletion", func(t *testing.T) {
+		versions := e.GET("/api/v1/employee/{id}/history", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array()
+
+		last := versions.Last().Object()
+		last.Value("validTo").String().NotEmpty()
+		validFrom, err := time.Parse(time.RFC3339, last.Value("validFrom").String().Raw())
+		if err != nil {
+			t.Fatal(err)
+		}
+		asOf := validFrom.Add(time.Second).Format(time.RFC3339)
+
+		// The status history survives the deletion, so they are still former staff
+		e.GET("/api/v1/employee").
+			WithQuery("asOf", asOf).
+			WithQuery("status", "former").
+			WithQuery("identityNumber", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array().Length().IsEqual(1)
+
+		e.GET("/api/v1/employee").
+			WithQuery("asOf", asOf).
+			WithQuery("status", "onboarding").
+			WithQuery("identityNumber", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array().IsEmpty()
+
+		// After the deletion they are gone
+		e.GET("/api/v1/employee").
+			WithQuery("status", "all").
+			WithQuery("identityNumber", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array().IsEmpty()
+	})
+
+	// Adding a deleted employee again starts a new employment
+	t.Run("Rehire under the same identity number", func(t *testing.T) {
+		e.POST("/api/v1/employee").
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			WithJSON(map[string]interface{}{
+				"identityNumber":   EMPLOYEE_ID,
+				"name":             "Rehired Bob Smith",
+				"employeeImageUri": "http://example.com/image.png",
+				"gender":           "male",
+				"departmentId":     DEPARTMENT_ID,
+			}).
+			Expect().
+			Status(201)
+
+		// The termination of the earlier employment is not theirs
+		history := e.GET("/api/v1/employee/{id}/status", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array()
+		history.Length().IsEqual(1)
+		history.Value(0).Object().Value("toStatus").IsEqual("active")
+
+		// Nor are the transfers of the earlier employment
+		timeline := e.GET("/api/v1/employee/{id}/timeline", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array()
+		timeline.Length().IsEqual(1)
+		timeline.Value(0).Object().ContainsMap(map[string]interface{}{"type": "status", "toStatus": "active"})
+
+		e.GET("/api/v1/employee").
+			WithQuery("status", "current").
+			WithQuery("identityNumber", EMPLOYEE_ID).
+			WithHeader("Authorization", "Bearer "+TOKEN).
+			Expect().
+			Status(200).
+			JSON().Array().Length().IsEqual(1)
+
 		e.DELETE("/api/v1/employee/{id}", EMPLOYEE_ID).
 			WithHeader("Authorization", "Bearer "+TOKEN).
 			Expect().
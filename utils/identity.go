@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// IdentityHolder carries the submitted employee fields a scheme may
+// cross-check against data encoded in the identity number.
+type IdentityHolder struct {
+	Gender string // "male" or "female"
+}
+
+// IdentityCheck reports why number is not a valid identity number of its
+// scheme for holder.
+type IdentityCheck func(number string, holder IdentityHolder) error
+
+// IdentitySchemeNone is the scheme of tenants that have not picked one; it
+// accepts any identity number.
+const IdentitySchemeNone = "none"
+
+var identitySchemes = map[string]IdentityCheck{}
+
+// RegisterIdentityScheme makes a national identity number scheme available
+// for tenants to pick. Schemes are registered at startup, before the server
+// handles requests.
+func RegisterIdentityScheme(name string, check IdentityCheck) {
+	identitySchemes[name] = check
+}
+
+// IdentitySchemes lists the registered schemes, including none.
+func IdentitySchemes() []string {
+	names := []string{IdentitySchemeNone}
+	for name := range identitySchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+func IsIdentityScheme(name string) bool {
+	if name == IdentitySchemeNone {
+		return true
+	}
+	_, ok := identitySchemes[name]
+	return ok
+}
+
+// CheckIdentityNumber validates number against scheme. Unknown schemes are an
+// error rather than a pass, so a scheme that is no longer registered does not
+// silently stop validating.
+func CheckIdentityNumber(scheme string, number string, holder IdentityHolder) error {
+	if scheme == IdentitySchemeNone || scheme == "" {
+		return nil
+	}
+
+	check, ok := identitySchemes[scheme]
+	if !ok {
+		return fmt.Errorf("identity scheme %q is not supported", scheme)
+	}
+	return check(number, holder)
+}
+
+// nikProvinces are the province codes in use, which open every NIK.
+var nikProvinces = map[string]bool{
+	"11": true, "12": true, "13": true, "14": true, "15": true, "16": true, "17": true, "18": true, "19": true,
+	"21": true,
+	"31": true, "32": true, "33": true, "34": true, "35": true, "36": true,
+	"51": true, "52": true, "53": true,
+	"61": true, "62": true, "63": true, "64": true, "65": true,
+	"71": true, "72": true, "73": true, "74": true, "75": true, "76": true,
+	"81": true, "82": true,
+	"91": true, "92": true, "93": true, "94": true, "95": true, "96": true, "97": true,
+}
+
+// CheckNIK validates an Indonesian Nomor Induk Kependudukan: 16 digits made of
+// a 6-digit region code (province, regency, district), the birth date as
+// DDMMYY with 40 added to the day for women, and a 4-digit serial number.
+func CheckNIK(number string, holder IdentityHolder) error {
+	if len(number) != 16 {
+		return errors.New("NIK must be 16 digits")
+	}
+	for _, r := range number {
+		if r < '0' || r > '9' {
+			return errors.New("NIK must be 16 digits")
+		}
+	}
+
+	if !nikProvinces[number[0:2]] {
+		return fmt.Errorf("NIK province code %s does not exist", number[0:2])
+	}
+	if number[2:4] == "00" || number[4:6] == "00" {
+		return errors.New("NIK regency and district codes cannot be 00")
+	}
+
+	day, _ := strconv.Atoi(number[6:8])
+	female := day > 40
+	if female {
+		day -= 40
+	}
+	month, _ := strconv.Atoi(number[8:10])
+	year, _ := strconv.Atoi(number[10:12])
+
+	// The century is not encoded, so 29 February passes whenever the two-digit
+	// year is divisible by four
+	birthDate := time.Date(2000+year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if birthDate.Day() != day || int(birthDate.Month()) != month {
+		return errors.New("NIK does not encode a valid birth date")
+	}
+
+	if number[12:16] == "0000" {
+		return errors.New("NIK serial number cannot be 0000")
+	}
+
+	switch {
+	case holder.Gender == "female" && !female:
+		return errors.New("NIK encodes a male birth date but gender is female")
+	case holder.Gender == "male" && female:
+		return errors.New("NIK encodes a female birth date but gender is male")
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Cursor marks a position in a sorted list. Clients receive it as an opaque
+// token, and it is only valid with the sort it was created for.
+type Cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	Key   string `json:"k"`
+	Prev  bool   `json:"p,omitempty"`
+}
+
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(token string) (Cursor, error) {
+	var cursor Cursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, errors.New("invalid cursor")
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, errors.New("invalid cursor")
+	}
+	return cursor, nil
+}
+
+// SortColumn is a sortable field: the SQL expression to order by and the SQL
+// type its cursor value is cast back to.
+type SortColumn struct {
+	Expr string
+	Type string
+}
+
+// Page describes the requested slice of a list. Sort is a field name with an
+// optional "-" prefix for descending order. When Cursor is set, Offset is ignored.
+type Page struct {
+	Sort      string
+	Limit     int
+	Offset    int
+	Cursor    *Cursor
+	WithTotal bool
+}
+
+func (p Page) Field() string {
+	return strings.TrimPrefix(p.Sort, "-")
+}
+
+func (p Page) Desc() bool {
+	return strings.HasPrefix(p.Sort, "-")
+}
+
+// PageInfo holds the cursors for the neighbouring pages, empty when there is
+// none, and the total number of matches when it was requested.
+type PageInfo struct {
+	Next  string
+	Prev  string
+	Total *int
+}
+
+// Keyset builds keyset pagination clauses for a set of whitelisted sort
+// columns. Key must be unique so rows with equal sort values keep a stable order.
+type Keyset struct {
+	Columns map[string]SortColumn
+	Key     SortColumn
+}
+
+// Clause returns the condition that starts the page after the cursor (or true
+// when there is none) and the ORDER BY list. arg registers a query argument
+// and returns its placeholder.
+func (k Keyset) Clause(p Page, arg func(interface{}) string) (string, string, error) {
+	column, ok := k.Columns[p.Field()]
+	if !ok {
+		return "", "", fmt.Errorf("cannot sort by %s", p.Field())
+	}
+
+	// Walking backwards flips the direction; the rows are reversed afterwards
+	ascending := !p.Desc()
+	if p.Cursor != nil && p.Cursor.Prev {
+		ascending = !ascending
+	}
+
+	direction, comparison := "ASC", ">"
+	if !ascending {
+		direction, comparison = "DESC", "<"
+	}
+	order := fmt.Sprintf("%s %s, %s %s", column.Expr, direction, k.Key.Expr, direction)
+
+	if p.Cursor == nil {
+		return "TRUE", order, nil
+	}
+	if p.Cursor.Sort != p.Sort {
+		return "", "", errors.New("cursor does not match the requested sort")
+	}
+
+	condition := fmt.Sprintf("(%s, %s) %s (%s::%s, %s::%s)",
+		column.Expr, k.Key.Expr, comparison,
+		arg(p.Cursor.Value), column.Type,
+		arg(p.Cursor.Key), k.Key.Type,
+	)
+	return condition, order, nil
+}
+
+// Info works out the neighbouring cursors from a page that was fetched with
+// Limit+1 rows. first and last are the sort value and key of the first and
+// last rows kept, after the rows of a backwards page have been put back in order.
+func (p Page) Info(fetched int, first [2]string, last [2]string) PageInfo {
+	var info PageInfo
+	if fetched == 0 {
+		return info
+	}
+
+	hasMore := fetched > p.Limit
+	backwards := p.Cursor != nil && p.Cursor.Prev
+
+	if (!backwards && hasMore) || backwards {
+		info.Next = Cursor{Sort: p.Sort, Value: last[0], Key: last[1]}.Encode()
+	}
+	if (backwards && hasMore) || (!backwards && (p.Cursor != nil || p.Offset > 0)) {
+		info.Prev = Cursor{Sort: p.Sort, Value: first[0], Key: first[1], Prev: true}.Encode()
+	}
+	return info
+}
+
+func (k Keyset) Allows(field string) bool {
+	_, ok := k.Columns[field]
+	return ok
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"errors"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -10,18 +11,42 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Claims identify a manager account. UserID is the tenant the account works
+// in, which is the account itself for the tenant's owner.
 type Claims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
+	UserID    uint   `json:"user_id"`
+	AccountID uint   `json:"account_id,omitempty"`
+	Email     string `json:"email"`
 	jwt.RegisteredClaims
 }
 
+// Account returns the signed-in account. Tokens issued before accounts could
+// join a tenant only carry the tenant, which was then always the account.
+func (c *Claims) Account() uint {
+	if c.AccountID == 0 {
+		return c.UserID
+	}
+	return c.AccountID
+}
+
+// EmployeeClaims identify an employee's self-service account. Their tokens
+// carry the employee audience, which ValidateJWT rejects, so they never open
+// the manager endpoints.
+type EmployeeClaims struct {
+	AccountID uint   `json:"account_id"`
+	Email     string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+const EmployeeAudience = "employee"
+
 var JWTSecret = []byte(os.Getenv("JWT_SECRET")) // need to update
 
-func GenerateJWT(userId uint, email string) (string, error) {
+func GenerateJWT(userId uint, accountId uint, email string) (string, error) {
 	claims := Claims{
-		UserID: userId,
-		Email:  email,
+		UserID:    userId,
+		AccountID: accountId,
+		Email:     email,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)), // Token expiration
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -45,6 +70,41 @@ func ValidateJWT(tokenString string) (*Claims, error) {
 	}
 
 	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid || slices.Contains(claims.Audience, EmployeeAudience) {
+		return nil, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
+
+func GenerateEmployeeJWT(accountID uint, email string) (string, error) {
+	claims := EmployeeClaims{
+		AccountID: accountID,
+		Email:     email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{EmployeeAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(JWTSecret)
+}
+
+func ValidateEmployeeJWT(tokenString string) (*EmployeeClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &EmployeeClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return JWTSecret, nil
+	}, jwt.WithAudience(EmployeeAudience))
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*EmployeeClaims)
 	if !ok || !token.Valid {
 		return nil, errors.New("invalid token")
 	}